@@ -0,0 +1,42 @@
+package config
+
+import (
+	"encoding/json"
+	"handworks-api/types"
+	"os"
+)
+
+// NewDepositPolicy returns the deposit rules checked before a crew is dispatched: 30% of
+// post-construction and general cleaning jobs of ₱20,000 or more. Set DEPOSIT_POLICY to a JSON
+// policy, e.g. {"rules":[{"serviceTypes":["POST"],"minTotal":"15000","fixed":"5000"}]}, to
+// override it; invalid JSON or an out of range rule keeps the default.
+func NewDepositPolicy() types.DepositPolicy {
+	policy := types.DepositPolicy{
+		Rules: []types.DepositRule{
+			{
+				ServiceTypes: []types.MainServiceType{types.PostCleaning, types.GeneralCleaning},
+				MinTotal:     types.PHP(2000000),
+				Percent:      30,
+			},
+		},
+	}
+	if raw := os.Getenv("DEPOSIT_POLICY"); raw != "" {
+		var custom types.DepositPolicy
+		if err := json.Unmarshal([]byte(raw), &custom); err == nil && validDepositRules(custom.Rules) {
+			policy = custom
+		}
+	}
+	return policy
+}
+
+func validDepositRules(rules []types.DepositRule) bool {
+	for _, r := range rules {
+		if r.Percent < 0 || r.Percent > 100 || r.Fixed.IsNegative() || r.MinTotal.IsNegative() {
+			return false
+		}
+		if types.CheckCurrencies(r.Fixed, r.MinTotal) != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+// NewReorderCheckInterval returns how often stock is checked against reorder points.
+// Set REORDER_CHECK_INTERVAL to a Go duration (e.g. "5m") to override the 15 minute default.
+func NewReorderCheckInterval() time.Duration {
+	return durationFromEnv("REORDER_CHECK_INTERVAL", 15*time.Minute)
+}
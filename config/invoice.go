@@ -0,0 +1,26 @@
+package config
+
+import (
+	"handworks-api/types"
+	"os"
+	"strings"
+	"time"
+)
+
+// NewInvoicePolicy returns the series invoices and credit notes are numbered in and the invoice
+// payment term. Set INVOICE_SERIES (default "INV") or CREDIT_NOTE_SERIES (default "CN") to start a
+// new series, and INVOICE_DUE to a Go duration to override the 7 day term.
+func NewInvoicePolicy() types.InvoicePolicy {
+	return types.InvoicePolicy{
+		Series:           seriesFromEnv("INVOICE_SERIES", "INV"),
+		CreditNoteSeries: seriesFromEnv("CREDIT_NOTE_SERIES", "CN"),
+		DueIn:            durationFromEnv("INVOICE_DUE", 7*24*time.Hour),
+	}
+}
+
+func seriesFromEnv(key, def string) string {
+	if series := strings.ToUpper(strings.TrimSpace(os.Getenv(key))); series != "" {
+		return series
+	}
+	return def
+}
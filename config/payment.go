@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// NewWebhookSecret returns the key payment provider webhooks are signed with, from
+// PAYMENT_WEBHOOK_SECRET. Without it every webhook is rejected.
+func NewWebhookSecret() []byte {
+	return []byte(os.Getenv("PAYMENT_WEBHOOK_SECRET"))
+}
+
+// NewPaymentReconcileInterval returns how often payments and refunds left PENDING are reconciled
+// with the provider. Set PAYMENT_RECONCILE_INTERVAL to a Go duration to override the 5 minute default.
+func NewPaymentReconcileInterval() time.Duration {
+	return durationFromEnv("PAYMENT_RECONCILE_INTERVAL", 5*time.Minute)
+}
+
+// NewPendingPaymentTimeout returns how long a payment or refund may stay PENDING before it is reconciled.
+// Set PAYMENT_PENDING_TIMEOUT to a Go duration to override the 15 minute default.
+func NewPendingPaymentTimeout() time.Duration {
+	return durationFromEnv("PAYMENT_PENDING_TIMEOUT", 15*time.Minute)
+}
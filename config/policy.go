@@ -0,0 +1,25 @@
+package config
+
+import (
+	"encoding/json"
+	"handworks-api/types"
+	"os"
+)
+
+// NewCancellationPolicy returns the refund tiers used when cancelling bookings.
+// Set CANCELLATION_POLICY to a JSON policy to override the defaults; invalid JSON keeps them.
+func NewCancellationPolicy() types.CancellationPolicy {
+	policy := types.CancellationPolicy{
+		Tiers: []types.CancellationTier{
+			{MinHoursBefore: 48, RefundPercent: 100},
+			{MinHoursBefore: 0, RefundPercent: 50},
+		},
+	}
+	if raw := os.Getenv("CANCELLATION_POLICY"); raw != "" {
+		var custom types.CancellationPolicy
+		if err := json.Unmarshal([]byte(raw), &custom); err == nil && len(custom.Tiers) > 0 {
+			policy = custom
+		}
+	}
+	return policy
+}
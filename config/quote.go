@@ -0,0 +1,28 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// NewQuoteTTL returns how long a quote can be booked after it is made.
+// Set QUOTE_TTL to a Go duration (e.g. "72h") to override the 7 day default.
+func NewQuoteTTL() time.Duration {
+	return durationFromEnv("QUOTE_TTL", 7*24*time.Hour)
+}
+
+// NewQuoteSweepInterval returns how often expired quotes are invalidated.
+// Set QUOTE_SWEEP_INTERVAL to a Go duration to override the hourly default.
+func NewQuoteSweepInterval() time.Duration {
+	return durationFromEnv("QUOTE_SWEEP_INTERVAL", time.Hour)
+}
+
+// durationFromEnv reads a positive Go duration from the environment, falling back to def.
+func durationFromEnv(key string, def time.Duration) time.Duration {
+	if raw := os.Getenv(key); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			return d
+		}
+	}
+	return def
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"encoding/json"
+	"handworks-api/types"
+	"os"
+)
+
+// NewTaxPolicy returns the VAT applied to quotes: 12% with catalog prices including it.
+// Set TAX_POLICY to a JSON policy, e.g. {"rateBasisPoints":1200,"pricesIncludeTax":false},
+// to override it; invalid JSON or a negative rate keeps the default.
+func NewTaxPolicy() types.TaxPolicy {
+	policy := types.TaxPolicy{RateBasisPoints: 1200, PricesIncludeTax: true}
+	if raw := os.Getenv("TAX_POLICY"); raw != "" {
+		var custom types.TaxPolicy
+		if err := json.Unmarshal([]byte(raw), &custom); err == nil && custom.RateBasisPoints >= 0 {
+			policy = custom
+		}
+	}
+	return policy
+}
@@ -157,6 +157,64 @@ const docTemplate = `{
                 }
             }
         },
+        "/account/customer/{id}/tax": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark a customer VATABLE, EXEMPT or ZERO_RATED. Exempt and zero-rated customers need a tax id (TIN). Applies to quotes made afterwards.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Set a customer's VAT status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tax status",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateCustomerTaxRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateCustomerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/account/customer/{id}/{accId}": {
             "delete": {
                 "security": [
@@ -516,7 +574,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a booking record",
+                "description": "Creates a booking record and consumes its quote. The main service and addons are re-priced with the quote's catalog and must match what was quoted, as must the dirty scale, modifiers, address and the depot serving it; a mismatch fails with 409 and a diff listing each differing item. The booking's total is the quote's main service, addons and adjustment lines with tax. Unknown quotes fail with 404, another customer's with 403, already used ones or ones priced for a different time window than startSched with 409, and expired ones with 410.",
                 "consumes": [
                     "application/json"
                 ],
@@ -551,6 +609,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -560,14 +642,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/booking/user/{uid}": {
+        "/booking/id/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all bookings for a specific user",
+                "description": "Retrieve a fully hydrated booking by its database ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -577,12 +659,12 @@ const docTemplate = `{
                 "tags": [
                     "Booking"
                 ],
-                "summary": "Get booking by user ID",
+                "summary": "Get booking by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "uid",
+                        "description": "Booking ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -591,24 +673,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "additionalProperties": true
-                            }
+                            "$ref": "#/definitions/types.Booking"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/booking/{id}": {
+        "/booking/uid/{uid}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve booking information by its database ID",
+                "description": "Retrieve a paginated list of a customer's bookings, newest schedule first",
                 "consumes": [
                     "application/json"
                 ],
@@ -618,33 +708,82 @@ const docTemplate = `{
                 "tags": [
                     "Booking"
                 ],
-                "summary": "Get booking by ID",
+                "summary": "Get bookings by customer ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Booking ID",
-                        "name": "id",
+                        "description": "Customer ID",
+                        "name": "uid",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest schedule date (YYYY-MM-DD)",
+                        "name": "startDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest schedule date (YYYY-MM-DD)",
+                        "name": "endDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment status",
+                        "name": "paymentStatus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Review status",
+                        "name": "reviewStatus",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/types.BookingsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/booking/{id}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update booking information",
+                "description": "Reschedule or partially update a booking. Schedule changes re-run allocation against the linked quote. Moving the address and dirty scale, modifier and addon changes affect the price, so they need a new quote for the edited job in quoteId; without one they fail with 409. A new address must be covered by the depot and travel fee the quote was priced with. The new quote is checked against the edited booking like on creation, with a diff on mismatch, and consumed, and its promo code replaces the old quote's. A new start outside the time window the quote was priced for fails with 409.",
                 "consumes": [
                     "application/json"
                 ],
@@ -664,13 +803,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Updated booking info",
+                        "description": "Fields to change",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/types.UpdateBookingRequest"
                         }
                     }
                 ],
@@ -678,10 +816,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/types.UpdateBookingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
                         }
                     }
                 }
@@ -692,7 +845,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove booking by ID",
+                "description": "Marks the booking CANCELLED, releases its cleaners, equipment and resources, and refunds the cancellation policy's share of what was captured for it through the payment provider, with a CANCELLATION reason. Unpaid bookings get nothing back. A refund that fails does not undo the cancellation; it is reported in refundError and can be retried with the booking refund endpoint.",
                 "consumes": [
                     "application/json"
                 ],
@@ -702,7 +855,7 @@ const docTemplate = `{
                 "tags": [
                     "Booking"
                 ],
-                "summary": "Delete a booking",
+                "summary": "Cancel a booking",
                 "parameters": [
                     {
                         "type": "string",
@@ -710,59 +863,77 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Cancellation reason",
+                        "name": "input",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/types.CancelBookingRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/types.CancelBookingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/inventory": {
-            "post": {
+        "/booking/{id}/history": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new item to inventory",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve the current status of a booking and its status trail with actor and timestamp",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventory"
+                    "Booking"
                 ],
-                "summary": "Create a new inventory item",
+                "summary": "Get booking status history",
                 "parameters": [
                     {
-                        "description": "Item info",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.CreateItemRequest"
-                        }
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.InventoryItem"
+                            "$ref": "#/definitions/types.BookingStatusHistoryResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -776,46 +947,78 @@ const docTemplate = `{
                 }
             }
         },
-        "/inventory/": {
-            "get": {
+        "/booking/{id}/status": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all inventory items",
+                "description": "Move a booking along PENDING → CONFIRMED → EN_ROUTE → IN_PROGRESS → COMPLETED, or to CANCELLED / NO_SHOW. Illegal moves are rejected with 409. Dispatching (EN_ROUTE) before the deposit is paid is rejected with 402. Completing a booking issues its invoice.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventory"
+                    "Booking"
+                ],
+                "summary": "Change booking status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target status",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.TransitionBookingRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all items",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.InventoryItem"
-                            }
+                            "$ref": "#/definitions/types.BookingStatusChange"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/inventory": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Modify fields of an existing inventory item",
+                "description": "Adds a new item to inventory",
                 "consumes": [
                     "application/json"
                 ],
@@ -825,15 +1028,15 @@ const docTemplate = `{
                 "tags": [
                     "Inventory"
                 ],
-                "summary": "Update an inventory item",
+                "summary": "Create a new inventory item",
                 "parameters": [
                     {
-                        "description": "Updated item info",
+                        "description": "Item info",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateItemRequest"
+                            "$ref": "#/definitions/types.CreateItemRequest"
                         }
                     }
                 ],
@@ -859,38 +1062,88 @@ const docTemplate = `{
                 }
             }
         },
-        "/inventory/category/{category}": {
+        "/inventory/": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all inventory items in a category",
+                "description": "Lists inventory items matching every given filter, one page at a time. Pass next_cursor from a response as cursor to get the next page.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Inventory"
                 ],
-                "summary": "List items filtered by category",
+                "summary": "Search inventory items",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Category (general, electronics, furniture, etc)",
-                        "name": "category",
-                        "in": "path",
-                        "required": true
-                    }
+                        "description": "Item types, comma separated (RESOURCE, EQUIPMENT)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock statuses, comma separated (HIGH, LOW, DANGER, OUT_OF_STOCK)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categories, comma separated (GENERAL, ELECTRONICS, ...)",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only available or unavailable items",
+                        "name": "available",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive name substring",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum quantity",
+                        "name": "quantity_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum quantity",
+                        "name": "quantity_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name, quantity, status, created_at or updated_at; prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 25, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.InventoryItem"
-                            }
+                            "$ref": "#/definitions/types.InventoryItemsResponse"
                         }
                     },
                     "400": {
@@ -898,42 +1151,48 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/inventory/status/{status}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all inventory items with the given stock status",
+                "description": "Modify fields of an existing inventory item. A new quantity is treated as a stock count and recorded as an ADJUST movement. Status is recomputed from quantity and max quantity.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Inventory"
                 ],
-                "summary": "List items filtered by status",
+                "summary": "Update an inventory item",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Status (high, low, danger, out_of_stock)",
-                        "name": "status",
-                        "in": "path",
-                        "required": true
+                        "description": "Updated item info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateItemRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.InventoryItem"
-                            }
+                            "$ref": "#/definitions/types.InventoryItem"
                         }
                     },
                     "400": {
@@ -941,46 +1200,54 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/inventory/type/{type}": {
+        "/inventory/reorder-suggestions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all inventory items matching the given type",
+                "description": "Lists items whose available stock is at or below their reorder point, with an order quantity sized from consumption over the last ` + "`" + `days` + "`" + ` days and capped at the item's max quantity",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Inventory"
                 ],
-                "summary": "List items filtered by type",
+                "summary": "Get reorder suggestions",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Item type (resource, equipment)",
-                        "name": "type",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Consumption window in days (default 30)",
+                        "name": "days",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.InventoryItem"
-                            }
+                            "$ref": "#/definitions/types.ReorderSuggestionsResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -1069,14 +1336,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/payment/quote": {
+        "/inventory/{id}/movements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the item's movement ledger, newest first, with the quantity the ledger adds up to and the stock still reserved for bookings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get an item's stock movements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of movements (1-500, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.StockMovementsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Generate a new quotation for a customer",
+                "description": "Adds a RESTOCK, CONSUME, ADJUST, DAMAGE or RETURN movement to an item's ledger and applies it to its quantity. Quantity is positive except for ADJUST, which is signed. RESERVE movements are only made by bookings.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1084,17 +1407,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Payment"
+                    "Inventory"
                 ],
-                "summary": "Create a quotation",
+                "summary": "Record a stock movement",
                 "parameters": [
                     {
-                        "description": "Quote details",
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Movement",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.QuoteRequest"
+                            "$ref": "#/definitions/types.CreateStockMovementRequest"
                         }
                     }
                 ],
@@ -1102,7 +1432,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.QuoteResponse"
+                            "$ref": "#/definitions/types.StockMovementResponse"
                         }
                     },
                     "400": {
@@ -1111,6 +1441,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1120,39 +1456,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/payment/quote/preview": {
-            "post": {
-                "description": "Generate a new quotation",
-                "consumes": [
-                    "application/json"
+        "/payment/booking/{id}/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Shows the total, what has been paid net of refunds, the outstanding balance and the deposit. amountDue is the unpaid deposit until the booking is completed, then the whole balance. Several partial payments can be made against one booking.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Payment"
                 ],
-                "summary": "Create a quotation",
+                "summary": "Get a booking's balance",
                 "parameters": [
                     {
-                        "description": "Quote details",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.QuoteRequest"
+                            "$ref": "#/definitions/types.BookingBalance"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/booking/{id}/invoice": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the invoice issued when the booking was completed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Get a booking's invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.QuoteResponse"
+                            "$ref": "#/definitions/types.Invoice"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -1166,14 +1548,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/payment/quote/{customerId}": {
-            "get": {
+        "/payment/booking/{id}/pay": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all quotations associated with a specific customer",
+                "description": "Charges the balance due, or the given amount, through the payment provider and moves the booking's paymentStatus to PARTIALLY_PAID or PAID. A retry with the same idempotencyKey returns the first attempt. More than the balance due is rejected with 409 and a declined payment with 402.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1183,24 +1565,30 @@ const docTemplate = `{
                 "tags": [
                     "Payment"
                 ],
-                "summary": "Get all quotations for a customer",
+                "summary": "Pay for a booking",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Customer ID",
-                        "name": "customer_id",
+                        "description": "Booking ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Payment",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PayBookingRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.QuotesResponse"
-                            }
+                            "$ref": "#/definitions/types.PaymentResponse"
                         }
                     },
                     "400": {
@@ -1209,6 +1597,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1217,816 +1623,3708 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "types.Account": {
-            "type": "object",
-            "properties": {
-                "clerk_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+        },
+        "/payment/booking/{id}/refund": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns everything refundable, or the given amount, through the payment provider with a reason code (CANCELLATION, COMPLAINT, OVERCHARGE, DUPLICATE, OTHER). The amount is taken from the newest payments first, one REFUND transaction per payment; more than was captured and not yet refunded is rejected with 409. Each succeeded refund is credited on the booking's invoice with a credit note. A refund that failed at the provider is listed as FAILED, and 502 is returned when all of them failed. A retry with the same idempotencyKey returns the first attempt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Refund a booking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.RefundBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.RefundResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/booking/{id}/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the payments and refunds of a booking, oldest first, including failed ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "List a booking's transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Booking ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.Transaction"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/catalogs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve every price catalog version, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "List price catalog versions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.PriceCatalog"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds the next catalog version. It prices new quotes from effectiveFrom (default now), which cannot be in the past.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Create a price catalog version",
+                "parameters": [
+                    {
+                        "description": "Catalog",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PriceCatalogRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PriceCatalog"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/catalogs/active": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the price catalog version quotes are currently priced with",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Get the active price catalog",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PriceCatalog"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/catalogs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Get a price catalog version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Catalog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PriceCatalog"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces a catalog version that has not taken effect yet. Versions in effect cannot change, since quotes were priced with them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Update a scheduled price catalog version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Catalog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Catalog",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PriceCatalogRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PriceCatalog"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a catalog version that has not taken effect yet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Delete a scheduled price catalog version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Catalog ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.PriceCatalog"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/depots": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve every depot of the service area, active or not",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Area"
+                ],
+                "summary": "List depots",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.Depot"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a depot with radius bands ordered by maxKm. Its last band is the edge of its coverage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Area"
+                ],
+                "summary": "Create a depot",
+                "parameters": [
+                    {
+                        "description": "Depot",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DepotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Depot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/depots/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces a depot. Quotes already priced keep their travel fee.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Area"
+                ],
+                "summary": "Update a depot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Depot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Depot",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.DepotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Depot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Takes a depot out of the service area. It is kept for the quotes it priced.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Service Area"
+                ],
+                "summary": "Deactivate a depot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Depot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Depot"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/holidays": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the holiday calendar from today on. Time pricing rules see these dates as HOLIDAY.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "List holidays",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.Holiday"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a date to the holiday calendar, or renames it if it is already there. Quotes already priced keep their time window lines.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Add a holiday",
+                "parameters": [
+                    {
+                        "description": "Holiday",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.HolidayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Holiday"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/holidays/{date}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a date from the holiday calendar",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Remove a holiday",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Holiday"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/invoices/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve an invoice with its lines and credit notes. Invoices are issued when a booking is COMPLETED.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Get an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Invoice"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/invoices/{id}/pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render an invoice as a PDF document",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Download an invoice as PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/invoices/{id}/void": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels an issued invoice. Its number stays used; paid and already voided invoices are rejected with 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoices"
+                ],
+                "summary": "Void an invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.VoidInvoiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Invoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/promotions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve every promo code with its redemption count, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "List promotions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.Promotion"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a PERCENT (percentOff) or FIXED (amountOff) promo code. Codes are case-insensitive and unique.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Create a promotion",
+                "parameters": [
+                    {
+                        "description": "Promotion",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/promotions/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces a promotion. Quotes already discounted keep their discount.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Update a promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Promotion",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stops a promo code from applying to new quotes. It is kept for its redemptions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Deactivate a promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Promotion"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/quote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate a new quotation for a customer. Surcharges for the dirtyScale and modifiers (codes from the active catalog), time window rules for an optional startSched (PHT, weekday or holiday), the travel fee from the nearest depot and an optional promoCode are applied as adjustment lines. A code that does not apply is rejected with 400, an address outside the service area with 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Create a quotation",
+                "parameters": [
+                    {
+                        "description": "Quote details",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.QuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.QuoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/quote/preview": {
+            "post": {
+                "description": "Generate a new quotation. Surcharges for the dirtyScale and modifiers (codes from the active catalog), time window rules for an optional startSched (PHT, weekday or holiday), the travel fee from the nearest depot and an optional promoCode are applied as adjustment lines. A code that does not apply is rejected with 400, an address outside the service area with 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Create a quotation",
+                "parameters": [
+                    {
+                        "description": "Quote details",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.QuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.QuoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/quote/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a single quotation with its main service and addon details, e.g. to pre-fill a booking",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Get a quotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Quote ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.QuoteDetail"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/quotes/{customerId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a paginated list of a customer's quotations, newest first, with their addons",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Get all quotations for a customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "customerId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.QuotesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/webhook": {
+            "post": {
+                "description": "Settles a payment or refund reported by the payment provider. The raw body must be signed with HMAC-SHA256 under PAYMENT_WEBHOOK_SECRET, hex encoded in the X-Webhook-Signature header. Each event id is applied once; redeliveries are acknowledged as duplicates. Only pending transactions are settled; an event contradicting a settled one is recorded as a DISCREPANCY for review.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Receive a payment provider event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "hex HMAC-SHA256 of the body",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Event",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.WebhookEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "types.Account": {
+            "type": "object",
+            "properties": {
+                "clerk_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.AddOnBreakdown": {
+            "type": "object",
+            "properties": {
+                "addonId": {
+                    "type": "string"
+                },
+                "addonName": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                }
+            }
+        },
+        "types.AddOnRequest": {
+            "type": "object",
+            "properties": {
+                "serviceDetail": {
+                    "$ref": "#/definitions/types.ServicesRequest"
+                }
+            }
+        },
+        "types.AddOns": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "serviceDetail": {
+                    "$ref": "#/definitions/types.ServiceDetails"
+                }
+            }
+        },
+        "types.Address": {
+            "type": "object",
+            "properties": {
+                "addressHuman": {
+                    "type": "string"
+                },
+                "addressLat": {
+                    "type": "number"
+                },
+                "addressLng": {
+                    "type": "number"
+                }
+            }
+        },
+        "types.AdjustmentKind": {
+            "type": "string",
+            "enum": [
+                "SURCHARGE",
+                "TIME_WINDOW",
+                "TRAVEL_FEE",
+                "DISCOUNT"
+            ],
+            "x-enum-varnames": [
+                "AdjustmentSurcharge",
+                "AdjustmentTimeWindow",
+                "AdjustmentTravelFee",
+                "AdjustmentDiscount"
+            ]
+        },
+        "types.BaseBookingDetails": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/types.Address"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "custId": {
+                    "type": "string"
+                },
+                "customerFirstName": {
+                    "type": "string"
+                },
+                "customerLastName": {
+                    "type": "string"
+                },
+                "dirtyScale": {
+                    "type": "integer"
+                },
+                "endSched": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "paymentStatus": {
+                    "$ref": "#/definitions/types.PaymentStatus"
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quoteId": {
+                    "type": "string"
+                },
+                "reviewStatus": {
+                    "type": "string"
+                },
+                "startSched": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/types.BookingStatus"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.BaseBookingDetailsRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/types.Address"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "custId": {
+                    "type": "string"
+                },
+                "customerFirstName": {
+                    "type": "string"
+                },
+                "customerLastName": {
+                    "type": "string"
+                },
+                "dirtyScale": {
+                    "type": "integer"
+                },
+                "endSched": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "modifiers": {
+                    "description": "catalog codes the quote was priced with, e.g. PETS",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quoteId": {
+                    "type": "string"
+                },
+                "startSched": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.Booking": {
+            "type": "object",
+            "properties": {
+                "addons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.AddOns"
+                    }
+                },
+                "base": {
+                    "$ref": "#/definitions/types.BaseBookingDetails"
+                },
+                "cleaners": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CleanerAssigned"
+                    }
+                },
+                "equipments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CleaningEquipment"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mainService": {
+                    "$ref": "#/definitions/types.ServiceDetails"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CleaningResources"
+                    }
+                },
+                "tax": {
+                    "$ref": "#/definitions/types.TaxBreakdown"
+                },
+                "totalPrice": {
+                    "description": "gross",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
+                }
+            }
+        },
+        "types.BookingBalance": {
+            "type": "object",
+            "properties": {
+                "amountDue": {
+                    "description": "due now",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
+                },
+                "balance": {
+                    "description": "outstanding",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
+                },
+                "bookingId": {
+                    "type": "string"
+                },
+                "bookingStatus": {
+                    "$ref": "#/definitions/types.BookingStatus"
+                },
+                "deposit": {
+                    "description": "required before dispatch, zero if none",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
+                },
+                "depositOutstanding": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "paid": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "paymentStatus": {
+                    "$ref": "#/definitions/types.PaymentStatus"
+                },
+                "pending": {
+                    "description": "payments still waiting on the provider",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
+                },
+                "serviceType": {
+                    "$ref": "#/definitions/types.MainServiceType"
+                },
+                "total": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                }
+            }
+        },
+        "types.BookingChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "from": {},
+                "to": {}
+            }
+        },
+        "types.BookingStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "CONFIRMED",
+                "EN_ROUTE",
+                "IN_PROGRESS",
+                "COMPLETED",
+                "CANCELLED",
+                "NO_SHOW"
+            ],
+            "x-enum-varnames": [
+                "BookingPending",
+                "BookingConfirmed",
+                "BookingEnRoute",
+                "BookingInProgress",
+                "BookingCompleted",
+                "BookingCancelled",
+                "BookingNoShow"
+            ]
+        },
+        "types.BookingStatusChange": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "bookingId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "fromStatus": {
+                    "$ref": "#/definitions/types.BookingStatus"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "toStatus": {
+                    "$ref": "#/definitions/types.BookingStatus"
+                }
+            }
+        },
+        "types.BookingStatusHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "bookingId": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BookingStatusChange"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/types.BookingStatus"
+                }
+            }
+        },
+        "types.BookingsResponse": {
+            "type": "object",
+            "properties": {
+                "bookings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.Booking"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "totalCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CancelBookingRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CancelBookingResponse": {
+            "type": "object",
+            "properties": {
+                "bookingId": {
+                    "type": "string"
+                },
+                "cancelledAt": {
+                    "type": "string"
+                },
+                "cancelledBy": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "refund": {
+                    "description": "the refund paid out, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.RefundResponse"
+                        }
+                    ]
+                },
+                "refundError": {
+                    "description": "why the refund could not be paid out; retry with the booking refund endpoint",
+                    "type": "string"
+                },
+                "refundPercent": {
+                    "type": "integer"
+                },
+                "refundableAmount": {
+                    "description": "the policy's share of what was captured and not yet refunded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
+                },
+                "status": {
+                    "$ref": "#/definitions/types.BookingStatus"
+                }
+            }
+        },
+        "types.CarCleaningDetails": {
+            "type": "object",
+            "properties": {
+                "childSeats": {
+                    "type": "integer"
+                },
+                "cleaningSpecs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CarCleaningSpecifications"
+                    }
+                }
+            }
+        },
+        "types.CarCleaningSpecifications": {
+            "type": "object",
+            "properties": {
+                "carType": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CleanerAssigned": {
+            "type": "object",
+            "properties": {
+                "cleanerFirstName": {
+                    "type": "string"
+                },
+                "cleanerLastName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "pfpUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CleaningEquipment": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "photoUrl": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CleaningResources": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "photoUrl": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CouchCleaningDetails": {
+            "type": "object",
+            "properties": {
+                "bedPillows": {
+                    "type": "integer"
+                },
+                "cleaningSpecs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CouchCleaningSpecifications"
+                    }
+                }
+            }
+        },
+        "types.CouchCleaningSpecifications": {
+            "type": "object",
+            "properties": {
+                "couchType": {
+                    "type": "string"
+                },
+                "depthCm": {
+                    "type": "integer"
+                },
+                "heightCm": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "widthCm": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CreateBookingRequest": {
+            "type": "object",
+            "properties": {
+                "addons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.AddOnRequest"
+                    }
+                },
+                "base": {
+                    "$ref": "#/definitions/types.BaseBookingDetailsRequest"
+                },
+                "mainService": {
+                    "$ref": "#/definitions/types.ServicesRequest"
+                }
+            }
+        },
+        "types.CreateItemRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "name",
+                "quantity",
+                "type",
+                "unit"
+            ],
+            "properties": {
+                "category": {
+                    "description": "GENERAL / ELECTRONICS / ...",
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reorder_point": {
+                    "description": "optional, defaults to half of the initial quantity",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "type": {
+                    "description": "RESOURCE / EQUIPMENT",
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateStockMovementRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "type"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/types.MovementType"
+                }
+            }
+        },
+        "types.CreditNote": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "bookingId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invoiceId": {
+                    "type": "string"
+                },
+                "invoiceNumber": {
+                    "type": "string"
+                },
+                "issuedAt": {
+                    "type": "string"
+                },
+                "net": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "reason": {
+                    "$ref": "#/definitions/types.RefundReason"
+                },
+                "tax": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "transactionId": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.Customer": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/types.Account"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "tax_id": {
+                    "description": "TIN, required for EXEMPT and ZERO_RATED",
+                    "type": "string"
+                },
+                "tax_status": {
+                    "$ref": "#/definitions/types.TaxStatus"
+                }
+            }
+        },
+        "types.DeleteCustomerResponse": {
+            "type": "object",
+            "properties": {
+                "customer": {
+                    "$ref": "#/definitions/types.Customer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.DeleteEmployeeResponse": {
+            "type": "object",
+            "properties": {
+                "employee": {
+                    "$ref": "#/definitions/types.Employee"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.Depot": {
+            "type": "object",
+            "properties": {
+                "bands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.TravelBand"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isActive": {
+                    "type": "boolean"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.DepotRequest": {
+            "type": "object",
+            "required": [
+                "bands",
+                "lat",
+                "lng",
+                "name"
+            ],
+            "properties": {
+                "bands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.TravelBand"
+                    }
+                },
+                "isActive": {
+                    "type": "boolean"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.DirtyScaleSurcharge": {
+            "type": "object",
+            "properties": {
+                "flat": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "minScale": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.DiscountType": {
+            "type": "string",
+            "enum": [
+                "PERCENT",
+                "FIXED"
+            ],
+            "x-enum-varnames": [
+                "DiscountPercent",
+                "DiscountFixed"
+            ]
+        },
+        "types.Employee": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/types.Account"
+                },
+                "hire_date": {
+                    "type": "string"
                 },
-                "email": {
+                "id": {
                     "type": "string"
                 },
-                "first_name": {
+                "num_ratings": {
+                    "type": "integer"
+                },
+                "performance_score": {
+                    "type": "number"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "ACTIVE / ONDUTY / INACTIVE",
+                    "type": "string"
+                }
+            }
+        },
+        "types.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "diff": {
+                    "description": "how a booking differs from its quote",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.QuoteDiff"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.GeneralCleaningDetails": {
+            "type": "object",
+            "properties": {
+                "homeType": {
+                    "type": "string"
+                },
+                "sqm": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.GeneralCleaningTier": {
+            "type": "object",
+            "properties": {
+                "homeType": {
+                    "type": "string"
+                },
+                "maxSqm": {
+                    "type": "integer"
+                },
+                "minSqm": {
+                    "type": "integer"
+                },
+                "price": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                }
+            }
+        },
+        "types.GetCustomerResponse": {
+            "type": "object",
+            "properties": {
+                "customer": {
+                    "$ref": "#/definitions/types.Customer"
+                }
+            }
+        },
+        "types.GetEmployeeResponse": {
+            "type": "object",
+            "properties": {
+                "employee": {
+                    "$ref": "#/definitions/types.Employee"
+                }
+            }
+        },
+        "types.Holiday": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2026-12-25"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.HolidayRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "name"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-12-25"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.InventoryItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/types.ItemCategory"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "max_quantity": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reorder_point": {
+                    "description": "ReorderPoint is the stock level that triggers a reorder; nil uses half of MaxQuantity.",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/types.ItemStatus"
+                },
+                "type": {
+                    "$ref": "#/definitions/types.ItemType"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.InventoryItemsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.InventoryItem"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.Invoice": {
+            "type": "object",
+            "properties": {
+                "billingAddress": {
+                    "type": "string"
+                },
+                "bookingId": {
+                    "type": "string"
+                },
+                "creditNotes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CreditNote"
+                    }
+                },
+                "customerId": {
+                    "type": "string"
+                },
+                "customerName": {
+                    "type": "string"
+                },
+                "dueAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issuedAt": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.InvoiceLine"
+                    }
+                },
+                "number": {
+                    "type": "string"
+                },
+                "paidAt": {
+                    "type": "string"
+                },
+                "series": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/types.InvoiceStatus"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "tax": {
+                    "$ref": "#/definitions/types.TaxBreakdown"
+                },
+                "voidReason": {
+                    "type": "string"
+                },
+                "voidedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.InvoiceLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "before tax, negative for discounts",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/types.InvoiceLineKind"
+                }
+            }
+        },
+        "types.InvoiceLineKind": {
+            "type": "string",
+            "enum": [
+                "SERVICE",
+                "ADDON"
+            ],
+            "x-enum-varnames": [
+                "InvoiceLineService",
+                "InvoiceLineAddon"
+            ]
+        },
+        "types.InvoiceStatus": {
+            "type": "string",
+            "enum": [
+                "ISSUED",
+                "PAID",
+                "VOID"
+            ],
+            "x-enum-varnames": [
+                "InvoiceIssued",
+                "InvoicePaid",
+                "InvoiceVoid"
+            ]
+        },
+        "types.ItemCategory": {
+            "type": "string",
+            "enum": [
+                "GENERAL",
+                "ELECTRONICS",
+                "FURNITURE",
+                "APPLIANCES",
+                "VEHICLES",
+                "OTHER"
+            ],
+            "x-enum-varnames": [
+                "CategoryGeneral",
+                "CategoryElectronics",
+                "CategoryFurniture",
+                "CategoryAppliances",
+                "CategoryVehicles",
+                "CategoryOther"
+            ]
+        },
+        "types.ItemStatus": {
+            "type": "string",
+            "enum": [
+                "HIGH",
+                "LOW",
+                "DANGER",
+                "OUT_OF_STOCK"
+            ],
+            "x-enum-varnames": [
+                "ItemStatusHigh",
+                "ItemStatusLow",
+                "ItemStatusDanger",
+                "ItemStatusOutOfStock"
+            ]
+        },
+        "types.ItemType": {
+            "type": "string",
+            "enum": [
+                "RESOURCE",
+                "EQUIPMENT"
+            ],
+            "x-enum-varnames": [
+                "ItemTypeResource",
+                "ItemTypeEquipment"
+            ]
+        },
+        "types.MainServiceType": {
+            "type": "string",
+            "enum": [
+                "SERVICE_TYPE_UNSPECIFIED",
+                "GENERAL_CLEANING",
+                "COUCH",
+                "MATTRESS",
+                "CAR",
+                "POST"
+            ],
+            "x-enum-varnames": [
+                "ServiceTypeUnspecified",
+                "GeneralCleaning",
+                "CouchCleaning",
+                "MattressCleaning",
+                "CarCleaning",
+                "PostCleaning"
+            ]
+        },
+        "types.MattressCleaningDetails": {
+            "type": "object",
+            "properties": {
+                "cleaningSpecs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.MattressCleaningSpecifications"
+                    }
+                }
+            }
+        },
+        "types.MattressCleaningSpecifications": {
+            "type": "object",
+            "properties": {
+                "bedType": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "depthCm": {
+                    "type": "integer"
                 },
-                "last_name": {
-                    "type": "string"
+                "heightCm": {
+                    "type": "integer"
                 },
-                "provider": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
                 },
-                "role": {
-                    "type": "string"
+                "widthCm": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.MoneyJSON": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "1250.50"
                 },
-                "updated_at": {
-                    "type": "string"
+                "currency": {
+                    "type": "string",
+                    "example": "PHP"
                 }
             }
         },
-        "types.AddOnBreakdown": {
+        "types.MovementType": {
+            "type": "string",
+            "enum": [
+                "RESTOCK",
+                "CONSUME",
+                "ADJUST",
+                "DAMAGE",
+                "RETURN",
+                "RESERVE"
+            ],
+            "x-enum-varnames": [
+                "MovementRestock",
+                "MovementConsume",
+                "MovementAdjust",
+                "MovementDamage",
+                "MovementReturn",
+                "MovementReserve"
+            ]
+        },
+        "types.PayBookingRequest": {
             "type": "object",
+            "required": [
+                "method"
+            ],
             "properties": {
-                "addonId": {
-                    "type": "string"
+                "amount": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "addonName": {
+                "idempotencyKey": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "method": {
+                    "type": "string",
+                    "example": "card"
                 }
             }
         },
-        "types.AddOnRequest": {
+        "types.PaymentResponse": {
             "type": "object",
             "properties": {
-                "serviceDetail": {
-                    "$ref": "#/definitions/types.ServicesRequest"
+                "balance": {
+                    "$ref": "#/definitions/types.BookingBalance"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/types.Transaction"
                 }
             }
         },
-        "types.AddOns": {
+        "types.PaymentStatus": {
+            "type": "string",
+            "enum": [
+                "UNPAID",
+                "PARTIALLY_PAID",
+                "PAID"
+            ],
+            "x-enum-varnames": [
+                "PaymentUnpaid",
+                "PaymentPartiallyPaid",
+                "PaymentPaid"
+            ]
+        },
+        "types.PostConstructionDetails": {
+            "type": "object",
+            "properties": {
+                "sqm": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PriceCatalog": {
             "type": "object",
             "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "effectiveFrom": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "notes": {
+                    "type": "string"
                 },
-                "serviceDetail": {
-                    "$ref": "#/definitions/types.ServiceDetails"
+                "rates": {
+                    "$ref": "#/definitions/types.PriceRates"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "types.Address": {
+        "types.PriceCatalogRequest": {
             "type": "object",
+            "required": [
+                "rates"
+            ],
             "properties": {
-                "addressHuman": {
+                "effectiveFrom": {
                     "type": "string"
                 },
-                "addressLat": {
-                    "type": "number"
+                "notes": {
+                    "type": "string"
                 },
-                "addressLng": {
-                    "type": "number"
+                "rates": {
+                    "$ref": "#/definitions/types.PriceRates"
                 }
             }
         },
-        "types.BaseBookingDetails": {
+        "types.PriceModifier": {
             "type": "object",
             "properties": {
-                "address": {
-                    "$ref": "#/definitions/types.Address"
+                "flat": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "createdAt": {
-                    "type": "string"
-                },
-                "custId": {
+                "label": {
                     "type": "string"
                 },
-                "customerFirstName": {
-                    "type": "string"
+                "percent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PriceRates": {
+            "type": "object",
+            "properties": {
+                "bedPillow": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "customerLastName": {
-                    "type": "string"
+                "car": {
+                    "description": "by car type",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/types.MoneyJSON"
+                    }
                 },
-                "dirtyScale": {
-                    "type": "integer"
+                "childSeat": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "endSched": {
-                    "type": "string"
+                "couch": {
+                    "description": "by couch type",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/types.MoneyJSON"
+                    }
                 },
-                "id": {
-                    "type": "string"
+                "dirtyScaleSurcharges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DirtyScaleSurcharge"
+                    }
                 },
-                "paymentStatus": {
-                    "type": "string"
+                "generalPerSqm": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "photos": {
+                "generalTiers": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/types.GeneralCleaningTier"
                     }
                 },
-                "quoteId": {
-                    "type": "string"
+                "mattress": {
+                    "description": "by bed type",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/types.MoneyJSON"
+                    }
                 },
-                "reviewStatus": {
-                    "type": "string"
+                "modifiers": {
+                    "description": "by modifier code, e.g. PETS",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/types.PriceModifier"
+                    }
                 },
-                "startSched": {
-                    "type": "string"
+                "postConstructionPerSqm": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "timeRules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.TimePricingRule"
+                    }
                 }
             }
         },
-        "types.BaseBookingDetailsRequest": {
+        "types.Promotion": {
             "type": "object",
             "properties": {
-                "address": {
-                    "$ref": "#/definitions/types.Address"
+                "amountOff": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "code": {
+                    "type": "string"
                 },
                 "createdAt": {
                     "type": "string"
                 },
-                "custId": {
+                "createdBy": {
                     "type": "string"
                 },
-                "customerFirstName": {
+                "description": {
                     "type": "string"
                 },
-                "customerLastName": {
+                "discountType": {
+                    "$ref": "#/definitions/types.DiscountType"
+                },
+                "id": {
                     "type": "string"
                 },
-                "dirtyScale": {
+                "isActive": {
+                    "type": "boolean"
+                },
+                "minSpend": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "perCustomerLimit": {
                     "type": "integer"
                 },
-                "endSched": {
-                    "type": "string"
+                "percentOff": {
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "redemptions": {
+                    "type": "integer"
                 },
-                "photos": {
+                "serviceTypes": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "quoteId": {
+                "updatedAt": {
                     "type": "string"
                 },
-                "startSched": {
+                "usageLimit": {
+                    "type": "integer"
+                },
+                "validFrom": {
                     "type": "string"
                 },
-                "updatedAt": {
+                "validUntil": {
                     "type": "string"
                 }
             }
         },
-        "types.Booking": {
+        "types.PromotionRequest": {
             "type": "object",
+            "required": [
+                "code",
+                "discountType"
+            ],
             "properties": {
-                "addons": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.AddOns"
-                    }
+                "amountOff": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "base": {
-                    "$ref": "#/definitions/types.BaseBookingDetails"
+                "code": {
+                    "type": "string"
                 },
-                "cleaners": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.CleanerAssigned"
-                    }
+                "description": {
+                    "type": "string"
                 },
-                "equipments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.CleaningEquipment"
-                    }
+                "discountType": {
+                    "$ref": "#/definitions/types.DiscountType"
                 },
-                "id": {
-                    "type": "string"
+                "isActive": {
+                    "type": "boolean"
                 },
-                "mainService": {
-                    "$ref": "#/definitions/types.ServiceDetails"
+                "minSpend": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "resources": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.CleaningResources"
-                    }
+                "perCustomerLimit": {
+                    "type": "integer"
                 },
-                "totalPrice": {
-                    "type": "number"
-                }
-            }
-        },
-        "types.CarCleaningDetails": {
-            "type": "object",
-            "properties": {
-                "childSeats": {
+                "percentOff": {
                     "type": "integer"
                 },
-                "cleaningSpecs": {
+                "serviceTypes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.CarCleaningSpecifications"
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "types.CarCleaningSpecifications": {
-            "type": "object",
-            "properties": {
-                "carType": {
-                    "type": "string"
                 },
-                "quantity": {
+                "usageLimit": {
                     "type": "integer"
+                },
+                "validFrom": {
+                    "type": "string"
+                },
+                "validUntil": {
+                    "type": "string"
                 }
             }
         },
-        "types.CleanerAssigned": {
+        "types.QuoteAddonDetail": {
             "type": "object",
-            "properties": {
-                "cleanerFirstName": {
-                    "type": "string"
-                },
-                "cleanerLastName": {
+            "properties": {
+                "createdAt": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "pfpUrl": {
+                "price": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "serviceDetail": {
+                    "$ref": "#/definitions/types.ServicesRequest"
+                },
+                "serviceType": {
                     "type": "string"
                 }
             }
         },
-        "types.CleaningEquipment": {
+        "types.QuoteAdjustment": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "string"
+                "amount": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "name": {
+                "code": {
+                    "description": "e.g. the promo code",
                     "type": "string"
                 },
-                "photoUrl": {
-                    "type": "string"
+                "kind": {
+                    "$ref": "#/definitions/types.AdjustmentKind"
                 },
-                "type": {
+                "label": {
                     "type": "string"
                 }
             }
         },
-        "types.CleaningResources": {
+        "types.QuoteDetail": {
             "type": "object",
             "properties": {
-                "id": {
+                "addonTotal": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "addons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.QuoteAddonDetail"
+                    }
+                },
+                "address": {
+                    "$ref": "#/definitions/types.Address"
+                },
+                "adjustmentTotal": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "adjustments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.QuoteAdjustment"
+                    }
+                },
+                "catalogVersion": {
+                    "description": "nil for quotes priced before catalogs",
+                    "type": "integer"
+                },
+                "consumedAt": {
                     "type": "string"
                 },
-                "name": {
+                "consumedBy": {
+                    "description": "base booking id",
                     "type": "string"
                 },
-                "photoUrl": {
+                "createdAt": {
                     "type": "string"
                 },
-                "type": {
+                "customerId": {
                     "type": "string"
-                }
-            }
-        },
-        "types.CouchCleaningDetails": {
-            "type": "object",
-            "properties": {
-                "bedPillows": {
+                },
+                "dirtyScale": {
                     "type": "integer"
                 },
-                "cleaningSpecs": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isValid": {
+                    "type": "boolean"
+                },
+                "mainService": {
+                    "type": "string"
+                },
+                "mainServiceDetail": {
+                    "$ref": "#/definitions/types.ServicesRequest"
+                },
+                "modifiers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.CouchCleaningSpecifications"
+                        "type": "string"
                     }
+                },
+                "startSched": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "tax": {
+                    "$ref": "#/definitions/types.TaxBreakdown"
+                },
+                "totalPrice": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "travel": {
+                    "$ref": "#/definitions/types.TravelQuote"
+                },
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "types.CouchCleaningSpecifications": {
+        "types.QuoteDiff": {
             "type": "object",
             "properties": {
-                "couchType": {
+                "item": {
+                    "description": "MAIN_SERVICE, ADDON, TOTAL, DIRTY_SCALE, MODIFIERS, ADDRESS or DEPOT",
                     "type": "string"
                 },
-                "depthCm": {
-                    "type": "integer"
+                "quoted": {
+                    "description": "nil when the item was not quoted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
                 },
-                "heightCm": {
-                    "type": "integer"
+                "quotedValue": {},
+                "reason": {
+                    "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "serviceType": {
+                    "type": "string"
                 },
-                "widthCm": {
-                    "type": "integer"
-                }
+                "submitted": {
+                    "description": "nil when the item was not submitted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
+                },
+                "submittedValue": {}
             }
         },
-        "types.CreateBookingRequest": {
+        "types.QuoteRequest": {
             "type": "object",
             "properties": {
                 "addons": {
+                    "description": "same here",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/types.AddOnRequest"
                     }
                 },
-                "base": {
-                    "$ref": "#/definitions/types.BaseBookingDetailsRequest"
+                "address": {
+                    "description": "required once depots are set up",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.Address"
+                        }
+                    ]
                 },
-                "mainService": {
-                    "$ref": "#/definitions/types.ServicesRequest"
+                "customerId": {
+                    "type": "string"
+                },
+                "dirtyScale": {
+                    "type": "integer"
+                },
+                "modifiers": {
+                    "description": "codes from the catalog, e.g. PETS, STAIRS_NO_LIFT",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "promoCode": {
+                    "description": "optional, case-insensitive",
+                    "type": "string"
+                },
+                "service": {
+                    "description": "nested structs usually don't need db tags",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.ServicesRequest"
+                        }
+                    ]
+                },
+                "startSched": {
+                    "description": "desired slot; prices time rules, and the booking must match them",
+                    "type": "string"
                 }
             }
         },
-        "types.CreateItemRequest": {
+        "types.QuoteResponse": {
             "type": "object",
-            "required": [
-                "category",
-                "name",
-                "quantity",
-                "type",
-                "unit"
-            ],
             "properties": {
-                "category": {
-                    "description": "GENERAL / ELECTRONICS / ...",
-                    "type": "string"
+                "addonTotal": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "image_url": {
-                    "type": "string"
+                "addons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.AddOnBreakdown"
+                    }
                 },
-                "name": {
-                    "type": "string"
+                "adjustmentTotal": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "quantity": {
+                "adjustments": {
+                    "description": "e.g. the promo discount line",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.QuoteAdjustment"
+                    }
+                },
+                "catalogVersion": {
                     "type": "integer"
                 },
-                "type": {
-                    "description": "RESOURCE / EQUIPMENT",
+                "expiresAt": {
+                    "description": "unset for previews",
                     "type": "string"
                 },
-                "unit": {
+                "mainServiceName": {
+                    "type": "string"
+                },
+                "mainServiceTotal": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "quote_id": {
                     "type": "string"
+                },
+                "tax": {
+                    "$ref": "#/definitions/types.TaxBreakdown"
+                },
+                "totalPrice": {
+                    "description": "gross, what the customer pays",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.MoneyJSON"
+                        }
+                    ]
+                },
+                "travel": {
+                    "description": "unset when no depots are set up",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.TravelQuote"
+                        }
+                    ]
                 }
             }
         },
-        "types.Customer": {
+        "types.QuotesResponse": {
             "type": "object",
             "properties": {
-                "account": {
-                    "$ref": "#/definitions/types.Account"
+                "limit": {
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "page": {
+                    "type": "integer"
+                },
+                "quotes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.QuoteDetail"
+                    }
+                },
+                "totalCount": {
+                    "type": "integer"
                 }
             }
         },
-        "types.DeleteCustomerResponse": {
+        "types.RefundBookingRequest": {
             "type": "object",
+            "required": [
+                "reason"
+            ],
             "properties": {
-                "customer": {
-                    "$ref": "#/definitions/types.Customer"
+                "amount": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "message": {
+                "idempotencyKey": {
                     "type": "string"
                 },
-                "ok": {
-                    "type": "boolean"
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.RefundReason"
+                        }
+                    ],
+                    "example": "CANCELLATION"
                 }
             }
         },
-        "types.DeleteEmployeeResponse": {
+        "types.RefundReason": {
+            "type": "string",
+            "enum": [
+                "CANCELLATION",
+                "COMPLAINT",
+                "OVERCHARGE",
+                "DUPLICATE",
+                "OTHER"
+            ],
+            "x-enum-varnames": [
+                "RefundCancellation",
+                "RefundComplaint",
+                "RefundOvercharge",
+                "RefundDuplicate",
+                "RefundOther"
+            ]
+        },
+        "types.RefundResponse": {
             "type": "object",
             "properties": {
-                "employee": {
-                    "$ref": "#/definitions/types.Employee"
+                "balance": {
+                    "$ref": "#/definitions/types.BookingBalance"
                 },
-                "message": {
-                    "type": "string"
+                "creditNotes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.CreditNote"
+                    }
                 },
-                "ok": {
-                    "type": "boolean"
+                "refunds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.Transaction"
+                    }
                 }
             }
         },
-        "types.Employee": {
+        "types.ReorderSuggestion": {
             "type": "object",
             "properties": {
-                "account": {
-                    "$ref": "#/definitions/types.Account"
+                "available": {
+                    "type": "integer"
                 },
-                "hire_date": {
+                "consumed": {
+                    "type": "integer"
+                },
+                "daily_usage": {
+                    "type": "number"
+                },
+                "days_of_stock": {
+                    "type": "number"
+                },
+                "item_id": {
                     "type": "string"
                 },
-                "id": {
+                "max_quantity": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 },
-                "num_ratings": {
+                "quantity": {
                     "type": "integer"
                 },
-                "performance_score": {
-                    "type": "number"
+                "reorder_point": {
+                    "type": "integer"
                 },
-                "position": {
-                    "type": "string"
+                "reserved": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/types.ItemStatus"
                 },
-                "status": {
-                    "description": "ACTIVE / ONDUTY / INACTIVE",
+                "suggested_quantity": {
+                    "type": "integer"
+                },
+                "unit": {
                     "type": "string"
                 }
             }
         },
-        "types.ErrorResponse": {
+        "types.ReorderSuggestionsResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "generated_at": {
                     "type": "string"
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ReorderSuggestion"
+                    }
+                },
+                "window_days": {
+                    "type": "integer"
                 }
             }
         },
-        "types.GeneralCleaningDetails": {
+        "types.ServiceDetail": {
             "type": "object",
             "properties": {
-                "homeType": {
-                    "type": "string"
+                "car": {
+                    "$ref": "#/definitions/types.CarCleaningDetails"
                 },
-                "sqm": {
-                    "type": "integer"
+                "couch": {
+                    "$ref": "#/definitions/types.CouchCleaningDetails"
+                },
+                "general": {
+                    "$ref": "#/definitions/types.GeneralCleaningDetails"
+                },
+                "mattress": {
+                    "$ref": "#/definitions/types.MattressCleaningDetails"
+                },
+                "post": {
+                    "$ref": "#/definitions/types.PostConstructionDetails"
                 }
             }
         },
-        "types.GetCustomerResponse": {
+        "types.ServiceDetails": {
             "type": "object",
             "properties": {
-                "customer": {
-                    "$ref": "#/definitions/types.Customer"
+                "details": {},
+                "id": {
+                    "type": "string"
+                },
+                "serviceType": {
+                    "type": "string"
                 }
             }
         },
-        "types.GetEmployeeResponse": {
+        "types.ServicesRequest": {
             "type": "object",
             "properties": {
-                "employee": {
-                    "$ref": "#/definitions/types.Employee"
+                "details": {
+                    "$ref": "#/definitions/types.ServiceDetail"
+                },
+                "serviceType": {
+                    "$ref": "#/definitions/types.MainServiceType"
                 }
             }
         },
-        "types.InventoryItem": {
+        "types.SignUpCustomerRequest": {
             "type": "object",
+            "required": [
+                "clerk_id",
+                "email",
+                "first_name",
+                "last_name",
+                "provider",
+                "role"
+            ],
             "properties": {
-                "category": {
-                    "$ref": "#/definitions/types.ItemCategory"
-                },
-                "created_at": {
+                "clerk_id": {
                     "type": "string"
                 },
-                "id": {
+                "email": {
                     "type": "string"
                 },
-                "image_url": {
+                "first_name": {
                     "type": "string"
                 },
-                "is_available": {
-                    "type": "boolean"
-                },
-                "max_quantity": {
-                    "type": "integer"
-                },
-                "name": {
+                "last_name": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
-                },
-                "status": {
-                    "$ref": "#/definitions/types.ItemStatus"
-                },
-                "type": {
-                    "$ref": "#/definitions/types.ItemType"
-                },
-                "unit": {
+                "provider": {
                     "type": "string"
                 },
-                "updated_at": {
+                "role": {
                     "type": "string"
                 }
             }
         },
-        "types.ItemCategory": {
-            "type": "string",
-            "enum": [
-                "GENERAL",
-                "ELECTRONICS",
-                "FURNITURE",
-                "APPLIANCES",
-                "VEHICLES",
-                "OTHER"
-            ],
-            "x-enum-varnames": [
-                "CategoryGeneral",
-                "CategoryElectronics",
-                "CategoryFurniture",
-                "CategoryAppliances",
-                "CategoryVehicles",
-                "CategoryOther"
-            ]
-        },
-        "types.ItemStatus": {
-            "type": "string",
-            "enum": [
-                "HIGH",
-                "LOW",
-                "DANGER",
-                "OUT_OF_STOCK"
-            ],
-            "x-enum-varnames": [
-                "ItemStatusHigh",
-                "ItemStatusLow",
-                "ItemStatusDanger",
-                "ItemStatusOutOfStock"
-            ]
-        },
-        "types.ItemType": {
-            "type": "string",
-            "enum": [
-                "RESOURCE",
-                "EQUIPMENT"
-            ],
-            "x-enum-varnames": [
-                "ItemTypeResource",
-                "ItemTypeEquipment"
-            ]
-        },
-        "types.MainServiceType": {
-            "type": "string",
-            "enum": [
-                "SERVICE_TYPE_UNSPECIFIED",
-                "GENERAL_CLEANING",
-                "COUCH",
-                "MATTRESS",
-                "CAR",
-                "POST"
-            ],
-            "x-enum-varnames": [
-                "ServiceTypeUnspecified",
-                "GeneralCleaning",
-                "CouchCleaning",
-                "MattressCleaning",
-                "CarCleaning",
-                "PostCleaning"
-            ]
-        },
-        "types.MattressCleaningDetails": {
+        "types.SignUpCustomerResponse": {
             "type": "object",
             "properties": {
-                "cleaningSpecs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.MattressCleaningSpecifications"
-                    }
+                "customer": {
+                    "$ref": "#/definitions/types.Customer"
                 }
             }
         },
-        "types.MattressCleaningSpecifications": {
+        "types.SignUpEmployeeRequest": {
             "type": "object",
+            "required": [
+                "clerk_id",
+                "email",
+                "first_name",
+                "hire_date",
+                "last_name",
+                "position",
+                "provider",
+                "role"
+            ],
             "properties": {
-                "bedType": {
+                "clerk_id": {
                     "type": "string"
                 },
-                "depthCm": {
-                    "type": "integer"
+                "email": {
+                    "type": "string"
                 },
-                "heightCm": {
-                    "type": "integer"
+                "first_name": {
+                    "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "hire_date": {
+                    "type": "string"
                 },
-                "widthCm": {
-                    "type": "integer"
+                "last_name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
                 }
             }
         },
-        "types.PostConstructionDetails": {
+        "types.SignUpEmployeeResponse": {
             "type": "object",
             "properties": {
-                "sqm": {
-                    "type": "integer"
+                "employee": {
+                    "$ref": "#/definitions/types.Employee"
                 }
             }
         },
-        "types.QuoteRequest": {
+        "types.StockMovement": {
             "type": "object",
             "properties": {
-                "addons": {
-                    "description": "same here",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.AddOnRequest"
-                    }
-                },
-                "customerId": {
+                "actor": {
                     "type": "string"
                 },
-                "service": {
-                    "description": "nested structs usually don't need db tags",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/types.ServicesRequest"
-                        }
-                    ]
-                }
-            }
-        },
-        "types.QuoteResponse": {
-            "type": "object",
-            "properties": {
-                "addonTotal": {
-                    "type": "number"
+                "created_at": {
+                    "type": "string"
                 },
-                "addons": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.AddOnBreakdown"
-                    }
+                "id": {
+                    "type": "string"
                 },
-                "mainServiceName": {
+                "item_id": {
                     "type": "string"
                 },
-                "mainServiceTotal": {
-                    "type": "number"
+                "quantity": {
+                    "type": "integer"
                 },
-                "quote_id": {
+                "reason": {
                     "type": "string"
                 },
-                "totalPrice": {
-                    "type": "number"
+                "reference_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/types.MovementType"
+                }
+            }
+        },
+        "types.StockMovementResponse": {
+            "type": "object",
+            "properties": {
+                "item": {
+                    "$ref": "#/definitions/types.InventoryItem"
+                },
+                "movement": {
+                    "$ref": "#/definitions/types.StockMovement"
                 }
             }
         },
-        "types.QuotesResponse": {
+        "types.StockMovementsResponse": {
             "type": "object",
             "properties": {
-                "quotes": {
+                "item_id": {
+                    "type": "string"
+                },
+                "ledger_quantity": {
+                    "type": "integer"
+                },
+                "movements": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.QuoteResponse"
+                        "$ref": "#/definitions/types.StockMovement"
                     }
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reserved": {
+                    "type": "integer"
                 }
             }
         },
-        "types.ServiceDetail": {
+        "types.TaxBreakdown": {
             "type": "object",
             "properties": {
-                "car": {
-                    "$ref": "#/definitions/types.CarCleaningDetails"
+                "gross": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "couch": {
-                    "$ref": "#/definitions/types.CouchCleaningDetails"
+                "net": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "general": {
-                    "$ref": "#/definitions/types.GeneralCleaningDetails"
+                "pricesIncludeTax": {
+                    "type": "boolean"
                 },
-                "mattress": {
-                    "$ref": "#/definitions/types.MattressCleaningDetails"
+                "rateBasisPoints": {
+                    "type": "integer"
                 },
-                "post": {
-                    "$ref": "#/definitions/types.PostConstructionDetails"
+                "status": {
+                    "$ref": "#/definitions/types.TaxStatus"
+                },
+                "tax": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 }
             }
         },
-        "types.ServiceDetails": {
+        "types.TaxStatus": {
+            "type": "string",
+            "enum": [
+                "VATABLE",
+                "EXEMPT",
+                "ZERO_RATED"
+            ],
+            "x-enum-varnames": [
+                "TaxVatable",
+                "TaxExempt",
+                "TaxZeroRated"
+            ]
+        },
+        "types.TimePricingRule": {
             "type": "object",
             "properties": {
-                "details": {},
-                "id": {
+                "code": {
                     "type": "string"
                 },
-                "serviceType": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "endHour": {
+                    "type": "integer"
+                },
+                "flat": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "label": {
                     "type": "string"
-                }
-            }
-        },
-        "types.ServicesRequest": {
-            "type": "object",
-            "properties": {
-                "details": {
-                    "$ref": "#/definitions/types.ServiceDetail"
                 },
-                "serviceType": {
-                    "$ref": "#/definitions/types.MainServiceType"
+                "percent": {
+                    "type": "integer"
+                },
+                "startHour": {
+                    "type": "integer"
                 }
             }
         },
-        "types.SignUpCustomerRequest": {
+        "types.Transaction": {
             "type": "object",
-            "required": [
-                "clerk_id",
-                "email",
-                "first_name",
-                "last_name",
-                "provider",
-                "role"
-            ],
             "properties": {
-                "clerk_id": {
+                "amount": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                },
+                "bookingId": {
                     "type": "string"
                 },
-                "email": {
+                "createdAt": {
                     "type": "string"
                 },
-                "first_name": {
+                "failureReason": {
                     "type": "string"
                 },
-                "last_name": {
+                "id": {
+                    "type": "string"
+                },
+                "idempotencyKey": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/types.TransactionKind"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "paymentId": {
+                    "description": "the payment a refund returns",
                     "type": "string"
                 },
                 "provider": {
                     "type": "string"
                 },
-                "role": {
+                "providerRef": {
+                    "type": "string"
+                },
+                "reason": {
+                    "$ref": "#/definitions/types.RefundReason"
+                },
+                "requestKey": {
+                    "description": "idempotency key of the refund request that made a refund",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/types.TransactionStatus"
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "types.SignUpCustomerResponse": {
+        "types.TransactionKind": {
+            "type": "string",
+            "enum": [
+                "PAYMENT",
+                "REFUND"
+            ],
+            "x-enum-varnames": [
+                "TransactionPayment",
+                "TransactionRefund"
+            ]
+        },
+        "types.TransactionStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "SUCCEEDED",
+                "FAILED"
+            ],
+            "x-enum-varnames": [
+                "TransactionPending",
+                "TransactionSucceeded",
+                "TransactionFailed"
+            ]
+        },
+        "types.TransitionBookingRequest": {
             "type": "object",
+            "required": [
+                "status"
+            ],
             "properties": {
-                "customer": {
-                    "$ref": "#/definitions/types.Customer"
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/types.BookingStatus"
                 }
             }
         },
-        "types.SignUpEmployeeRequest": {
+        "types.TravelBand": {
             "type": "object",
-            "required": [
-                "clerk_id",
-                "email",
-                "first_name",
-                "hire_date",
-                "last_name",
-                "position",
-                "provider",
-                "role"
-            ],
             "properties": {
-                "clerk_id": {
-                    "type": "string"
+                "fee": {
+                    "$ref": "#/definitions/types.MoneyJSON"
                 },
-                "email": {
-                    "type": "string"
+                "maxKm": {
+                    "type": "number"
                 },
-                "first_name": {
+                "perKm": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                }
+            }
+        },
+        "types.TravelQuote": {
+            "type": "object",
+            "properties": {
+                "depotId": {
                     "type": "string"
                 },
-                "hire_date": {
+                "depotName": {
                     "type": "string"
                 },
-                "last_name": {
-                    "type": "string"
+                "distanceMeters": {
+                    "type": "integer"
                 },
-                "position": {
+                "fee": {
+                    "$ref": "#/definitions/types.MoneyJSON"
+                }
+            }
+        },
+        "types.UpdateBookingRequest": {
+            "type": "object",
+            "properties": {
+                "addons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.AddOnRequest"
+                    }
+                },
+                "address": {
+                    "$ref": "#/definitions/types.Address"
+                },
+                "dirtyScale": {
+                    "type": "integer"
+                },
+                "endSched": {
                     "type": "string"
                 },
-                "provider": {
+                "modifiers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "photos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quoteId": {
+                    "description": "quote for the edited job; required when the price changes",
                     "type": "string"
                 },
-                "role": {
+                "startSched": {
                     "type": "string"
                 }
             }
         },
-        "types.SignUpEmployeeResponse": {
+        "types.UpdateBookingResponse": {
             "type": "object",
             "properties": {
-                "employee": {
-                    "$ref": "#/definitions/types.Employee"
+                "booking": {
+                    "$ref": "#/definitions/types.Booking"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BookingChange"
+                    }
+                },
+                "reallocated": {
+                    "type": "boolean"
                 }
             }
         },
@@ -2061,6 +5359,29 @@ const docTemplate = `{
                 }
             }
         },
+        "types.UpdateCustomerTaxRequest": {
+            "type": "object",
+            "required": [
+                "tax_status"
+            ],
+            "properties": {
+                "tax_id": {
+                    "type": "string"
+                },
+                "tax_status": {
+                    "enum": [
+                        "VATABLE",
+                        "EXEMPT",
+                        "ZERO_RATED"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.TaxStatus"
+                        }
+                    ]
+                }
+            }
+        },
         "types.UpdateEmployeeRequest": {
             "type": "object",
             "required": [
@@ -2138,11 +5459,13 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "quantity": {
+                    "description": "optional, a physical count recorded as an ADJUST movement",
                     "type": "integer"
                 },
-                "status": {
-                    "description": "HIGH / LOW / DANGER / OUT_OF_STOCK",
-                    "type": "string"
+                "reorder_point": {
+                    "description": "optional",
+                    "type": "integer",
+                    "minimum": 0
                 },
                 "type": {
                     "description": "optional",
@@ -2175,6 +5498,113 @@ const docTemplate = `{
                     "type": "boolean"
                 }
             }
+        },
+        "types.VoidInvoiceRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.WebhookEvent": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "data": {
+                    "$ref": "#/definitions/types.WebhookEventData"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/types.WebhookEventType"
+                }
+            }
+        },
+        "types.WebhookEventData": {
+            "type": "object",
+            "properties": {
+                "failureReason": {
+                    "type": "string"
+                },
+                "objectId": {
+                    "description": "payment intent or refund id",
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "our transaction id",
+                    "type": "string"
+                }
+            }
+        },
+        "types.WebhookEventType": {
+            "type": "string",
+            "enum": [
+                "payment.succeeded",
+                "payment.failed",
+                "refund.succeeded",
+                "refund.failed"
+            ],
+            "x-enum-varnames": [
+                "EventPaymentSucceeded",
+                "EventPaymentFailed",
+                "EventRefundSucceeded",
+                "EventRefundFailed"
+            ]
+        },
+        "types.WebhookOutcome": {
+            "type": "string",
+            "enum": [
+                "APPLIED",
+                "UNCHANGED",
+                "UNMATCHED",
+                "IGNORED",
+                "DISCREPANCY"
+            ],
+            "x-enum-comments": {
+                "WebhookDiscrepancy": "contradicts how the transaction was settled; kept for review, not applied",
+                "WebhookIgnored": "an event type we do not act on",
+                "WebhookUnchanged": "the transaction was already settled that way",
+                "WebhookUnmatched": "no transaction of ours"
+            },
+            "x-enum-descriptions": [
+                "",
+                "the transaction was already settled that way",
+                "no transaction of ours",
+                "an event type we do not act on",
+                "contradicts how the transaction was settled; kept for review, not applied"
+            ],
+            "x-enum-varnames": [
+                "WebhookApplied",
+                "WebhookUnchanged",
+                "WebhookUnmatched",
+                "WebhookIgnored",
+                "WebhookDiscrepancy"
+            ]
+        },
+        "types.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "duplicate": {
+                    "description": "already received; nothing was applied this time",
+                    "type": "boolean"
+                },
+                "eventId": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "$ref": "#/definitions/types.WebhookOutcome"
+                },
+                "transactionId": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -11,6 +11,7 @@ func AccountEndpoint(r* gin.RouterGroup, h * handlers.AccountHandler){
 		customer.POST("/signup", h.SignUpCustomer)
 		customer.GET("/:id", h.GetCustomer)
 		customer.PUT("/:id", h.UpdateCustomer)
+		customer.PUT("/:id/tax", h.UpdateCustomerTax)
 		// Route should be like this in your router:
 		customer.DELETE("/:id/:accId", h.DeleteCustomer)
 
@@ -28,23 +29,52 @@ func AccountEndpoint(r* gin.RouterGroup, h * handlers.AccountHandler){
 }
 func InventoryEndpoint(r* gin.RouterGroup, h * handlers.InventoryHandler){
 	r.POST("/", h.CreateItem)
+	r.GET("/reorder-suggestions", h.GetReorderSuggestions)
 	r.GET("/:id", h.GetItem)
 	r.GET("/", h.GetItems)
-	r.GET("/type/:type", h.ListItemsByType)
-	r.GET("/status/:status", h.ListItemsByStatus)
-	r.GET("/category/:category", h.ListItemsByCategory)
 	r.PUT("/", h.UpdateItem)
 	r.DELETE("/:id", h.DeleteItem)
+	r.POST("/:id/movements", h.RecordMovement)
+	r.GET("/:id/movements", h.GetMovements)
 }
 func BookingEndpoint(r* gin.RouterGroup, h * handlers.BookingHandler){
 	r.POST("/", h.CreateBooking)
 	r.GET("/id/:id", h.GetBookingById)
 	r.GET("/uid/:uid", h.GetBookingByUId)
 	r.PUT("/:id", h.UpdateBooking)
+	r.PUT("/:id/status", h.TransitionBooking)
+	r.GET("/:id/history", h.GetBookingHistory)
 	r.DELETE("/:id", h.DeleteBooking)
 }
 func PaymentEndpoint(r* gin.RouterGroup, h * handlers.PaymentHandler){
 	r.POST("/quote", h.MakeQuotation)
 	r.POST("/quote/preview", h.MakePublicQuotation)
+	r.GET("/quote/:id", h.GetQuote)
 	r.GET("/quotes/:customerId", h.GetAllQuotesFromCustomer)
+	r.GET("/catalogs", h.GetCatalogs)
+	r.GET("/catalogs/active", h.GetActiveCatalog)
+	r.GET("/catalogs/:id", h.GetCatalog)
+	r.POST("/catalogs", h.CreateCatalog)
+	r.PUT("/catalogs/:id", h.UpdateCatalog)
+	r.DELETE("/catalogs/:id", h.DeleteCatalog)
+	r.GET("/promotions", h.GetPromotions)
+	r.POST("/promotions", h.CreatePromotion)
+	r.PUT("/promotions/:id", h.UpdatePromotion)
+	r.DELETE("/promotions/:id", h.DeactivatePromotion)
+	r.GET("/depots", h.GetDepots)
+	r.POST("/depots", h.CreateDepot)
+	r.PUT("/depots/:id", h.UpdateDepot)
+	r.DELETE("/depots/:id", h.DeactivateDepot)
+	r.GET("/holidays", h.GetHolidays)
+	r.POST("/holidays", h.SaveHoliday)
+	r.DELETE("/holidays/:date", h.DeleteHoliday)
+	r.GET("/invoices/:id", h.GetInvoice)
+	r.GET("/invoices/:id/pdf", h.GetInvoicePDF)
+	r.POST("/invoices/:id/void", h.VoidInvoice)
+	r.GET("/booking/:id/invoice", h.GetBookingInvoice)
+	r.POST("/booking/:id/pay", h.PayBooking)
+	r.POST("/booking/:id/refund", h.RefundBooking)
+	r.GET("/booking/:id/balance", h.GetBookingBalance)
+	r.GET("/booking/:id/transactions", h.GetBookingTransactions)
+	r.POST("/webhook", h.PaymentWebhook)
 }
\ No newline at end of file
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"handworks-api/types"
 	"net/http"
 	"time"
@@ -92,6 +93,41 @@ func (h *AccountHandler) UpdateCustomer(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// UpdateCustomerTax godoc
+// @Summary Set a customer's VAT status
+// @Description Mark a customer VATABLE, EXEMPT or ZERO_RATED. Exempt and zero-rated customers need a tax id (TIN). Applies to quotes made afterwards.
+// @Security BearerAuth
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param input body types.UpdateCustomerTaxRequest true "Tax status"
+// @Success 200 {object} types.UpdateCustomerResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /account/customer/{id}/tax [put]
+func (h *AccountHandler) UpdateCustomerTax(c *gin.Context) {
+	var req types.UpdateCustomerTaxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.ID = c.Param("id")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := h.Service.UpdateCustomerTax(ctx, req)
+	if errors.Is(err, types.ErrTaxIDRequired) {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // DeleteCustomer godoc
 // @Summary Delete a customer
 // @Description Remove a customer by ID
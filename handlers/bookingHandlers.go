@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"handworks-api/types"
 	"net/http"
 	"time"
@@ -11,7 +12,7 @@ import (
 
 // CreateBooking godoc
 // @Summary Create a new booking
-// @Description Creates a booking record
+// @Description Creates a booking record and consumes its quote. The main service and addons are re-priced with the quote's catalog and must match what was quoted, as must the dirty scale, modifiers, address and the depot serving it; a mismatch fails with 409 and a diff listing each differing item. The booking's total is the quote's main service, addons and adjustment lines with tax. Unknown quotes fail with 404, another customer's with 403, already used ones or ones priced for a different time window than startSched with 409, and expired ones with 410.
 // @Tags Booking
 // @Security BearerAuth
 // @Accept json
@@ -19,6 +20,10 @@ import (
 // @Param input body types.CreateBookingRequest true "Booking info"
 // @Success 200 {object} types.Booking
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 410 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /booking [post]
 func (h *BookingHandler) CreateBooking(c *gin.Context) {
@@ -32,7 +37,7 @@ func (h *BookingHandler) CreateBooking(c *gin.Context) {
 	defer cancel()
 	res, err := h.Service.CreateBooking(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
+		c.JSON(bookingErrorStatus(err), types.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -40,61 +45,216 @@ func (h *BookingHandler) CreateBooking(c *gin.Context) {
 
 // GetBookingById godoc
 // @Summary Get booking by ID
-// @Description Retrieve booking information by its database ID
+// @Description Retrieve a fully hydrated booking by its database ID
 // @Tags Booking
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Booking ID"
-// @Success 200 {object} map[string]interface{}
-// @Router /booking/{id} [get]
+// @Success 200 {object} types.Booking
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /booking/id/{id} [get]
 func (h *BookingHandler) GetBookingById(c *gin.Context) {
-	_ = h.Service.GetBookingById(c.Request.Context())
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
+	id := c.Param("id")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetBookingById(ctx, id)
+	if err != nil {
+		c.JSON(bookingErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
 }
 
 // GetBookingByUId godoc
-// @Summary Get booking by user ID
-// @Description Retrieve all bookings for a specific user
+// @Summary Get bookings by customer ID
+// @Description Retrieve a paginated list of a customer's bookings, newest schedule first
 // @Tags Booking
 // @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param uid path string true "User ID"
-// @Success 200 {array} map[string]interface{}
-// @Router /booking/user/{uid} [get]
+// @Param uid path string true "Customer ID"
+// @Param startDate query string false "Earliest schedule date (YYYY-MM-DD)"
+// @Param endDate query string false "Latest schedule date (YYYY-MM-DD)"
+// @Param paymentStatus query string false "Payment status"
+// @Param reviewStatus query string false "Review status"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} types.BookingsResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /booking/uid/{uid} [get]
 func (h *BookingHandler) GetBookingByUId(c *gin.Context) {
-	_ = h.Service.GetBookingByUId(c.Request.Context())
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
+	var filter types.BookingFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	filter.CustID = c.Param("uid")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetBookingByUId(ctx, filter)
+	if err != nil {
+		c.JSON(bookingErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
 }
 
 // UpdateBooking godoc
 // @Summary Update a booking
-// @Description Update booking information
+// @Description Reschedule or partially update a booking. Schedule changes re-run allocation against the linked quote. Moving the address and dirty scale, modifier and addon changes affect the price, so they need a new quote for the edited job in quoteId; without one they fail with 409. A new address must be covered by the depot and travel fee the quote was priced with. The new quote is checked against the edited booking like on creation, with a diff on mismatch, and consumed, and its promo code replaces the old quote's. A new start outside the time window the quote was priced for fails with 409.
 // @Tags Booking
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Booking ID"
-// @Param input body map[string]interface{} true "Updated booking info"
-// @Success 200 {object} map[string]string
+// @Param input body types.UpdateBookingRequest true "Fields to change"
+// @Success 200 {object} types.UpdateBookingResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
 // @Router /booking/{id} [put]
 func (h *BookingHandler) UpdateBooking(c *gin.Context) {
-	_ = h.Service.UpdateBooking(c.Request.Context())
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
+	var req types.UpdateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.ID = c.Param("id")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.UpdateBooking(ctx, req)
+	if err != nil {
+		c.JSON(bookingErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
 }
 
 // DeleteBooking godoc
-// @Summary Delete a booking
-// @Description Remove booking by ID
+// @Summary Cancel a booking
+// @Description Marks the booking CANCELLED, releases its cleaners, equipment and resources, and refunds the cancellation policy's share of what was captured for it through the payment provider, with a CANCELLATION reason. Unpaid bookings get nothing back. A refund that fails does not undo the cancellation; it is reported in refundError and can be retried with the booking refund endpoint.
 // @Tags Booking
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Booking ID"
-// @Success 200 {object} map[string]string
+// @Param input body types.CancelBookingRequest false "Cancellation reason"
+// @Success 200 {object} types.CancelBookingResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
 // @Router /booking/{id} [delete]
 func (h *BookingHandler) DeleteBooking(c *gin.Context) {
-	_ = h.Service.DeleteBooking(c.Request.Context())
-	c.JSON(http.StatusOK, gin.H{"status": "success"})
-}
\ No newline at end of file
+	var req types.CancelBookingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+			return
+		}
+	}
+	req.ID = c.Param("id")
+	req.CancelledBy = actorFromContext(c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.CancelBooking(ctx, req)
+	if err != nil {
+		c.JSON(bookingErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// TransitionBooking godoc
+// @Summary Change booking status
+// @Description Move a booking along PENDING → CONFIRMED → EN_ROUTE → IN_PROGRESS → COMPLETED, or to CANCELLED / NO_SHOW. Illegal moves are rejected with 409. Dispatching (EN_ROUTE) before the deposit is paid is rejected with 402. Completing a booking issues its invoice.
+// @Tags Booking
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param input body types.TransitionBookingRequest true "Target status"
+// @Success 200 {object} types.BookingStatusChange
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /booking/{id}/status [put]
+func (h *BookingHandler) TransitionBooking(c *gin.Context) {
+	var req types.TransitionBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.ID = c.Param("id")
+	req.Actor = actorFromContext(c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.TransitionBooking(ctx, req)
+	if err != nil {
+		c.JSON(bookingErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetBookingHistory godoc
+// @Summary Get booking status history
+// @Description Retrieve the current status of a booking and its status trail with actor and timestamp
+// @Tags Booking
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {object} types.BookingStatusHistoryResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /booking/{id}/history [get]
+func (h *BookingHandler) GetBookingHistory(c *gin.Context) {
+	id := c.Param("id")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetBookingHistory(ctx, id)
+	if err != nil {
+		c.JSON(bookingErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// bookingErrorStatus maps booking lifecycle errors to HTTP status codes.
+func bookingErrorStatus(err error) int {
+	var (
+		illegal    *types.IllegalTransitionError
+		noCapacity *types.NoCapacityError
+		noStock    *types.InsufficientStockError
+		mismatch   *types.QuoteMismatchError
+	)
+	switch {
+	case errors.As(err, &illegal),
+		errors.As(err, &noCapacity),
+		errors.As(err, &noStock),
+		errors.As(err, &mismatch),
+		errors.Is(err, types.ErrBookingCancelled),
+		errors.Is(err, types.ErrBookingNotEditable),
+		errors.Is(err, types.ErrQuoteConsumed),
+		errors.Is(err, types.ErrQuoteSchedule),
+		errors.Is(err, types.ErrQuoteRequired),
+		errors.Is(err, types.ErrPromoExhausted):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrQuoteExpired):
+		return http.StatusGone
+	case errors.Is(err, types.ErrDepositRequired):
+		return http.StatusPaymentRequired
+	case errors.Is(err, types.ErrQuoteNotOwned):
+		return http.StatusForbidden
+	case errors.Is(err, types.ErrInvalidBookingFilter),
+		errors.Is(err, types.ErrInvalidBookingUpdate):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrQuoteNotFound),
+		errors.Is(err, types.ErrBookingNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
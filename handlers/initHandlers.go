@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"handworks-api/middleware"
 	"handworks-api/services"
 	"handworks-api/utils"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/gin-gonic/gin"
 )
 
 // --- Account Handler ---
@@ -56,3 +60,13 @@ func NewPaymentHandler(service *services.PaymentService, logger *utils.Logger) *
 		Logger:  logger,
 	}
 }
+
+// actorFromContext returns the Clerk user id of the caller, or "" on public routes.
+func actorFromContext(c *gin.Context) string {
+	if v, ok := c.Get(string(middleware.ClerkClaimsKey)); ok {
+		if claims, ok := v.(*clerk.SessionClaims); ok {
+			return claims.Subject
+		}
+	}
+	return ""
+}
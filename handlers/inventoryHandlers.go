@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"handworks-api/types"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -60,144 +63,201 @@ func (h *InventoryHandler) GetItem(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
-// GetItem godoc
-// @Summary Get all items
-// @Description Retrieve all inventory items
+// GetItems godoc
+// @Summary Search inventory items
+// @Description Lists inventory items matching every given filter, one page at a time. Pass next_cursor from a response as cursor to get the next page.
 // @Security BearerAuth
 // @Tags Inventory
 // @Produce json
-// @Success 200 {object} []types.InventoryItem
-// @Failure 404 {object} types.ErrorResponse
+// @Param type query string false "Item types, comma separated (RESOURCE, EQUIPMENT)"
+// @Param status query string false "Stock statuses, comma separated (HIGH, LOW, DANGER, OUT_OF_STOCK)"
+// @Param category query string false "Categories, comma separated (GENERAL, ELECTRONICS, ...)"
+// @Param available query bool false "Only available or unavailable items"
+// @Param name query string false "Case-insensitive name substring"
+// @Param quantity_min query int false "Minimum quantity"
+// @Param quantity_max query int false "Maximum quantity"
+// @Param sort query string false "name, quantity, status, created_at or updated_at; prefix with - for descending (default -created_at)"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size (default 25, max 100)"
+// @Success 200 {object} types.InventoryItemsResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
 // @Router /inventory/ [get]
 func (h *InventoryHandler) GetItems(c *gin.Context) {
+	var filter types.InventoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	resp, err := h.Service.GetItems(ctx)
+	resp, err := h.Service.GetItems(ctx, filter)
 	if err != nil {
-		c.JSON(http.StatusNotFound, types.NewErrorResponse(err))
+		c.JSON(inventoryErrorStatus(err), types.NewErrorResponse(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
-// ListItemsByType godoc
-// @Summary List items filtered by type
-// @Description Get all inventory items matching the given type
+
+// UpdateItem godoc
+// @Summary Update an inventory item
+// @Description Modify fields of an existing inventory item. A new quantity is treated as a stock count and recorded as an ADJUST movement. Status is recomputed from quantity and max quantity.
 // @Security BearerAuth
 // @Tags Inventory
+// @Accept json
 // @Produce json
-// @Param type path string true "Item type (resource, equipment)"
-// @Success 200 {object} []types.InventoryItem
+// @Param input body types.UpdateItemRequest true "Updated item info"
+// @Success 200 {object} types.InventoryItem
 // @Failure 400 {object} types.ErrorResponse
-// @Router /inventory/type/{type} [get]
-func (h *InventoryHandler) ListItemsByType(c *gin.Context) {
-	itemType := c.Param("type")
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /inventory/ [put]
+func (h *InventoryHandler) UpdateItem(c *gin.Context) {
+	var req types.UpdateItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.Actor = actorFromContext(c)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	resp, err := h.Service.ListItemsByType(ctx, itemType)
+	resp, err := h.Service.UpdateItem(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		c.JSON(inventoryErrorStatus(err), types.NewErrorResponse(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
-// ListItemsByStatus godoc
-// @Summary List items filtered by status
-// @Description Get all inventory items with the given stock status
+// DeleteItem godoc
+// @Summary Delete an item
+// @Description Remove inventory item by ID
 // @Security BearerAuth
 // @Tags Inventory
 // @Produce json
-// @Param status path string true "Status (high, low, danger, out_of_stock)"
+// @Param id path string true "Item ID"
 // @Success 200 {object} []types.InventoryItem
-// @Failure 400 {object} types.ErrorResponse
-// @Router /inventory/status/{status} [get]
-func (h *InventoryHandler) ListItemsByStatus(c *gin.Context) {
-	status := c.Param("status")
+// @Failure 500 {object} types.ErrorResponse
+// @Router /inventory/{id} [delete]
+func (h *InventoryHandler) DeleteItem(c *gin.Context) {
+	id := c.Param("id")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	resp, err := h.Service.ListItemsByStatus(ctx, status)
+	resp, err := h.Service.DeleteItem(ctx, id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
-// ListItemsByCategory godoc
-// @Summary List items filtered by category
-// @Description Retrieve all inventory items in a category
+// RecordMovement godoc
+// @Summary Record a stock movement
+// @Description Adds a RESTOCK, CONSUME, ADJUST, DAMAGE or RETURN movement to an item's ledger and applies it to its quantity. Quantity is positive except for ADJUST, which is signed. RESERVE movements are only made by bookings.
 // @Security BearerAuth
 // @Tags Inventory
+// @Accept json
 // @Produce json
-// @Param category path string true "Category (general, electronics, furniture, etc)"
-// @Success 200 {object} []types.InventoryItem
+// @Param id path string true "Item ID"
+// @Param input body types.CreateStockMovementRequest true "Movement"
+// @Success 200 {object} types.StockMovementResponse
 // @Failure 400 {object} types.ErrorResponse
-// @Router /inventory/category/{category} [get]
-func (h *InventoryHandler) ListItemsByCategory(c *gin.Context) {
-	category := c.Param("category")
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /inventory/{id}/movements [post]
+func (h *InventoryHandler) RecordMovement(c *gin.Context) {
+	var req types.CreateStockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.ItemID = c.Param("id")
+	req.Actor = actorFromContext(c)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	resp, err := h.Service.ListItemsByCategory(ctx, category)
+	resp, err := h.Service.RecordMovement(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		c.JSON(inventoryErrorStatus(err), types.NewErrorResponse(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
-// UpdateItem godoc
-// @Summary Update an inventory item
-// @Description Modify fields of an existing inventory item
+// GetMovements godoc
+// @Summary Get an item's stock movements
+// @Description Returns the item's movement ledger, newest first, with the quantity the ledger adds up to and the stock still reserved for bookings
 // @Security BearerAuth
 // @Tags Inventory
-// @Accept json
 // @Produce json
-// @Param input body types.UpdateItemRequest true "Updated item info"
-// @Success 200 {object} types.InventoryItem
+// @Param id path string true "Item ID"
+// @Param limit query int false "Maximum number of movements (1-500, default 100)"
+// @Success 200 {object} types.StockMovementsResponse
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
-// @Router /inventory/ [put]
-func (h *InventoryHandler) UpdateItem(c *gin.Context) {
-	var req types.UpdateItemRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
-		return
+// @Router /inventory/{id}/movements [get]
+func (h *InventoryHandler) GetMovements(c *gin.Context) {
+	id := c.Param("id")
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			err = fmt.Errorf("%w: limit must be a number", types.ErrInvalidInventoryFilter)
+			c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+			return
+		}
+		limit = parsed
 	}
-	
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	resp, err := h.Service.UpdateItem(ctx, req)
+	resp, err := h.Service.GetMovements(ctx, id, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
+		c.JSON(inventoryErrorStatus(err), types.NewErrorResponse(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
 
-// DeleteItem godoc
-// @Summary Delete an item
-// @Description Remove inventory item by ID
+// GetReorderSuggestions godoc
+// @Summary Get reorder suggestions
+// @Description Lists items whose available stock is at or below their reorder point, with an order quantity sized from consumption over the last `days` days and capped at the item's max quantity
 // @Security BearerAuth
 // @Tags Inventory
 // @Produce json
-// @Param id path string true "Item ID"
-// @Success 200 {object} []types.InventoryItem
+// @Param days query int false "Consumption window in days (default 30)"
+// @Success 200 {object} types.ReorderSuggestionsResponse
 // @Failure 500 {object} types.ErrorResponse
-// @Router /inventory/{id} [delete]
-func (h *InventoryHandler) DeleteItem(c *gin.Context) {
-	id := c.Param("id")
+// @Router /inventory/reorder-suggestions [get]
+func (h *InventoryHandler) GetReorderSuggestions(c *gin.Context) {
+	days, _ := strconv.Atoi(c.Query("days"))
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	resp, err := h.Service.DeleteItem(ctx, id)
+	resp, err := h.Service.GetReorderSuggestions(ctx, days)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
-}
\ No newline at end of file
+}
+
+// inventoryErrorStatus maps stock ledger errors to their HTTP status.
+func inventoryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrInvalidStockMovement), errors.Is(err, types.ErrInvalidInventoryFilter):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrNegativeStock):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrItemNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
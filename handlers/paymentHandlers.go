@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"handworks-api/types"
+	"handworks-api/utils"
 	"net/http"
 	"time"
 
@@ -11,7 +14,7 @@ import (
 
 // MakeQuotation godoc
 // @Summary Create a quotation
-// @Description Generate a new quotation for a customer
+// @Description Generate a new quotation for a customer. Surcharges for the dirtyScale and modifiers (codes from the active catalog), time window rules for an optional startSched (PHT, weekday or holiday), the travel fee from the nearest depot and an optional promoCode are applied as adjustment lines. A code that does not apply is rejected with 400, an address outside the service area with 422.
 // @Security BearerAuth
 // @Tags Payment
 // @Accept json
@@ -19,6 +22,7 @@ import (
 // @Param input body types.QuoteRequest true "Quote details"
 // @Success 200 {object} types.QuoteResponse
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 422 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /payment/quote [post]
 func (h *PaymentHandler) MakeQuotation(c *gin.Context) {
@@ -31,20 +35,21 @@ func (h *PaymentHandler) MakeQuotation(c *gin.Context) {
 	defer cancel()
 	res, err := h.Service.MakeQuotation(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
 }
 // MakeQuotation godoc
 // @Summary Create a quotation
-// @Description Generate a new quotation
+// @Description Generate a new quotation. Surcharges for the dirtyScale and modifiers (codes from the active catalog), time window rules for an optional startSched (PHT, weekday or holiday), the travel fee from the nearest depot and an optional promoCode are applied as adjustment lines. A code that does not apply is rejected with 400, an address outside the service area with 422.
 // @Tags Payment
 // @Accept json
 // @Produce json
 // @Param input body types.QuoteRequest true "Quote details"
 // @Success 200 {object} types.QuoteResponse
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 422 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /payment/quote/preview [post]
 func (h *PaymentHandler) MakePublicQuotation(c *gin.Context) {
@@ -57,7 +62,7 @@ func (h *PaymentHandler) MakePublicQuotation(c *gin.Context) {
 	defer cancel()
 	res, err := h.Service.MakePublicQuotation(ctx, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -66,23 +71,751 @@ func (h *PaymentHandler) MakePublicQuotation(c *gin.Context) {
 // GetAllQuotesFromCustomer godoc
 // @Summary Get all quotations for a customer
 // @Security BearerAuth
-// @Description Retrieve all quotations associated with a specific customer
+// @Description Retrieve a paginated list of a customer's quotations, newest first, with their addons
 // @Tags Payment
 // @Accept json
 // @Produce json
-// @Param customer_id path string true "Customer ID"
-// @Success 200 {array} types.QuotesResponse
+// @Param customerId path string true "Customer ID"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} types.QuotesResponse
 // @Failure 400 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
-// @Router /payment/quote/{customerId} [get]
+// @Router /payment/quotes/{customerId} [get]
 func (h *PaymentHandler) GetAllQuotesFromCustomer(c *gin.Context) {
-	customerId := c.Param("customerId")
+	var filter types.QuotesFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	filter.CustomerID = c.Param("customerId")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetAllQuotesFromCustomer(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetQuote godoc
+// @Summary Get a quotation
+// @Security BearerAuth
+// @Description Retrieve a single quotation with its main service and addon details, e.g. to pre-fill a booking
+// @Tags Payment
+// @Produce json
+// @Param id path string true "Quote ID"
+// @Success 200 {object} types.QuoteDetail
+// @Failure 404 {object} types.ErrorResponse
+// @Router /payment/quote/{id} [get]
+func (h *PaymentHandler) GetQuote(c *gin.Context) {
+	id := c.Param("id")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetQuote(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetCatalogs godoc
+// @Summary List price catalog versions
+// @Security BearerAuth
+// @Description Retrieve every price catalog version, newest first
+// @Tags Pricing
+// @Produce json
+// @Success 200 {array} types.PriceCatalog
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/catalogs [get]
+func (h *PaymentHandler) GetCatalogs(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetCatalogs(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetActiveCatalog godoc
+// @Summary Get the active price catalog
+// @Security BearerAuth
+// @Description Retrieve the price catalog version quotes are currently priced with
+// @Tags Pricing
+// @Produce json
+// @Success 200 {object} types.PriceCatalog
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/catalogs/active [get]
+func (h *PaymentHandler) GetActiveCatalog(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetActiveCatalog(ctx)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetCatalog godoc
+// @Summary Get a price catalog version
+// @Security BearerAuth
+// @Tags Pricing
+// @Produce json
+// @Param id path string true "Catalog ID"
+// @Success 200 {object} types.PriceCatalog
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/catalogs/{id} [get]
+func (h *PaymentHandler) GetCatalog(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetCatalog(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// CreateCatalog godoc
+// @Summary Create a price catalog version
+// @Security BearerAuth
+// @Description Adds the next catalog version. It prices new quotes from effectiveFrom (default now), which cannot be in the past.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param input body types.PriceCatalogRequest true "Catalog"
+// @Success 200 {object} types.PriceCatalog
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/catalogs [post]
+func (h *PaymentHandler) CreateCatalog(c *gin.Context) {
+	var req types.PriceCatalogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.Actor = actorFromContext(c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.CreateCatalog(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// UpdateCatalog godoc
+// @Summary Update a scheduled price catalog version
+// @Security BearerAuth
+// @Description Replaces a catalog version that has not taken effect yet. Versions in effect cannot change, since quotes were priced with them.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Catalog ID"
+// @Param input body types.PriceCatalogRequest true "Catalog"
+// @Success 200 {object} types.PriceCatalog
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/catalogs/{id} [put]
+func (h *PaymentHandler) UpdateCatalog(c *gin.Context) {
+	var req types.PriceCatalogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.ID = c.Param("id")
+	req.Actor = actorFromContext(c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.UpdateCatalog(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// DeleteCatalog godoc
+// @Summary Delete a scheduled price catalog version
+// @Security BearerAuth
+// @Description Removes a catalog version that has not taken effect yet
+// @Tags Pricing
+// @Produce json
+// @Param id path string true "Catalog ID"
+// @Success 200 {object} types.PriceCatalog
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/catalogs/{id} [delete]
+func (h *PaymentHandler) DeleteCatalog(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.DeleteCatalog(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetPromotions godoc
+// @Summary List promotions
+// @Security BearerAuth
+// @Description Retrieve every promo code with its redemption count, newest first
+// @Tags Promotions
+// @Produce json
+// @Success 200 {array} types.Promotion
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/promotions [get]
+func (h *PaymentHandler) GetPromotions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetPromotions(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// CreatePromotion godoc
+// @Summary Create a promotion
+// @Security BearerAuth
+// @Description Adds a PERCENT (percentOff) or FIXED (amountOff) promo code. Codes are case-insensitive and unique.
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Param input body types.PromotionRequest true "Promotion"
+// @Success 200 {object} types.Promotion
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/promotions [post]
+func (h *PaymentHandler) CreatePromotion(c *gin.Context) {
+	var req types.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.Actor = actorFromContext(c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.CreatePromotion(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// UpdatePromotion godoc
+// @Summary Update a promotion
+// @Security BearerAuth
+// @Description Replaces a promotion. Quotes already discounted keep their discount.
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Param input body types.PromotionRequest true "Promotion"
+// @Success 200 {object} types.Promotion
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/promotions/{id} [put]
+func (h *PaymentHandler) UpdatePromotion(c *gin.Context) {
+	var req types.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.ID = c.Param("id")
+	req.Actor = actorFromContext(c)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.UpdatePromotion(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// DeactivatePromotion godoc
+// @Summary Deactivate a promotion
+// @Security BearerAuth
+// @Description Stops a promo code from applying to new quotes. It is kept for its redemptions.
+// @Tags Promotions
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} types.Promotion
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/promotions/{id} [delete]
+func (h *PaymentHandler) DeactivatePromotion(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.DeactivatePromotion(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetDepots godoc
+// @Summary List depots
+// @Security BearerAuth
+// @Description Retrieve every depot of the service area, active or not
+// @Tags Service Area
+// @Produce json
+// @Success 200 {array} types.Depot
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/depots [get]
+func (h *PaymentHandler) GetDepots(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetDepots(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// CreateDepot godoc
+// @Summary Create a depot
+// @Security BearerAuth
+// @Description Adds a depot with radius bands ordered by maxKm. Its last band is the edge of its coverage.
+// @Tags Service Area
+// @Accept json
+// @Produce json
+// @Param input body types.DepotRequest true "Depot"
+// @Success 200 {object} types.Depot
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/depots [post]
+func (h *PaymentHandler) CreateDepot(c *gin.Context) {
+	var req types.DepotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.CreateDepot(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// UpdateDepot godoc
+// @Summary Update a depot
+// @Security BearerAuth
+// @Description Replaces a depot. Quotes already priced keep their travel fee.
+// @Tags Service Area
+// @Accept json
+// @Produce json
+// @Param id path string true "Depot ID"
+// @Param input body types.DepotRequest true "Depot"
+// @Success 200 {object} types.Depot
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/depots/{id} [put]
+func (h *PaymentHandler) UpdateDepot(c *gin.Context) {
+	var req types.DepotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.ID = c.Param("id")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.UpdateDepot(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// DeactivateDepot godoc
+// @Summary Deactivate a depot
+// @Security BearerAuth
+// @Description Takes a depot out of the service area. It is kept for the quotes it priced.
+// @Tags Service Area
+// @Produce json
+// @Param id path string true "Depot ID"
+// @Success 200 {object} types.Depot
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/depots/{id} [delete]
+func (h *PaymentHandler) DeactivateDepot(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.DeactivateDepot(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetHolidays godoc
+// @Summary List holidays
+// @Security BearerAuth
+// @Description Retrieve the holiday calendar from today on. Time pricing rules see these dates as HOLIDAY.
+// @Tags Pricing
+// @Produce json
+// @Success 200 {array} types.Holiday
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/holidays [get]
+func (h *PaymentHandler) GetHolidays(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	res, err := h.Service.GetAllQuotesFromCustomer(ctx, customerId)
+	res, err := h.Service.GetHolidays(ctx)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, types.NewErrorResponse(err))
 		return
 	}
 	c.JSON(http.StatusOK, res)
-}
\ No newline at end of file
+}
+
+// SaveHoliday godoc
+// @Summary Add a holiday
+// @Security BearerAuth
+// @Description Adds a date to the holiday calendar, or renames it if it is already there. Quotes already priced keep their time window lines.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param input body types.HolidayRequest true "Holiday"
+// @Success 200 {object} types.Holiday
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/holidays [post]
+func (h *PaymentHandler) SaveHoliday(c *gin.Context) {
+	var req types.HolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.SaveHoliday(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// DeleteHoliday godoc
+// @Summary Remove a holiday
+// @Security BearerAuth
+// @Description Removes a date from the holiday calendar
+// @Tags Pricing
+// @Produce json
+// @Param date path string true "Date (YYYY-MM-DD)"
+// @Success 200 {object} types.Holiday
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/holidays/{date} [delete]
+func (h *PaymentHandler) DeleteHoliday(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.DeleteHoliday(ctx, c.Param("date"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetInvoice godoc
+// @Summary Get an invoice
+// @Security BearerAuth
+// @Description Retrieve an invoice with its lines and credit notes. Invoices are issued when a booking is COMPLETED.
+// @Tags Invoices
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} types.Invoice
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/invoices/{id} [get]
+func (h *PaymentHandler) GetInvoice(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetInvoice(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetInvoicePDF godoc
+// @Summary Download an invoice as PDF
+// @Security BearerAuth
+// @Description Render an invoice as a PDF document
+// @Tags Invoices
+// @Produce application/pdf
+// @Param id path string true "Invoice ID"
+// @Success 200 {file} file
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/invoices/{id}/pdf [get]
+func (h *PaymentHandler) GetInvoicePDF(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	invoice, pdf, err := h.Service.GetInvoicePDF(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, invoice.Number))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// GetBookingInvoice godoc
+// @Summary Get a booking's invoice
+// @Security BearerAuth
+// @Description Retrieve the invoice issued when the booking was completed
+// @Tags Invoices
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {object} types.Invoice
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/booking/{id}/invoice [get]
+func (h *PaymentHandler) GetBookingInvoice(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetBookingInvoice(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// VoidInvoice godoc
+// @Summary Void an invoice
+// @Security BearerAuth
+// @Description Cancels an issued invoice. Its number stays used; paid and already voided invoices are rejected with 409.
+// @Tags Invoices
+// @Accept json
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Param input body types.VoidInvoiceRequest true "Reason"
+// @Success 200 {object} types.Invoice
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/invoices/{id}/void [post]
+func (h *PaymentHandler) VoidInvoice(c *gin.Context) {
+	var req types.VoidInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.ID = c.Param("id")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.VoidInvoice(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// PayBooking godoc
+// @Summary Pay for a booking
+// @Security BearerAuth
+// @Description Charges the balance due, or the given amount, through the payment provider and moves the booking's paymentStatus to PARTIALLY_PAID or PAID. A retry with the same idempotencyKey returns the first attempt. More than the balance due is rejected with 409 and a declined payment with 402.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param input body types.PayBookingRequest true "Payment"
+// @Success 200 {object} types.PaymentResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 402 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/booking/{id}/pay [post]
+func (h *PaymentHandler) PayBooking(c *gin.Context) {
+	var req types.PayBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.BookingID = c.Param("id")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.PayBooking(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// RefundBooking godoc
+// @Summary Refund a booking
+// @Security BearerAuth
+// @Description Returns everything refundable, or the given amount, through the payment provider with a reason code (CANCELLATION, COMPLAINT, OVERCHARGE, DUPLICATE, OTHER). The amount is taken from the newest payments first, one REFUND transaction per payment; more than was captured and not yet refunded is rejected with 409. Each succeeded refund is credited on the booking's invoice with a credit note. A refund that failed at the provider is listed as FAILED, and 502 is returned when all of them failed. A retry with the same idempotencyKey returns the first attempt.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param input body types.RefundBookingRequest true "Refund"
+// @Success 200 {object} types.RefundResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Failure 502 {object} types.ErrorResponse
+// @Router /payment/booking/{id}/refund [post]
+func (h *PaymentHandler) RefundBooking(c *gin.Context) {
+	var req types.RefundBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	req.BookingID = c.Param("id")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.RefundBooking(ctx, req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetBookingBalance godoc
+// @Summary Get a booking's balance
+// @Security BearerAuth
+// @Description Shows the total, what has been paid net of refunds, the outstanding balance and the deposit. amountDue is the unpaid deposit until the booking is completed, then the whole balance. Several partial payments can be made against one booking.
+// @Tags Payment
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {object} types.BookingBalance
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/booking/{id}/balance [get]
+func (h *PaymentHandler) GetBookingBalance(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetBookingBalance(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetBookingTransactions godoc
+// @Summary List a booking's transactions
+// @Security BearerAuth
+// @Description Retrieve the payments and refunds of a booking, oldest first, including failed ones
+// @Tags Payment
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {array} types.Transaction
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/booking/{id}/transactions [get]
+func (h *PaymentHandler) GetBookingTransactions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.GetBookingTransactions(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// PaymentWebhook godoc
+// @Summary Receive a payment provider event
+// @Description Settles a payment or refund reported by the payment provider. The raw body must be signed with HMAC-SHA256 under PAYMENT_WEBHOOK_SECRET, hex encoded in the X-Webhook-Signature header. Each event id is applied once; redeliveries are acknowledged as duplicates. Only pending transactions are settled; an event contradicting a settled one is recorded as a DISCREPANCY for review.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param X-Webhook-Signature header string true "hex HMAC-SHA256 of the body"
+// @Param input body types.WebhookEvent true "Event"
+// @Success 200 {object} types.WebhookResponse
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /payment/webhook [post]
+func (h *PaymentHandler) PaymentWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.NewErrorResponse(err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := h.Service.HandleWebhook(ctx, body, c.GetHeader(utils.WebhookSignatureHeader))
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), types.NewErrorResponse(err))
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// paymentErrorStatus maps pricing and quote errors to their HTTP status.
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, types.ErrInvalidCatalog),
+		errors.Is(err, types.ErrInvalidQuoteRequest),
+		errors.Is(err, types.ErrInvalidPromotion),
+		errors.Is(err, types.ErrInvalidDepot),
+		errors.Is(err, types.ErrInvalidHoliday),
+		errors.Is(err, types.ErrInvalidPayment),
+		errors.Is(err, types.ErrInvalidWebhook),
+		errors.Is(err, types.ErrInvalidRefund),
+		errors.Is(err, types.ErrPromoCodeRejected):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrCatalogNotFound),
+		errors.Is(err, types.ErrQuoteNotFound),
+		errors.Is(err, types.ErrPromotionNotFound),
+		errors.Is(err, types.ErrDepotNotFound),
+		errors.Is(err, types.ErrHolidayNotFound),
+		errors.Is(err, types.ErrInvoiceNotFound),
+		errors.Is(err, types.ErrBookingNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, types.ErrCatalogInEffect),
+		errors.Is(err, types.ErrInvoiceNotVoidable),
+		errors.Is(err, types.ErrOverpayment),
+		errors.Is(err, types.ErrRefundExceedsCaptured),
+		errors.Is(err, types.ErrBookingCancelled):
+		return http.StatusConflict
+	case errors.Is(err, types.ErrPaymentDeclined):
+		return http.StatusPaymentRequired
+	case errors.Is(err, types.ErrRefundFailed):
+		return http.StatusBadGateway
+	case errors.Is(err, types.ErrInvalidSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, types.ErrOutsideServiceArea):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
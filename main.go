@@ -52,12 +52,17 @@ func main() {
 	// public paths for Clerk middleware
 	publicPaths := []string{"/api/account/customer/signup", 
 	"/api/account/employee/signup", 
-	"/api/payment/quote/preview", "/health"}
+	"/api/payment/quote/preview",
+	"/api/payment/webhook", // signed by the payment provider instead
+	"/health"}
 	router.Use(middleware.ClerkAuthMiddleware(publicPaths))
 
 	accountService := services.NewAccountService(conn, logger)
 	inventoryService := services.NewInventoryService(conn, logger)
+	go inventoryService.RunReorderChecker(c, config.NewReorderCheckInterval())
 	paymentService := services.NewPaymentService(conn, logger)
+	go paymentService.RunQuoteSweeper(c, config.NewQuoteSweepInterval())
+	go paymentService.RunPaymentReconciler(c, config.NewPaymentReconcileInterval(), config.NewPendingPaymentTimeout())
 	bookingService := services.NewBookingService(conn, logger, paymentService)
 
 	accountHandler := handlers.NewAccountHandler(accountService, logger)
@@ -0,0 +1,16 @@
+-- Bookings are cancelled rather than deleted so accounting keeps the history.
+ALTER TABLE booking.basebookings
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'PENDING';
+
+CREATE TABLE IF NOT EXISTS booking.cancellations (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id             UUID NOT NULL UNIQUE REFERENCES booking.bookings(id),
+    cancelled_by           TEXT NOT NULL,
+    reason                 TEXT NOT NULL DEFAULT '',
+    refund_percent         INT NOT NULL,
+    refund_amount          REAL NOT NULL,
+    released_equipment_ids UUID[] NOT NULL DEFAULT '{}',
+    released_resource_ids  UUID[] NOT NULL DEFAULT '{}',
+    released_cleaner_ids   UUID[] NOT NULL DEFAULT '{}',
+    cancelled_at           TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
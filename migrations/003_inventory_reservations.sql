@@ -0,0 +1,14 @@
+-- Stock held for bookings. RESERVED rows count against available stock,
+-- CONSUMED rows have been taken off inventory.items.quantity, RELEASED rows are void.
+CREATE TABLE IF NOT EXISTS inventory.reservations (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id    UUID NOT NULL REFERENCES inventory.items(id),
+    booking_id UUID NOT NULL REFERENCES booking.bookings(id),
+    quantity   INT NOT NULL CHECK (quantity > 0),
+    status     TEXT NOT NULL DEFAULT 'RESERVED',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reservations_booking_idx ON inventory.reservations (booking_id);
+CREATE INDEX IF NOT EXISTS reservations_open_item_idx ON inventory.reservations (item_id) WHERE status = 'RESERVED';
@@ -0,0 +1,27 @@
+-- Every change to inventory.items.quantity is recorded here. quantity is a signed delta.
+-- On-hand stock is SUM(quantity) over non-RESERVE rows; RESERVE rows track holds for
+-- bookings (positive to reserve, negative to release) and never change on-hand stock.
+CREATE TABLE IF NOT EXISTS inventory.stock_movements (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    item_id       UUID NOT NULL REFERENCES inventory.items(id) ON DELETE CASCADE,
+    movement_type TEXT NOT NULL CHECK (movement_type IN ('RESTOCK', 'CONSUME', 'ADJUST', 'DAMAGE', 'RETURN', 'RESERVE')),
+    quantity      INT NOT NULL CHECK (quantity <> 0),
+    reason        TEXT NOT NULL DEFAULT '',
+    reference_id  UUID,
+    actor         TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_item_idx ON inventory.stock_movements (item_id, created_at);
+
+-- opening balances so existing quantities are derivable from the ledger
+INSERT INTO inventory.stock_movements (item_id, movement_type, quantity, reason)
+SELECT id, 'ADJUST', quantity, 'opening balance'
+FROM inventory.items
+WHERE quantity <> 0;
+
+-- holds for reservations made before the ledger existed
+INSERT INTO inventory.stock_movements (item_id, movement_type, quantity, reason, reference_id)
+SELECT item_id, 'RESERVE', quantity, 'opening reservation', booking_id
+FROM inventory.reservations
+WHERE status = 'RESERVED';
@@ -0,0 +1,3 @@
+-- Per-item reorder threshold. NULL falls back to half of max_quantity, where an item turns LOW.
+ALTER TABLE inventory.items
+    ADD COLUMN IF NOT EXISTS reorder_point INT CHECK (reorder_point >= 0);
@@ -0,0 +1,6 @@
+-- Keep the full main service request on the quote so a booking can be pre-filled from it.
+-- Quotes made before this column existed have NULL here.
+ALTER TABLE payment.quotes
+    ADD COLUMN IF NOT EXISTS main_service_detail JSONB;
+
+CREATE INDEX IF NOT EXISTS quotes_customer_created_idx ON payment.quotes (customer_id, created_at DESC);
@@ -0,0 +1,30 @@
+-- Quotes expire after a TTL and are consumed by the booking that uses them.
+-- consumed_by is the base booking the quote was spent on.
+ALTER TABLE payment.quotes
+    ADD COLUMN IF NOT EXISTS expires_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS consumed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS consumed_by UUID;
+
+UPDATE payment.quotes
+SET expires_at = created_at + INTERVAL '7 days'
+WHERE expires_at IS NULL;
+
+ALTER TABLE payment.quotes
+    ALTER COLUMN expires_at SET DEFAULT NOW() + INTERVAL '7 days',
+    ALTER COLUMN expires_at SET NOT NULL;
+
+-- quotes already used by a booking are consumed by it
+UPDATE payment.quotes q
+SET consumed_at = bb.created_at,
+    consumed_by = bb.id,
+    is_valid = FALSE
+FROM booking.basebookings bb
+WHERE bb.quote_id::text = q.id::text
+  AND q.consumed_at IS NULL;
+
+-- anything left past its expiry is swept now rather than on the first job run
+UPDATE payment.quotes
+SET is_valid = FALSE
+WHERE is_valid AND expires_at <= NOW();
+
+CREATE INDEX IF NOT EXISTS quotes_valid_expires_idx ON payment.quotes (expires_at) WHERE is_valid;
@@ -0,0 +1,48 @@
+-- Versioned price catalog. The active version is the newest one whose effective_from has passed.
+-- rates holds types.PriceRates as JSON.
+CREATE TABLE IF NOT EXISTS payment.price_catalogs (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    version        INT NOT NULL UNIQUE,
+    effective_from TIMESTAMPTZ NOT NULL,
+    notes          TEXT NOT NULL DEFAULT '',
+    rates          JSONB NOT NULL,
+    created_by     TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS price_catalogs_effective_idx ON payment.price_catalogs (effective_from DESC, version DESC);
+
+-- version 1 carries the prices that used to be hard-coded
+INSERT INTO payment.price_catalogs (version, effective_from, notes, rates)
+VALUES (1, '2000-01-01T00:00:00Z', 'initial prices', '{
+    "generalTiers": [
+        {"homeType": "CONDO_ROOM", "minSqm": 0, "maxSqm": 30, "price": 2000},
+        {"homeType": "HOUSE", "minSqm": 30, "maxSqm": 50, "price": 2500},
+        {"minSqm": 50, "maxSqm": 100, "price": 5000}
+    ],
+    "generalPerSqm": 50,
+    "postConstructionPerSqm": 50,
+    "mattress": {
+        "KING": 2000, "KING_HEADBAND": 2500, "QUEEN": 1800, "QUEEN_HEADBAND": 2300,
+        "DOUBLE": 1500, "SINGLE": 1000
+    },
+    "car": {
+        "SEDAN": 3250, "MPV": 4000, "SUV": 4000, "VAN": 5200, "PICKUP": 3600, "CAR_SMALL": 1750
+    },
+    "couch": {
+        "SEATER_1": 500, "SEATER_2": 1000, "SEATER_3": 1300,
+        "SEATER_3_LTYPE_SMALL": 1500, "SEATER_3_LTYPE_LARGE": 1750,
+        "SEATER_4_LTYPE_SMALL": 1800, "SEATER_4_LTYPE_LARGE": 2000,
+        "SEATER_5_LTYPE": 2250, "SEATER_6_LTYPE": 2500,
+        "OTTOMAN": 500, "LAZBOY": 900, "CHAIR": 250
+    },
+    "childSeat": 250,
+    "bedPillow": 100
+}')
+ON CONFLICT (version) DO NOTHING;
+
+-- the catalog version each quote was priced with; NULL for quotes made before catalogs existed
+ALTER TABLE payment.quotes
+    ADD COLUMN IF NOT EXISTS catalog_id      UUID REFERENCES payment.price_catalogs(id),
+    ADD COLUMN IF NOT EXISTS catalog_version INT;
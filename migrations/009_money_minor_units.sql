@@ -0,0 +1,18 @@
+-- Money is stored as BIGINT minor units (centavos) instead of REAL pesos,
+-- so totals add up exactly. Existing amounts are rounded to the nearest centavo.
+ALTER TABLE payment.quotes
+    ALTER COLUMN subtotal    TYPE BIGINT USING ROUND(subtotal::numeric * 100)::bigint,
+    ALTER COLUMN addon_total TYPE BIGINT USING ROUND(addon_total::numeric * 100)::bigint,
+    ALTER COLUMN total_price TYPE BIGINT USING ROUND(total_price::numeric * 100)::bigint;
+
+ALTER TABLE payment.quote_addons
+    ALTER COLUMN addon_price TYPE BIGINT USING ROUND(addon_price::numeric * 100)::bigint;
+
+ALTER TABLE booking.addons
+    ALTER COLUMN price TYPE BIGINT USING ROUND(price::numeric * 100)::bigint;
+
+ALTER TABLE booking.bookings
+    ALTER COLUMN total_price TYPE BIGINT USING ROUND(total_price::numeric * 100)::bigint;
+
+ALTER TABLE booking.cancellations
+    ALTER COLUMN refund_amount TYPE BIGINT USING ROUND(refund_amount::numeric * 100)::bigint;
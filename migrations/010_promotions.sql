@@ -0,0 +1,54 @@
+-- Promo codes. A PERCENT promotion takes percent_off of the eligible services, a FIXED one takes
+-- amount_off (minor units). Empty service_types means every service is eligible.
+CREATE TABLE IF NOT EXISTS payment.promotions (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code               TEXT NOT NULL UNIQUE,
+    description        TEXT NOT NULL DEFAULT '',
+    discount_type      TEXT NOT NULL CHECK (discount_type IN ('PERCENT', 'FIXED')),
+    percent_off        INT CHECK (percent_off BETWEEN 1 AND 100),
+    amount_off         BIGINT CHECK (amount_off > 0),
+    min_spend          BIGINT NOT NULL DEFAULT 0,
+    valid_from         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    valid_until        TIMESTAMPTZ,
+    usage_limit        INT CHECK (usage_limit > 0),
+    per_customer_limit INT CHECK (per_customer_limit > 0),
+    service_types      TEXT[] NOT NULL DEFAULT '{}',
+    is_active          BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by         TEXT NOT NULL DEFAULT '',
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((discount_type = 'PERCENT') = (percent_off IS NOT NULL)),
+    CHECK ((discount_type = 'FIXED') = (amount_off IS NOT NULL)),
+    CHECK (valid_until IS NULL OR valid_until > valid_from)
+);
+
+-- One redemption per quote, recorded in the transaction that books it.
+CREATE TABLE IF NOT EXISTS payment.promotion_redemptions (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promotion_id UUID NOT NULL REFERENCES payment.promotions (id),
+    quote_id     UUID NOT NULL UNIQUE REFERENCES payment.quotes (id),
+    booking_id   UUID NOT NULL,
+    customer_id  TEXT NOT NULL,
+    amount       BIGINT NOT NULL,
+    redeemed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS promotion_redemptions_customer_idx ON payment.promotion_redemptions (promotion_id, customer_id);
+
+-- Priced lines of a quote on top of its services, e.g. promo discounts. amount is signed minor units,
+-- negative for discounts; quotes.total_price = subtotal + addon_total + adjustment_total.
+CREATE TABLE IF NOT EXISTS payment.quote_adjustments (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    quote_id   UUID NOT NULL REFERENCES payment.quotes (id) ON DELETE CASCADE,
+    kind       TEXT NOT NULL,
+    code       TEXT NOT NULL DEFAULT '',
+    label      TEXT NOT NULL,
+    amount     BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS quote_adjustments_quote_idx ON payment.quote_adjustments (quote_id);
+
+ALTER TABLE payment.quotes
+    ADD COLUMN IF NOT EXISTS adjustment_total BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS promotion_id     UUID REFERENCES payment.promotions (id);
@@ -0,0 +1,28 @@
+-- VAT. Customers are VATABLE unless marked EXEMPT or ZERO_RATED; tax_id is their TIN.
+ALTER TABLE account.customers
+    ADD COLUMN IF NOT EXISTS tax_status TEXT NOT NULL DEFAULT 'VATABLE'
+        CHECK (tax_status IN ('VATABLE', 'EXEMPT', 'ZERO_RATED')),
+    ADD COLUMN IF NOT EXISTS tax_id     TEXT NOT NULL DEFAULT '';
+
+-- Quotes and bookings keep the tax breakdown they were priced with; total_price is the gross.
+-- tax_rate_bps is in hundredths of a percent (1200 = 12%).
+ALTER TABLE payment.quotes
+    ADD COLUMN IF NOT EXISTS net_amount         BIGINT,
+    ADD COLUMN IF NOT EXISTS tax_amount         BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_rate_bps       INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_status         TEXT NOT NULL DEFAULT 'VATABLE',
+    ADD COLUMN IF NOT EXISTS prices_include_tax BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE booking.bookings
+    ADD COLUMN IF NOT EXISTS net_amount         BIGINT,
+    ADD COLUMN IF NOT EXISTS tax_amount         BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_rate_bps       INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_status         TEXT NOT NULL DEFAULT 'VATABLE',
+    ADD COLUMN IF NOT EXISTS prices_include_tax BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- rows priced before tax was itemised carry no tax line
+UPDATE payment.quotes SET net_amount = total_price WHERE net_amount IS NULL;
+UPDATE booking.bookings SET net_amount = total_price WHERE net_amount IS NULL;
+
+ALTER TABLE payment.quotes ALTER COLUMN net_amount SET NOT NULL;
+ALTER TABLE booking.bookings ALTER COLUMN net_amount SET NOT NULL;
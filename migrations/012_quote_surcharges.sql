@@ -0,0 +1,5 @@
+-- Job conditions a quote was priced with. Surcharge rates live in the catalog rates
+-- (dirtyScaleSurcharges, modifiers); catalogs without them charge no surcharges.
+ALTER TABLE payment.quotes
+    ADD COLUMN IF NOT EXISTS dirty_scale INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS modifiers   TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,19 @@
+-- Service area: depots with radius bands. An address is covered when it lies within the last band
+-- of an active depot; the nearest covering depot prices the travel fee.
+-- bands holds []types.TravelBand as JSON, ordered by maxKm.
+CREATE TABLE IF NOT EXISTS payment.depots (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name       TEXT NOT NULL UNIQUE,
+    lat        DOUBLE PRECISION NOT NULL CHECK (lat BETWEEN -90 AND 90),
+    lng        DOUBLE PRECISION NOT NULL CHECK (lng BETWEEN -180 AND 180),
+    bands      JSONB NOT NULL,
+    is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- where a quote's job is and how far it is from the depot that serves it
+ALTER TABLE payment.quotes
+    ADD COLUMN IF NOT EXISTS address           JSONB,
+    ADD COLUMN IF NOT EXISTS depot_id          UUID REFERENCES payment.depots (id),
+    ADD COLUMN IF NOT EXISTS travel_distance_m INT;
@@ -0,0 +1,11 @@
+-- Holiday calendar for time-based pricing; on these dates time rules see the day as HOLIDAY.
+-- The rules themselves live in the catalog rates (timeRules).
+CREATE TABLE IF NOT EXISTS payment.holidays (
+    date       DATE PRIMARY KEY,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- the slot a quote was priced for, if the customer gave one
+ALTER TABLE payment.quotes
+    ADD COLUMN IF NOT EXISTS start_sched TIMESTAMPTZ;
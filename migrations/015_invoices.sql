@@ -0,0 +1,45 @@
+-- Invoices for completed bookings. Numbers are gap-free per series: the series row is locked and
+-- bumped in the transaction that issues the invoice, so a rolled back issue does not use up a number.
+CREATE TABLE IF NOT EXISTS payment.invoice_series (
+    series      TEXT PRIMARY KEY,
+    last_number BIGINT NOT NULL CHECK (last_number > 0)
+);
+
+-- Customer details, lines and totals are copied at issue time so later edits do not change the invoice.
+CREATE TABLE IF NOT EXISTS payment.invoices (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    series             TEXT NOT NULL REFERENCES payment.invoice_series (series),
+    number             BIGINT NOT NULL,
+    invoice_no         TEXT NOT NULL UNIQUE,
+    booking_id         UUID NOT NULL UNIQUE,
+    customer_id        TEXT NOT NULL,
+    customer_name      TEXT NOT NULL,
+    billing_address    TEXT NOT NULL DEFAULT '',
+    status             TEXT NOT NULL DEFAULT 'ISSUED' CHECK (status IN ('ISSUED', 'PAID', 'VOID')),
+    subtotal           BIGINT NOT NULL,
+    net_amount         BIGINT NOT NULL,
+    tax_amount         BIGINT NOT NULL,
+    total_amount       BIGINT NOT NULL,
+    tax_rate_bps       INT NOT NULL,
+    tax_status         TEXT NOT NULL,
+    prices_include_tax BOOLEAN NOT NULL,
+    issued_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    due_at             TIMESTAMPTZ NOT NULL,
+    paid_at            TIMESTAMPTZ,
+    voided_at          TIMESTAMPTZ,
+    void_reason        TEXT NOT NULL DEFAULT '',
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (series, number)
+);
+
+CREATE INDEX IF NOT EXISTS invoices_customer_idx ON payment.invoices (customer_id, issued_at DESC);
+
+-- amount is signed minor units, before tax; the lines add up to invoices.subtotal.
+CREATE TABLE IF NOT EXISTS payment.invoice_lines (
+    invoice_id  UUID NOT NULL REFERENCES payment.invoices (id) ON DELETE CASCADE,
+    position    INT NOT NULL,
+    kind        TEXT NOT NULL,
+    description TEXT NOT NULL,
+    amount      BIGINT NOT NULL,
+    PRIMARY KEY (invoice_id, position)
+);
@@ -0,0 +1,20 @@
+-- Money moved through the payment provider for a booking. A payment is recorded PENDING before the
+-- provider is called and settled with its outcome, so a crash in between leaves a visible trace.
+-- provider_ref is the provider's payment intent (or refund) id.
+CREATE TABLE IF NOT EXISTS payment.transactions (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id      UUID NOT NULL,
+    kind            TEXT NOT NULL CHECK (kind IN ('PAYMENT', 'REFUND')),
+    status          TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SUCCEEDED', 'FAILED')),
+    provider        TEXT NOT NULL,
+    provider_ref    TEXT,
+    amount          BIGINT NOT NULL CHECK (amount > 0),
+    method          TEXT NOT NULL DEFAULT '',
+    failure_reason  TEXT NOT NULL DEFAULT '',
+    idempotency_key TEXT UNIQUE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_ref)
+);
+
+CREATE INDEX IF NOT EXISTS transactions_booking_idx ON payment.transactions (booking_id, created_at);
@@ -0,0 +1,13 @@
+-- Payment provider webhook events, one row per provider event id. The row is written in the same
+-- transaction that applies the event, so a redelivered event is recognised and applied only once,
+-- and an event that failed to apply is not recorded and can be retried by the provider.
+CREATE TABLE IF NOT EXISTS payment.webhook_events (
+    provider       TEXT NOT NULL,
+    event_id       TEXT NOT NULL,
+    type           TEXT NOT NULL,
+    transaction_id UUID REFERENCES payment.transactions (id),
+    outcome        TEXT NOT NULL,
+    payload        JSONB NOT NULL,
+    received_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, event_id)
+);
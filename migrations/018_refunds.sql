@@ -0,0 +1,32 @@
+-- Refunds return money from one captured payment each: payment_id is that payment, and the
+-- refunds against a payment never add up to more than it captured. reason is a refund reason code.
+ALTER TABLE payment.transactions
+    ADD COLUMN IF NOT EXISTS payment_id UUID REFERENCES payment.transactions (id),
+    ADD COLUMN IF NOT EXISTS reason     TEXT CHECK (reason IN ('CANCELLATION', 'COMPLAINT', 'OVERCHARGE', 'DUPLICATE', 'OTHER')),
+    ADD COLUMN IF NOT EXISTS note       TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE payment.transactions DROP CONSTRAINT IF EXISTS transactions_refund_payment_check;
+ALTER TABLE payment.transactions ADD CONSTRAINT transactions_refund_payment_check
+    CHECK ((kind = 'REFUND') = (payment_id IS NOT NULL AND reason IS NOT NULL));
+
+CREATE INDEX IF NOT EXISTS transactions_payment_idx ON payment.transactions (payment_id) WHERE payment_id IS NOT NULL;
+
+-- Credit notes reduce an invoice by a succeeded refund, one per refund transaction. They are
+-- numbered without gaps in their own series of payment.invoice_series.
+CREATE TABLE IF NOT EXISTS payment.credit_notes (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    series         TEXT NOT NULL,
+    number         BIGINT NOT NULL,
+    credit_note_no TEXT NOT NULL UNIQUE,
+    invoice_id     UUID NOT NULL REFERENCES payment.invoices (id),
+    booking_id     UUID NOT NULL,
+    transaction_id UUID NOT NULL UNIQUE REFERENCES payment.transactions (id),
+    reason         TEXT NOT NULL,
+    net_amount     BIGINT NOT NULL,
+    tax_amount     BIGINT NOT NULL,
+    total_amount   BIGINT NOT NULL CHECK (total_amount > 0),
+    issued_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (series, number)
+);
+
+CREATE INDEX IF NOT EXISTS credit_notes_invoice_idx ON payment.credit_notes (invoice_id, number);
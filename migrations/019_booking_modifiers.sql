@@ -0,0 +1,9 @@
+-- Job conditions a booking is priced with, besides its dirty scale: the catalog modifier codes it
+-- was quoted for. Existing bookings take them from their quote.
+ALTER TABLE booking.basebookings
+    ADD COLUMN IF NOT EXISTS modifiers TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE booking.basebookings bb
+SET modifiers = q.modifiers
+FROM payment.quotes q
+WHERE q.id::text = bb.quote_id::text AND bb.modifiers = '{}';
@@ -0,0 +1,13 @@
+-- A refund request's idempotency key is kept as is in request_key, shared by the refunds it makes
+-- (one per payment), instead of being folded into idempotency_key, which stays for payments only.
+-- Keys are matched exactly, so one containing ':' cannot match another request's refunds.
+ALTER TABLE payment.transactions
+    ADD COLUMN IF NOT EXISTS request_key TEXT;
+
+UPDATE payment.transactions
+SET request_key = left(idempotency_key, length(idempotency_key) - length(payment_id::text) - 1),
+    idempotency_key = NULL
+WHERE kind = 'REFUND' AND idempotency_key LIKE '%:' || payment_id::text;
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_request_key_payment_idx
+    ON payment.transactions (request_key, payment_id) WHERE request_key IS NOT NULL;
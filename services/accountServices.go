@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"handworks-api/types"
+	"strings"
 	"time"
 
 	"github.com/clerk/clerk-sdk-go/v2/user"
@@ -114,6 +115,31 @@ func (s *AccountService) UpdateCustomer(ctx context.Context, req types.UpdateCus
 	}, nil
 }
 
+// UpdateCustomerTax sets a customer's VAT status. It applies to quotes made afterwards.
+func (s *AccountService) UpdateCustomerTax(ctx context.Context, req types.UpdateCustomerTaxRequest) (*types.UpdateCustomerResponse, error) {
+	req.TaxID = strings.TrimSpace(req.TaxID)
+	if req.TaxStatus != types.TaxVatable && req.TaxID == "" {
+		return nil, types.ErrTaxIDRequired
+	}
+	var customer types.Customer
+
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		cust, err := s.Tasks.UpdateCustomerTax(ctx, tx, req.ID, req.TaxStatus, req.TaxID)
+		if err != nil {
+			return err
+		}
+		customer = *cust
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("could not update customer tax status: %w", err)
+	}
+	s.Logger.Info("Customer %s tax status set to %s", customer.ID, customer.TaxStatus)
+	return &types.UpdateCustomerResponse{
+		Customer: customer,
+	}, nil
+}
+
 func (s *AccountService) DeleteCustomer(ctx context.Context, id, accId string) (*types.DeleteCustomerResponse,error) {
 		var customer types.Customer
 
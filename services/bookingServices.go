@@ -1,9 +1,14 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"handworks-api/tasks"
 	"handworks-api/types"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -30,15 +35,16 @@ func (s *BookingService) withTx(
 func (s *BookingService) CreateBooking(ctx context.Context, req types.CreateBookingRequest) (*types.Booking, error) {
 	s.Logger.Info("Creating booking for customer: %s...", req.Base.CustomerFirstName)
 
-	alloc, err := s.Tasks.AllocateAll(ctx, s.PaymentPort, &req)
-	if err != nil {
-		s.Logger.Error("Allocation failed: %v", err)
-		return nil, err
-	}
-
 	var createdBooking *types.Booking
+	// a new booking has no id yet; a client supplied one would let it reuse another booking's quote and slots
+	req.Base.ID = ""
 
-	err = s.withTx(ctx, func(tx pgx.Tx) error {
+	err := s.withTx(ctx, func(tx pgx.Tx) error {
+		alloc, err := s.Tasks.AllocateAll(ctx, tx, s.PaymentPort, &req)
+		if err != nil {
+			s.Logger.Error("Allocation failed: %v", err)
+			return err
+		}
 
 		mainService, err := s.Tasks.CreateMainServiceBooking(ctx, tx, s.Logger, req.MainService.Details)
 		if err != nil {
@@ -55,6 +61,7 @@ func (s *BookingService) CreateBooking(ctx context.Context, req types.CreateBook
 			req.Base.StartSched,
 			req.Base.EndSched,
 			req.Base.DirtyScale,
+			req.Base.Modifiers,
 			req.Base.Photos,
 			req.Base.QuoteId,
 		)
@@ -62,16 +69,17 @@ func (s *BookingService) CreateBooking(ctx context.Context, req types.CreateBook
 			return err
 		}
 
+		if err := s.PaymentPort.CheckQuoteSchedule(ctx, tx, req.Base.QuoteId, req.Base.StartSched); err != nil {
+			return err
+		}
+		if err := s.PaymentPort.ConsumeQuote(ctx, tx, req.Base.QuoteId, baseBook.ID); err != nil {
+			return err
+		}
+
 		var addonModels []types.AddOns
 		var addonIDs []string
-		for _, addonReq := range req.Addons {
-			var addonPrice float32
-			for _, ap := range alloc.CleaningPrices.AddonPrices {
-				if ap.AddonName == string(addonReq.ServiceDetail.ServiceType) {
-					addonPrice = ap.AddonPrice
-					break
-				}
-			}
+		for i, addonReq := range req.Addons {
+			addonPrice := alloc.CleaningPrices.AddonPrices[i].AddonPrice
 
 			createdAddon, err := s.Tasks.CreateAddOn(ctx, tx, s.Logger, addonReq, addonPrice)
 			if err != nil {
@@ -96,7 +104,7 @@ func (s *BookingService) CreateBooking(ctx context.Context, req types.CreateBook
 			cleanerIDs = append(cleanerIDs, c.ID)
 		}
 
-		totalPrice := alloc.CleaningPrices.MainServicePrice
+		totalPrice := alloc.CleaningPrices.Tax.Gross
 
 		bookingID, err := s.Tasks.SaveBooking(
 			ctx,
@@ -107,12 +115,20 @@ func (s *BookingService) CreateBooking(ctx context.Context, req types.CreateBook
 			equipmentIDs,
 			resourceIDs,
 			cleanerIDs,
-			totalPrice,
+			alloc.CleaningPrices.Tax,
 		)
 		if err != nil {
 			return err
 		}
 
+		if _, err := s.Tasks.RecordStatusChange(ctx, tx, bookingID, "", types.BookingPending, req.Base.CustID, "booking created"); err != nil {
+			return err
+		}
+
+		if _, err := s.Inventory.ReserveStock(ctx, tx, bookingID, alloc.CleaningAllocation.CleaningResources); err != nil {
+			return err
+		}
+
 		createdBooking = &types.Booking{
 			ID:          bookingID,
 			Base:        *baseBook,
@@ -122,6 +138,7 @@ func (s *BookingService) CreateBooking(ctx context.Context, req types.CreateBook
 			Resources:   alloc.CleaningAllocation.CleaningResources,
 			Cleaners:    alloc.CleanerAssigned,
 			TotalPrice:  totalPrice,
+			Tax:         alloc.CleaningPrices.Tax,
 		}
 
 		return nil
@@ -133,18 +150,450 @@ func (s *BookingService) CreateBooking(ctx context.Context, req types.CreateBook
 
 	return createdBooking, nil
 }
-func (s *BookingService) GetBookingById(ctx context.Context) error {
-	return nil
+func (s *BookingService) GetBookingById(ctx context.Context, id string) (*types.Booking, error) {
+	var booking *types.Booking
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		b, err := s.Tasks.FetchBookingByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		booking = b
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to fetch booking %s: %v", id, err)
+		return nil, err
+	}
+	return booking, nil
+}
+
+func (s *BookingService) GetBookingByUId(ctx context.Context, filter types.BookingFilter) (*types.BookingsResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+
+	var startDate, endDate *time.Time
+	if filter.StartDate != "" {
+		parsed, err := time.Parse("2006-01-02", filter.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: startDate must be YYYY-MM-DD: %v", types.ErrInvalidBookingFilter, err)
+		}
+		startDate = &parsed
+	}
+	if filter.EndDate != "" {
+		parsed, err := time.Parse("2006-01-02", filter.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: endDate must be YYYY-MM-DD: %v", types.ErrInvalidBookingFilter, err)
+		}
+		// end date is inclusive, so bound on the start of the following day
+		parsed = parsed.AddDate(0, 0, 1)
+		endDate = &parsed
+	}
+
+	resp := &types.BookingsResponse{
+		Bookings: []types.Booking{},
+		Page:     filter.Page,
+		Limit:    filter.Limit,
+	}
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		ids, total, err := s.Tasks.FetchBookingIDsByCustomer(
+			ctx,
+			tx,
+			filter.CustID,
+			startDate,
+			endDate,
+			filter.PaymentStatus,
+			filter.ReviewStatus,
+			filter.Limit,
+			(filter.Page-1)*filter.Limit,
+		)
+		if err != nil {
+			return err
+		}
+		resp.TotalCount = total
+		resp.Bookings, err = s.Tasks.FetchBookingsByIDs(ctx, tx, ids)
+		return err
+	}); err != nil {
+		s.Logger.Error("Failed to fetch bookings for customer %s: %v", filter.CustID, err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *BookingService) UpdateBooking(ctx context.Context, req types.UpdateBookingRequest) (*types.UpdateBookingResponse, error) {
+	s.Logger.Info("Updating booking %s...", req.ID)
+	resp := &types.UpdateBookingResponse{Changes: []types.BookingChange{}}
+
+	err := s.withTx(ctx, func(tx pgx.Tx) error {
+		current, err := s.Tasks.FetchBookingByID(ctx, tx, req.ID)
+		if err != nil {
+			return err
+		}
+		if current.Base.Status != types.BookingPending && current.Base.Status != types.BookingConfirmed {
+			return types.ErrBookingNotEditable
+		}
+		base := current.Base
+		reallocate := false
+
+		if req.StartSched != nil && !req.StartSched.Equal(base.StartSched) {
+			resp.Changes = append(resp.Changes, types.BookingChange{Field: "startSched", From: base.StartSched, To: *req.StartSched})
+			base.StartSched = *req.StartSched
+			reallocate = true
+		}
+		if req.EndSched != nil && !req.EndSched.Equal(base.EndSched) {
+			resp.Changes = append(resp.Changes, types.BookingChange{Field: "endSched", From: base.EndSched, To: *req.EndSched})
+			base.EndSched = *req.EndSched
+			reallocate = true
+		}
+		// changes to the job's price need a new quote, which is verified against the edited booking;
+		// moving the address re-checks its coverage and travel fee that way
+		priceChanged := false
+		if req.Address != nil && *req.Address != base.Address {
+			resp.Changes = append(resp.Changes, types.BookingChange{Field: "address", From: base.Address, To: *req.Address})
+			priceChanged = !tasks.SameLocation(*req.Address, base.Address)
+			base.Address = *req.Address
+		}
+		if req.DirtyScale != nil && *req.DirtyScale != base.DirtyScale {
+			resp.Changes = append(resp.Changes, types.BookingChange{Field: "dirtyScale", From: base.DirtyScale, To: *req.DirtyScale})
+			base.DirtyScale = *req.DirtyScale
+			priceChanged = true
+		}
+		if req.Modifiers != nil {
+			modifiers := tasks.NormalizeModifiers(*req.Modifiers)
+			if !slices.Equal(modifiers, base.Modifiers) {
+				resp.Changes = append(resp.Changes, types.BookingChange{Field: "modifiers", From: base.Modifiers, To: modifiers})
+				base.Modifiers = modifiers
+				priceChanged = true
+			}
+		}
+		if req.Photos != nil && !slices.Equal(*req.Photos, base.Photos) {
+			resp.Changes = append(resp.Changes, types.BookingChange{Field: "photos", From: base.Photos, To: *req.Photos})
+			base.Photos = *req.Photos
+		}
+
+		addons := make([]types.AddOnRequest, 0, len(current.Addons))
+		currentAddonIDs := make([]string, 0, len(current.Addons))
+		for _, a := range current.Addons {
+			addons = append(addons, types.AddOnRequest{ServiceDetail: tasks.ServiceRequestFromDetails(a.ServiceDetail)})
+			currentAddonIDs = append(currentAddonIDs, a.ID)
+		}
+		addonsChanged := false
+		if req.Addons != nil {
+			before, err := json.Marshal(addons)
+			if err != nil {
+				return fmt.Errorf("failed to marshal addons: %w", err)
+			}
+			after, err := json.Marshal(*req.Addons)
+			if err != nil {
+				return fmt.Errorf("failed to marshal addons: %w", err)
+			}
+			if !bytes.Equal(before, after) {
+				resp.Changes = append(resp.Changes, types.BookingChange{Field: "addons", From: addons, To: *req.Addons})
+				addons = *req.Addons
+				addonsChanged = true
+				priceChanged = true
+			}
+		}
+		quoteChanged := req.QuoteId != nil && *req.QuoteId != base.QuoteId
+		if quoteChanged {
+			resp.Changes = append(resp.Changes, types.BookingChange{Field: "quoteId", From: base.QuoteId, To: *req.QuoteId})
+			base.QuoteId = *req.QuoteId
+			reallocate = true
+		}
+		if priceChanged && !quoteChanged {
+			return types.ErrQuoteRequired
+		}
+
+		if len(resp.Changes) == 0 {
+			resp.Booking = *current
+			return nil
+		}
+		if !base.EndSched.After(base.StartSched) {
+			return fmt.Errorf("%w: endSched must be after startSched", types.ErrInvalidBookingUpdate)
+		}
+		if quoteChanged || !base.StartSched.Equal(current.Base.StartSched) {
+			if err := s.PaymentPort.CheckQuoteSchedule(ctx, tx, base.QuoteId, base.StartSched); err != nil {
+				return err
+			}
+		}
+
+		if _, err := s.Tasks.UpdateBaseBooking(
+			ctx,
+			tx,
+			base.ID,
+			base.Address,
+			base.StartSched,
+			base.EndSched,
+			base.DirtyScale,
+			base.Modifiers,
+			base.Photos,
+			base.QuoteId,
+		); err != nil {
+			return err
+		}
+
+		if reallocate {
+			allocReq := types.CreateBookingRequest{
+				Base: types.BaseBookingDetailsRequest{
+					ID:                base.ID,
+					CustID:            base.CustID,
+					CustomerFirstName: base.CustomerFirstName,
+					CustomerLastName:  base.CustomerLastName,
+					Address:           base.Address,
+					StartSched:        base.StartSched,
+					EndSched:          base.EndSched,
+					DirtyScale:        base.DirtyScale,
+					Modifiers:         base.Modifiers,
+					Photos:            base.Photos,
+					QuoteId:           base.QuoteId,
+				},
+				MainService: tasks.ServiceRequestFromDetails(current.MainService),
+				Addons:      addons,
+			}
+			alloc, err := s.Tasks.AllocateAll(ctx, tx, s.PaymentPort, &allocReq)
+			if err != nil {
+				s.Logger.Error("Reallocation failed: %v", err)
+				return err
+			}
+			if quoteChanged {
+				if err := s.PaymentPort.ReplaceQuote(ctx, tx, current.Base.QuoteId, base.QuoteId, base.ID); err != nil {
+					return err
+				}
+			}
+
+			addonIDs := currentAddonIDs
+			if addonsChanged {
+				if err := s.Tasks.DeleteAddOns(ctx, tx, currentAddonIDs); err != nil {
+					return err
+				}
+				addonIDs = make([]string, 0, len(addons))
+				for i, addonReq := range addons {
+					addonPrice := alloc.CleaningPrices.AddonPrices[i].AddonPrice
+					createdAddon, err := s.Tasks.CreateAddOn(ctx, tx, s.Logger, addonReq, addonPrice)
+					if err != nil {
+						return err
+					}
+					addonIDs = append(addonIDs, createdAddon.ID)
+				}
+			}
+
+			equipmentIDs := make([]string, 0, len(alloc.CleaningAllocation.CleaningEquipment))
+			for _, eq := range alloc.CleaningAllocation.CleaningEquipment {
+				equipmentIDs = append(equipmentIDs, eq.ID)
+			}
+
+			resourceIDs := make([]string, 0, len(alloc.CleaningAllocation.CleaningResources))
+			for _, r := range alloc.CleaningAllocation.CleaningResources {
+				resourceIDs = append(resourceIDs, r.ID)
+			}
+
+			cleanerIDs := make([]string, 0, len(alloc.CleanerAssigned))
+			for _, c := range alloc.CleanerAssigned {
+				cleanerIDs = append(cleanerIDs, c.ID)
+			}
+
+			totalPrice := alloc.CleaningPrices.Tax.Gross
+			if !totalPrice.Equal(current.TotalPrice) {
+				resp.Changes = append(resp.Changes, types.BookingChange{Field: "totalPrice", From: current.TotalPrice, To: totalPrice})
+			}
+
+			if err := s.Tasks.UpdateBookingAllocation(
+				ctx,
+				tx,
+				current.ID,
+				addonIDs,
+				equipmentIDs,
+				resourceIDs,
+				cleanerIDs,
+				alloc.CleaningPrices.Tax,
+			); err != nil {
+				return err
+			}
+			if err := s.Inventory.ReleaseReservations(ctx, tx, current.ID); err != nil {
+				return err
+			}
+			if _, err := s.Inventory.ReserveStock(ctx, tx, current.ID, alloc.CleaningAllocation.CleaningResources); err != nil {
+				return err
+			}
+			resp.Reallocated = true
+		}
+
+		updated, err := s.Tasks.FetchBookingByID(ctx, tx, current.ID)
+		if err != nil {
+			return err
+		}
+		resp.Booking = *updated
+		return nil
+	})
+	if err != nil {
+		s.Logger.Error("Failed to update booking %s: %v", req.ID, err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// CancelBooking soft-cancels a booking, releasing its allocation and computing the refund from the cancellation policy.
+func (s *BookingService) CancelBooking(ctx context.Context, req types.CancelBookingRequest) (*types.CancelBookingResponse, error) {
+	s.Logger.Info("Cancelling booking %s...", req.ID)
+	var resp *types.CancelBookingResponse
+
+	err := s.withTx(ctx, func(tx pgx.Tx) error {
+		booking, err := s.Tasks.FetchBookingByID(ctx, tx, req.ID)
+		if err != nil {
+			return err
+		}
+		resp, _, err = s.cancel(ctx, tx, booking, req.CancelledBy, req.Reason)
+		return err
+	})
+	if err != nil {
+		s.Logger.Error("Failed to cancel booking %s: %v", req.ID, err)
+		return nil, err
+	}
+	s.refundCancellation(ctx, resp)
+
+	return resp, nil
 }
 
-func (s *BookingService) GetBookingByUId(ctx context.Context) error {
-	return nil
+// refundCancellation pays out the refund of a committed cancellation through the payment provider.
+// The cancellation stands if the refund fails; the failure is reported in the response so the refund
+// can be retried through the refund endpoint. The idempotency key keeps it from being paid out twice.
+func (s *BookingService) refundCancellation(ctx context.Context, resp *types.CancelBookingResponse) {
+	if resp.RefundableAmount.Minor <= 0 {
+		return
+	}
+	amount := resp.RefundableAmount
+	refund, err := s.PaymentPort.RefundBooking(ctx, types.RefundBookingRequest{
+		BookingID:      resp.BookingID,
+		Amount:         &amount,
+		Reason:         types.RefundCancellation,
+		Note:           resp.Reason,
+		IdempotencyKey: "cancellation:" + resp.BookingID,
+	})
+	if err != nil {
+		s.Logger.Error("Failed to refund cancelled booking %s: %v", resp.BookingID, err)
+		resp.RefundError = err.Error()
+		return
+	}
+	resp.Refund = refund
+}
+
+func (s *BookingService) cancel(
+	ctx context.Context,
+	tx pgx.Tx,
+	booking *types.Booking,
+	cancelledBy, reason string,
+) (*types.CancelBookingResponse, *types.BookingStatusChange, error) {
+	if booking.Base.Status == types.BookingCancelled {
+		return nil, nil, types.ErrBookingCancelled
+	}
+	if err := s.Tasks.UpdateBookingStatus(ctx, tx, booking.Base.ID, booking.Base.Status, types.BookingCancelled); err != nil {
+		return nil, nil, err
+	}
+	change, err := s.Tasks.RecordStatusChange(ctx, tx, booking.ID, booking.Base.Status, types.BookingCancelled, cancelledBy, reason)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.Inventory.ReleaseReservations(ctx, tx, booking.ID); err != nil {
+		return nil, nil, err
+	}
+
+	refundable, err := s.PaymentPort.RefundableAmount(ctx, tx, booking.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	refundPercent, refundAmount := s.Tasks.CalculateRefund(s.CancellationPolicy, booking.Base.StartSched, time.Now(), refundable)
+	cancelledAt, err := s.Tasks.CancelBooking(ctx, tx, booking, cancelledBy, reason, refundPercent, refundAmount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &types.CancelBookingResponse{
+		BookingID:        booking.ID,
+		Status:           types.BookingCancelled,
+		CancelledBy:      cancelledBy,
+		Reason:           reason,
+		RefundPercent:    refundPercent,
+		RefundableAmount: refundAmount,
+		CancelledAt:      cancelledAt,
+	}, change, nil
 }
 
-func (s *BookingService) UpdateBooking(ctx context.Context) error {
-	return nil
+// TransitionBooking moves a booking along its lifecycle. Moving to CANCELLED applies the full cancellation flow.
+func (s *BookingService) TransitionBooking(ctx context.Context, req types.TransitionBookingRequest) (*types.BookingStatusChange, error) {
+	s.Logger.Info("Moving booking %s to %s...", req.ID, req.Status)
+	var (
+		change    *types.BookingStatusChange
+		cancelled *types.CancelBookingResponse
+	)
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		booking, err := s.Tasks.FetchBookingByID(ctx, tx, req.ID)
+		if err != nil {
+			return err
+		}
+		if req.Status == types.BookingCancelled {
+			cancelled, change, err = s.cancel(ctx, tx, booking, req.Actor, req.Note)
+			return err
+		}
+		if req.Status == types.BookingEnRoute {
+			if err := s.Tasks.ValidateTransition(booking.Base.Status, req.Status); err != nil {
+				return err
+			}
+			if err := s.PaymentPort.CheckDeposit(ctx, tx, booking.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.Tasks.UpdateBookingStatus(ctx, tx, booking.Base.ID, booking.Base.Status, req.Status); err != nil {
+			return err
+		}
+		change, err = s.Tasks.RecordStatusChange(ctx, tx, booking.ID, booking.Base.Status, req.Status, req.Actor, req.Note)
+		if err != nil {
+			return err
+		}
+
+		switch req.Status {
+		case types.BookingCompleted:
+			if err := s.Inventory.ConsumeReservations(ctx, tx, booking.ID); err != nil {
+				return err
+			}
+			return s.PaymentPort.IssueInvoice(ctx, tx, booking.ID)
+		case types.BookingNoShow:
+			return s.Inventory.ReleaseReservations(ctx, tx, booking.ID)
+		}
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to move booking %s to %s: %v", req.ID, req.Status, err)
+		return nil, err
+	}
+	if cancelled != nil {
+		s.refundCancellation(ctx, cancelled)
+	}
+	return change, nil
 }
 
-func (s *BookingService) DeleteBooking(ctx context.Context) error {
-	return nil
-}
\ No newline at end of file
+func (s *BookingService) GetBookingHistory(ctx context.Context, id string) (*types.BookingStatusHistoryResponse, error) {
+	var resp *types.BookingStatusHistoryResponse
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		booking, err := s.Tasks.FetchBookingByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		history, err := s.Tasks.FetchStatusHistory(ctx, tx, booking.ID)
+		if err != nil {
+			return err
+		}
+		resp = &types.BookingStatusHistoryResponse{
+			BookingID: booking.ID,
+			Status:    booking.Base.Status,
+			History:   history,
+		}
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to fetch booking history %s: %v", id, err)
+		return nil, err
+	}
+	return resp, nil
+}
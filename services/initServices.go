@@ -1,8 +1,12 @@
 package services
 
 import (
+	"handworks-api/config"
 	"handworks-api/tasks"
+	"handworks-api/types"
 	"handworks-api/utils"
+	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -20,13 +24,24 @@ func NewAccountService(db *pgxpool.Pool, logger *utils.Logger) *AccountService {
 
 // --- Inventory Service ---
 type InventoryService struct {
-	DB     *pgxpool.Pool
-	Logger *utils.Logger
-	Tasks * tasks.InventoryTasks
+	DB       *pgxpool.Pool
+	Logger   *utils.Logger
+	Tasks    *tasks.InventoryTasks
+	Notifier utils.Notifier
+
+	// last alert level raised per item, so an alert fires once per threshold crossing
+	alertMu     sync.Mutex
+	alertLevels map[string]types.AlertLevel
 }
 
 func NewInventoryService(db *pgxpool.Pool, logger *utils.Logger) *InventoryService {
-	return &InventoryService{DB: db, Logger: logger, Tasks: &tasks.InventoryTasks{}}
+	return &InventoryService{
+		DB:          db,
+		Logger:      logger,
+		Tasks:       &tasks.InventoryTasks{},
+		Notifier:    &utils.LogNotifier{Logger: logger},
+		alertLevels: map[string]types.AlertLevel{},
+	}
 }
 
 // --- Booking Service ---
@@ -37,20 +52,45 @@ type BookingService struct {
 	Logger *utils.Logger
 	Tasks * tasks.BookingTasks
 	PaymentPort tasks.PaymentPort
+	Inventory * tasks.InventoryTasks
+	CancellationPolicy types.CancellationPolicy
 }
 
 func NewBookingService(db *pgxpool.Pool, logger *utils.Logger, paymentPort tasks.PaymentPort) *BookingService {
-	return &BookingService{DB: db, Logger: logger, Tasks: &tasks.BookingTasks{}, PaymentPort: paymentPort}
+	return &BookingService{
+		DB:                 db,
+		Logger:             logger,
+		Tasks:              &tasks.BookingTasks{},
+		PaymentPort:        paymentPort,
+		Inventory:          &tasks.InventoryTasks{},
+		CancellationPolicy: config.NewCancellationPolicy(),
+	}
 }
 
 
 // --- Payment Service ---
 type PaymentService struct {
-	DB     *pgxpool.Pool
-	Logger *utils.Logger
-	Tasks * tasks.PaymentTasks
+	DB        *pgxpool.Pool
+	Logger    *utils.Logger
+	Tasks     *tasks.PaymentTasks
+	QuoteTTL      time.Duration
+	TaxPolicy     types.TaxPolicy
+	InvoicePolicy types.InvoicePolicy
+	Provider      utils.PaymentProvider
+	WebhookSecret []byte
+	DepositPolicy types.DepositPolicy
 }
 
 func NewPaymentService(db *pgxpool.Pool, logger *utils.Logger) *PaymentService {
-	return &PaymentService{DB: db, Logger: logger, Tasks: &tasks.PaymentTasks{}}
+	return &PaymentService{
+		DB:            db,
+		Logger:        logger,
+		Tasks:         &tasks.PaymentTasks{},
+		QuoteTTL:      config.NewQuoteTTL(),
+		TaxPolicy:     config.NewTaxPolicy(),
+		InvoicePolicy: config.NewInvoicePolicy(),
+		Provider:      utils.NewFakePaymentProvider(),
+		WebhookSecret: config.NewWebhookSecret(),
+		DepositPolicy: config.NewDepositPolicy(),
+	}
 }
\ No newline at end of file
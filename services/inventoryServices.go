@@ -1,9 +1,14 @@
 package services
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"handworks-api/tasks"
 	"handworks-api/types"
+	"math"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -29,10 +34,18 @@ func (s *InventoryService) withTx(
 func (s *InventoryService) CreateItem(ctx context.Context, req types.CreateItemRequest) (*types.InventoryItem, error) {
 	var item types.InventoryItem
 	if err := s.withTx(ctx, func (tx pgx.Tx) error {
-		inv, err := s.Tasks.CreateInventoryItem(ctx, tx, req.Name, req.Type, req.Unit, req.Category, req.ImageURL, req.Quantity, req.Quantity)
+		inv, err := s.Tasks.CreateInventoryItem(ctx, tx, req.Name, req.Type, req.Unit, req.Category, req.ImageURL, req.Quantity, req.ReorderPoint)
 		if err != nil {
 			return err
 		}
+		if req.Quantity > 0 {
+			if _, err := s.Tasks.RecordStockMovement(ctx, tx, inv.ID, types.MovementRestock, req.Quantity, "initial stock", "", ""); err != nil {
+				return err
+			}
+			if inv, err = s.Tasks.FetchInventoryItem(ctx, tx, inv.ID); err != nil {
+				return err
+			}
+		}
 		item = *inv
 		return nil
 	}); err != nil {
@@ -54,91 +67,295 @@ func (s *InventoryService) GetItem(ctx context.Context, id string) (*types.Inven
 	}
 	return &item, nil
 }
-func (s *InventoryService) GetItems(ctx context.Context) ([]*types.InventoryItem, error) {
-	var items []*types.InventoryItem
-	if err := s.withTx(ctx, func (tx pgx.Tx) error {
-		invs, err := s.Tasks.FetchFilter(ctx, tx, &types.InventoryFilter{})
+func (s *InventoryService) GetItems(ctx context.Context, filter types.InventoryFilter) (*types.InventoryItemsResponse, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 25
+	} else if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+	if filter.MinQuantity != nil && filter.MaxQuantity != nil && *filter.MinQuantity > *filter.MaxQuantity {
+		return nil, fmt.Errorf("%w: quantity_min is greater than quantity_max", types.ErrInvalidInventoryFilter)
+	}
+
+	resp := types.InventoryItemsResponse{Limit: filter.Limit}
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		items, next, err := s.Tasks.SearchInventoryItems(ctx, tx, &filter)
 		if err != nil {
 			return err
 		}
-		items = invs
+		resp.Items = items
+		resp.NextCursor = next
 		return nil
 	}); err != nil {
 		return nil, err
 	}
-	return items, nil
+	return &resp, nil
 }
-func (s *InventoryService) ListItemsByType(ctx context.Context, itemType string) ([]*types.InventoryItem, error) {
-	var items []*types.InventoryItem
+
+func (s *InventoryService) UpdateItem(ctx context.Context, req types.UpdateItemRequest) (*types.InventoryItem, error) {
+	var item types.InventoryItem
 	if err := s.withTx(ctx, func (tx pgx.Tx) error {
-		invs, err := s.Tasks.FetchFilter(ctx, tx, &types.InventoryFilter{Type: &itemType})
+		inv, err := s.Tasks.UpdateInventoryItem(ctx, tx, &req)
 		if err != nil {
 			return err
 		}
-		items = invs
+		if req.Quantity != nil {
+			if *req.Quantity < 0 {
+				return fmt.Errorf("%w: quantity cannot be negative", types.ErrInvalidStockMovement)
+			}
+			// a new quantity is a physical count; record the difference instead of overwriting
+			if delta := *req.Quantity - inv.Quantity; delta != 0 {
+				if _, err := s.Tasks.RecordStockMovement(ctx, tx, inv.ID, types.MovementAdjust, delta, "stock count", "", req.Actor); err != nil {
+					return err
+				}
+				if inv, err = s.Tasks.FetchInventoryItem(ctx, tx, inv.ID); err != nil {
+					return err
+				}
+			}
+		}
+		item = *inv
 		return nil
 	}); err != nil {
 		return nil, err
 	}
-	return items, nil
+	return &item, nil
 }
 
-func (s *InventoryService) ListItemsByStatus(ctx context.Context, status string) ([]*types.InventoryItem, error) {
-	var items []*types.InventoryItem
+func (s *InventoryService) DeleteItem(ctx context.Context, id string) (*types.InventoryItem, error) {
+	var item types.InventoryItem
 	if err := s.withTx(ctx, func (tx pgx.Tx) error {
-		invs, err := s.Tasks.FetchFilter(ctx, tx, &types.InventoryFilter{Status: &status})
+		inv, err := s.Tasks.DeleteInventoryItem(ctx, tx, id)
 		if err != nil {
 			return err
 		}
-		items = invs
+		item = *inv
 		return nil
 	}); err != nil {
 		return nil, err
 	}
-	return items, nil
+	return &item, nil
 }
 
-func (s *InventoryService) ListItemsByCategory(ctx context.Context, category string) ([]*types.InventoryItem, error) {
-	var items []*types.InventoryItem
-	if err := s.withTx(ctx, func (tx pgx.Tx) error {
-		invs, err := s.Tasks.FetchFilter(ctx, tx, &types.InventoryFilter{Category: &category})
+func (s *InventoryService) RecordMovement(ctx context.Context, req types.CreateStockMovementRequest) (*types.StockMovementResponse, error) {
+	var resp types.StockMovementResponse
+	delta, err := tasks.MovementDelta(req.Type, req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		movement, err := s.Tasks.RecordStockMovement(ctx, tx, req.ItemID, req.Type, delta, req.Reason, "", req.Actor)
+		if err != nil {
+			return err
+		}
+		item, err := s.Tasks.FetchInventoryItem(ctx, tx, req.ItemID)
 		if err != nil {
 			return err
 		}
-		items = invs
+		resp = types.StockMovementResponse{Movement: *movement, Item: *item}
 		return nil
 	}); err != nil {
 		return nil, err
 	}
-	return items, nil
+	return &resp, nil
 }
 
-func (s *InventoryService) UpdateItem(ctx context.Context, req types.UpdateItemRequest) (*types.InventoryItem, error) {
-	var item types.InventoryItem
-	if err := s.withTx(ctx, func (tx pgx.Tx) error {
-		inv, err := s.Tasks.UpdateInventoryItem(ctx, tx, &req)
+func (s *InventoryService) GetMovements(ctx context.Context, itemID string, limit int) (*types.StockMovementsResponse, error) {
+	if limit < 0 || limit > 500 {
+		return nil, fmt.Errorf("%w: limit must be between 1 and 500", types.ErrInvalidInventoryFilter)
+	}
+	if limit == 0 {
+		limit = 100
+	}
+	var resp types.StockMovementsResponse
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		item, err := s.Tasks.FetchInventoryItem(ctx, tx, itemID)
 		if err != nil {
 			return err
 		}
-		item = *inv
+		onHand, reserved, err := s.Tasks.FetchLedgerTotals(ctx, tx, itemID)
+		if err != nil {
+			return err
+		}
+		movements, err := s.Tasks.FetchStockMovements(ctx, tx, itemID, limit)
+		if err != nil {
+			return err
+		}
+		if onHand != item.Quantity {
+			s.Logger.Warn("inventory item %s quantity %d does not match ledger %d", itemID, item.Quantity, onHand)
+		}
+		resp = types.StockMovementsResponse{
+			ItemID:         itemID,
+			Quantity:       item.Quantity,
+			LedgerQuantity: onHand,
+			Reserved:       reserved,
+			Movements:      movements,
+		}
 		return nil
 	}); err != nil {
 		return nil, err
 	}
-	return &item, nil
+	return &resp, nil
 }
 
-func (s *InventoryService) DeleteItem(ctx context.Context, id string) (*types.InventoryItem, error) {
-	var item types.InventoryItem
-	if err := s.withTx(ctx, func (tx pgx.Tx) error {
-		inv, err := s.Tasks.DeleteInventoryItem(ctx, tx, id)
+// RunReorderChecker checks stock against reorder points every interval until ctx is done.
+func (s *InventoryService) RunReorderChecker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.CheckReorderPoints(ctx); err != nil {
+			s.Logger.Error("reorder check failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckReorderPoints notifies about items whose available stock has newly fallen to their reorder
+// point or run out since the last check. Items that recover are re-armed.
+func (s *InventoryService) CheckReorderPoints(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var levels []types.StockLevel
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		l, err := s.Tasks.FetchStockLevels(ctx, tx, time.Now())
 		if err != nil {
 			return err
 		}
-		item = *inv
+		levels = l
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	s.alertMu.Lock()
+	defer s.alertMu.Unlock()
+	seen := make(map[string]bool, len(levels))
+	for _, l := range levels {
+		seen[l.ItemID] = true
+		available := l.Quantity - l.Reserved
+		level := alertLevel(available, l.ReorderPoint)
+		prev := s.alertLevels[l.ItemID]
+		if level == "" {
+			delete(s.alertLevels, l.ItemID)
+			continue
+		}
+		if alertRank(level) <= alertRank(prev) {
+			s.alertLevels[l.ItemID] = level
+			continue
+		}
+		alert := types.ReorderAlert{
+			ItemID:       l.ItemID,
+			ItemName:     l.Name,
+			Unit:         l.Unit,
+			Level:        level,
+			Available:    available,
+			ReorderPoint: l.ReorderPoint,
+			MaxQuantity:  l.MaxQuantity,
+			RaisedAt:     time.Now(),
+		}
+		if err := s.Notifier.Notify(ctx, alert); err != nil {
+			// keep the previous level so the alert is retried on the next check
+			s.Logger.Error("could not send reorder alert for %s: %v", l.Name, err)
+			continue
+		}
+		s.alertLevels[l.ItemID] = level
+	}
+	for id := range s.alertLevels {
+		if !seen[id] {
+			delete(s.alertLevels, id)
+		}
+	}
+	return nil
+}
+
+func alertLevel(available, reorderPoint int32) types.AlertLevel {
+	switch {
+	case available <= 0:
+		return types.AlertLevelOutOfStock
+	case available <= reorderPoint:
+		return types.AlertLevelReorder
+	default:
+		return ""
+	}
+}
+
+func alertRank(level types.AlertLevel) int {
+	switch level {
+	case types.AlertLevelOutOfStock:
+		return 2
+	case types.AlertLevelReorder:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// GetReorderSuggestions lists items at or below their reorder point with an order quantity sized to
+// cover the consumption seen over the last windowDays, capped at MaxQuantity. Items with no recent
+// consumption are topped up to MaxQuantity.
+func (s *InventoryService) GetReorderSuggestions(ctx context.Context, windowDays int) (*types.ReorderSuggestionsResponse, error) {
+	if windowDays <= 0 || windowDays > 365 {
+		windowDays = 30
+	}
+	now := time.Now()
+	var levels []types.StockLevel
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		l, err := s.Tasks.FetchStockLevels(ctx, tx, now.AddDate(0, 0, -windowDays))
+		if err != nil {
+			return err
+		}
+		levels = l
 		return nil
 	}); err != nil {
 		return nil, err
 	}
-	return &item, nil
+
+	suggestions := []types.ReorderSuggestion{}
+	for _, l := range levels {
+		available := l.Quantity - l.Reserved
+		if available > l.ReorderPoint {
+			continue
+		}
+		dailyUsage := float64(l.Consumed) / float64(windowDays)
+		target := l.MaxQuantity
+		var daysOfStock *float64
+		if dailyUsage > 0 {
+			target = min(l.MaxQuantity, l.ReorderPoint+int32(math.Ceil(dailyUsage*float64(windowDays))))
+			days := math.Max(float64(available), 0) / dailyUsage
+			daysOfStock = &days
+		}
+		suggested := target - max(available, 0)
+		if suggested <= 0 {
+			continue
+		}
+		suggestions = append(suggestions, types.ReorderSuggestion{
+			ItemID:            l.ItemID,
+			Name:              l.Name,
+			Unit:              l.Unit,
+			Status:            l.Status,
+			Quantity:          l.Quantity,
+			Reserved:          l.Reserved,
+			Available:         available,
+			ReorderPoint:      l.ReorderPoint,
+			MaxQuantity:       l.MaxQuantity,
+			Consumed:          l.Consumed,
+			DailyUsage:        dailyUsage,
+			DaysOfStock:       daysOfStock,
+			SuggestedQuantity: suggested,
+		})
+	}
+	// most urgent first: least available stock relative to the reorder point
+	slices.SortStableFunc(suggestions, func(a, b types.ReorderSuggestion) int {
+		return cmp.Compare(a.Available-a.ReorderPoint, b.Available-b.ReorderPoint)
+	})
+
+	return &types.ReorderSuggestionsResponse{
+		WindowDays:  windowDays,
+		GeneratedAt: now,
+		Suggestions: suggestions,
+	}, nil
 }
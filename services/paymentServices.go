@@ -2,8 +2,13 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"handworks-api/tasks"
 	"handworks-api/types"
+	"handworks-api/utils"
+	"slices"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -28,12 +33,12 @@ func (s *PaymentService) withTx(
 	return fn(tx)
 }
 
-func (s *PaymentService) GetQuotePrices(ctx context.Context, quoteId string) (*types.CleaningPrices, error) {
+func (s *PaymentService) GetQuotePrices(ctx context.Context, req *types.CreateBookingRequest) (*types.CleaningPrices, error) {
 	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	var prices *types.CleaningPrices
 	if err := s.withTx(dbCtx, func(tx pgx.Tx) error {
-		cleaningPrices, err := s.Tasks.VerifyQuoteAndFetchPrices(ctx, tx, quoteId)
+		cleaningPrices, err := s.Tasks.VerifyQuoteAndFetchPrices(ctx, tx, req)
 		if err != nil {
 			return err
 		}
@@ -48,10 +53,21 @@ func (s *PaymentService) GetQuotePrices(ctx context.Context, quoteId string) (*t
 
 func (s* PaymentService) MakePublicQuotation(ctx context.Context, req types.QuoteRequest) (*types.QuoteResponse, error) {
 	s.Logger.Info("Generating Quote Preview")
-	quotePrev, err := s.Tasks.CalculateQuotePreview(ctx, &req)
-	if err != nil {
+	var quotePrev *types.Quote
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		catalog, err := s.Tasks.FetchActiveCatalog(ctx, tx, time.Now())
+		if err != nil {
+			return err
+		}
+		q, err := s.Tasks.CalculateQuotePreview(ctx, tx, &req, catalog, s.TaxPolicy)
+		if err != nil {
+			return fmt.Errorf("failed to genearte Quote Preview: %w", err)
+		}
+		quotePrev = q
+		return nil
+	}); err != nil {
 		s.Logger.Error("Failed to genearte Quote Preview: %v", err)
-		return nil, fmt.Errorf("failed to genearte Quote Preview: %v", err)
+		return nil, err
 	}
 	addonsBreakdown := s.Tasks.MapAddonstoAddonBreakdown(&quotePrev.Addons)
 	return &types.QuoteResponse{
@@ -61,6 +77,11 @@ func (s* PaymentService) MakePublicQuotation(ctx context.Context, req types.Quot
 		TotalPrice: quotePrev.TotalPrice,
 		AddonTotal: quotePrev.AddonTotal,
 		Addons: addonsBreakdown,
+		Adjustments: quotePrev.Adjustments,
+		AdjustmentTotal: quotePrev.AdjustmentTotal,
+		Tax: quotePrev.Tax,
+		Travel: quotePrev.Travel,
+		CatalogVersion: quotePrev.CatalogVersion,
 	}, nil
 }
 
@@ -68,15 +89,25 @@ func (s* PaymentService) MakePublicQuotation(ctx context.Context, req types.Quot
 func (s *PaymentService) MakeQuotation(ctx context.Context, req types.QuoteRequest) (*types.QuoteResponse, error) {
 	var quoteResponse types.QuoteResponse
 	if err := s.withTx(ctx, func (tx pgx.Tx) error {
-		quote, err := s.Tasks.CreateQuote(ctx, tx, &req)
+		catalog, err := s.Tasks.FetchActiveCatalog(ctx, tx, time.Now())
+		if err != nil {
+			return err
+		}
+		quote, err := s.Tasks.CreateQuote(ctx, tx, &req, s.QuoteTTL, catalog, s.TaxPolicy)
 		if err != nil {
-			return fmt.Errorf("failed to create Quote: %v", err)
+			return fmt.Errorf("failed to create Quote: %w", err)
 		}
 		quoteResponse.QuoteId = quote.ID
 		quoteResponse.MainServiceName = quote.MainService
 		quoteResponse.MainServiceTotal = quote.TotalPrice
 		quoteResponse.AddonTotal = quote.AddonTotal
+		quoteResponse.Adjustments = quote.Adjustments
+		quoteResponse.AdjustmentTotal = quote.AdjustmentTotal
 		quoteResponse.TotalPrice = quote.TotalPrice
+		quoteResponse.Tax = quote.Tax
+		quoteResponse.Travel = quote.Travel
+		quoteResponse.ExpiresAt = quote.ExpiresAt
+		quoteResponse.CatalogVersion = quote.CatalogVersion
 		quoteResponse.Addons = s.Tasks.MapAddonstoAddonBreakdown(&quote.Addons)
 		return nil
 	}); err != nil {
@@ -86,7 +117,997 @@ func (s *PaymentService) MakeQuotation(ctx context.Context, req types.QuoteReque
 	return &quoteResponse, nil
 }
 
-// TODO: implement this
-func (s *PaymentService) GetAllQuotesFromCustomer(ctx context.Context, id string) (*types.QuotesResponse, error) {
-	return nil, nil
-}
\ No newline at end of file
+func (s *PaymentService) GetAllQuotesFromCustomer(ctx context.Context, filter types.QuotesFilter) (*types.QuotesResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+
+	resp := &types.QuotesResponse{
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	}
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		quotes, total, err := s.Tasks.FetchQuotesByCustomer(ctx, tx, filter.CustomerID, filter.Limit, (filter.Page-1)*filter.Limit)
+		if err != nil {
+			return err
+		}
+		resp.Quotes = quotes
+		resp.TotalCount = total
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to fetch quotes: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ConsumeQuote spends a quote on a base booking within the booking's own transaction.
+func (s *PaymentService) ConsumeQuote(ctx context.Context, tx pgx.Tx, quoteId, bookingID string) error {
+	return s.Tasks.ConsumeQuote(ctx, tx, quoteId, bookingID)
+}
+
+// ReplaceQuote swaps the quote of an edited booking within the booking's transaction.
+func (s *PaymentService) ReplaceQuote(ctx context.Context, tx pgx.Tx, oldQuoteId, newQuoteId, bookingID string) error {
+	return s.Tasks.ReplaceQuote(ctx, tx, oldQuoteId, newQuoteId, bookingID)
+}
+
+// CheckQuoteSchedule checks a quote's time window pricing against a booked slot within the booking's transaction.
+func (s *PaymentService) CheckQuoteSchedule(ctx context.Context, tx pgx.Tx, quoteId string, startSched time.Time) error {
+	return s.Tasks.CheckQuoteSchedule(ctx, tx, quoteId, startSched)
+}
+
+// IssueInvoice bills a completed booking within the booking's own transaction.
+func (s *PaymentService) IssueInvoice(ctx context.Context, tx pgx.Tx, bookingID string) error {
+	invoice, err := s.Tasks.IssueInvoice(ctx, tx, bookingID, s.InvoicePolicy)
+	if err != nil {
+		return err
+	}
+	s.Logger.Info("Issued invoice %s for booking %s", invoice.Number, bookingID)
+	return nil
+}
+
+// RunQuoteSweeper invalidates expired quotes every interval until ctx is done.
+func (s *PaymentService) RunQuoteSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.sweepQuotes(ctx); err != nil {
+			s.Logger.Error("quote sweep failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PaymentService) sweepQuotes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return s.withTx(ctx, func(tx pgx.Tx) error {
+		expired, err := s.Tasks.ExpireQuotes(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			s.Logger.Info("Expired %d quotes", expired)
+		}
+		return nil
+	})
+}
+
+func (s *PaymentService) GetQuote(ctx context.Context, id string) (*types.QuoteDetail, error) {
+	var quote *types.QuoteDetail
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		q, err := s.Tasks.FetchQuoteByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		quote = q
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+func (s *PaymentService) GetCatalogs(ctx context.Context) ([]types.PriceCatalog, error) {
+	var catalogs []types.PriceCatalog
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		c, err := s.Tasks.FetchCatalogs(ctx, tx)
+		if err != nil {
+			return err
+		}
+		catalogs = c
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return catalogs, nil
+}
+
+func (s *PaymentService) GetActiveCatalog(ctx context.Context) (*types.PriceCatalog, error) {
+	var catalog *types.PriceCatalog
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		c, err := s.Tasks.FetchActiveCatalog(ctx, tx, time.Now())
+		if err != nil {
+			return err
+		}
+		catalog = c
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+func (s *PaymentService) GetCatalog(ctx context.Context, id string) (*types.PriceCatalog, error) {
+	var catalog *types.PriceCatalog
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		c, err := s.Tasks.FetchCatalog(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		catalog = c
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// catalogEffectiveFrom defaults a request's effective date to now and rejects dates in the past,
+// which would reprice history.
+func catalogEffectiveFrom(req *types.PriceCatalogRequest) (time.Time, error) {
+	now := time.Now()
+	if req.EffectiveFrom == nil {
+		return now, nil
+	}
+	// allow for clock skew between the admin client and the server
+	if req.EffectiveFrom.Before(now.Add(-time.Minute)) {
+		return time.Time{}, fmt.Errorf("%w: effectiveFrom cannot be in the past", types.ErrInvalidCatalog)
+	}
+	return *req.EffectiveFrom, nil
+}
+
+func (s *PaymentService) CreateCatalog(ctx context.Context, req types.PriceCatalogRequest) (*types.PriceCatalog, error) {
+	effectiveFrom, err := catalogEffectiveFrom(&req)
+	if err != nil {
+		return nil, err
+	}
+	if err := tasks.ValidatePriceRates(&req.Rates); err != nil {
+		return nil, err
+	}
+	var catalog *types.PriceCatalog
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		c, err := s.Tasks.CreateCatalog(ctx, tx, effectiveFrom, req.Notes, &req.Rates, req.Actor)
+		if err != nil {
+			return err
+		}
+		catalog = c
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to create price catalog: %v", err)
+		return nil, err
+	}
+	s.Logger.Info("Price catalog version %d takes effect %s", catalog.Version, catalog.EffectiveFrom.Format(time.RFC3339))
+	return catalog, nil
+}
+
+func (s *PaymentService) UpdateCatalog(ctx context.Context, req types.PriceCatalogRequest) (*types.PriceCatalog, error) {
+	effectiveFrom, err := catalogEffectiveFrom(&req)
+	if err != nil {
+		return nil, err
+	}
+	if err := tasks.ValidatePriceRates(&req.Rates); err != nil {
+		return nil, err
+	}
+	var catalog *types.PriceCatalog
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		c, err := s.Tasks.UpdateCatalog(ctx, tx, req.ID, effectiveFrom, req.Notes, &req.Rates)
+		if err != nil {
+			return err
+		}
+		catalog = c
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+func (s *PaymentService) DeleteCatalog(ctx context.Context, id string) (*types.PriceCatalog, error) {
+	var catalog *types.PriceCatalog
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		c, err := s.Tasks.DeleteCatalog(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		catalog = c
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+func (s *PaymentService) GetPromotions(ctx context.Context) ([]types.Promotion, error) {
+	var promotions []types.Promotion
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		p, err := s.Tasks.FetchPromotions(ctx, tx)
+		if err != nil {
+			return err
+		}
+		promotions = p
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// promotionDefaults fills in the optional start date and active flag of a promotion request
+// and checks the validity window.
+func promotionDefaults(req *types.PromotionRequest) (time.Time, bool, error) {
+	validFrom, isActive := time.Now(), true
+	if req.ValidFrom != nil {
+		validFrom = *req.ValidFrom
+	}
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	if req.ValidUntil != nil && !req.ValidUntil.After(validFrom) {
+		return time.Time{}, false, fmt.Errorf("%w: validUntil must be after validFrom", types.ErrInvalidPromotion)
+	}
+	return validFrom, isActive, nil
+}
+
+func (s *PaymentService) CreatePromotion(ctx context.Context, req types.PromotionRequest) (*types.Promotion, error) {
+	if err := tasks.ValidatePromotion(&req); err != nil {
+		return nil, err
+	}
+	validFrom, isActive, err := promotionDefaults(&req)
+	if err != nil {
+		return nil, err
+	}
+	var promotion *types.Promotion
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		p, err := s.Tasks.CreatePromotion(ctx, tx, &req, validFrom, isActive)
+		if err != nil {
+			return err
+		}
+		promotion = p
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to create promotion: %v", err)
+		return nil, err
+	}
+	s.Logger.Info("Promotion %s created by %s", promotion.Code, req.Actor)
+	return promotion, nil
+}
+
+func (s *PaymentService) UpdatePromotion(ctx context.Context, req types.PromotionRequest) (*types.Promotion, error) {
+	if err := tasks.ValidatePromotion(&req); err != nil {
+		return nil, err
+	}
+	validFrom, isActive, err := promotionDefaults(&req)
+	if err != nil {
+		return nil, err
+	}
+	var promotion *types.Promotion
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		p, err := s.Tasks.UpdatePromotion(ctx, tx, &req, validFrom, isActive)
+		if err != nil {
+			return err
+		}
+		promotion = p
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *PaymentService) DeactivatePromotion(ctx context.Context, id string) (*types.Promotion, error) {
+	var promotion *types.Promotion
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		p, err := s.Tasks.DeactivatePromotion(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		promotion = p
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (s *PaymentService) GetDepots(ctx context.Context) ([]types.Depot, error) {
+	var depots []types.Depot
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		d, err := s.Tasks.FetchDepots(ctx, tx, false)
+		if err != nil {
+			return err
+		}
+		depots = d
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return depots, nil
+}
+
+func (s *PaymentService) CreateDepot(ctx context.Context, req types.DepotRequest) (*types.Depot, error) {
+	if err := tasks.ValidateDepot(&req); err != nil {
+		return nil, err
+	}
+	var depot *types.Depot
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		d, err := s.Tasks.CreateDepot(ctx, tx, &req, req.IsActive == nil || *req.IsActive)
+		if err != nil {
+			return err
+		}
+		depot = d
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to create depot: %v", err)
+		return nil, err
+	}
+	return depot, nil
+}
+
+func (s *PaymentService) UpdateDepot(ctx context.Context, req types.DepotRequest) (*types.Depot, error) {
+	if err := tasks.ValidateDepot(&req); err != nil {
+		return nil, err
+	}
+	var depot *types.Depot
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		d, err := s.Tasks.UpdateDepot(ctx, tx, &req, req.IsActive == nil || *req.IsActive)
+		if err != nil {
+			return err
+		}
+		depot = d
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return depot, nil
+}
+
+func (s *PaymentService) DeactivateDepot(ctx context.Context, id string) (*types.Depot, error) {
+	var depot *types.Depot
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		d, err := s.Tasks.DeactivateDepot(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		depot = d
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return depot, nil
+}
+
+// GetHolidays returns the holiday calendar from today on.
+func (s *PaymentService) GetHolidays(ctx context.Context) ([]types.Holiday, error) {
+	var holidays []types.Holiday
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		h, err := s.Tasks.FetchHolidays(ctx, tx, time.Now())
+		if err != nil {
+			return err
+		}
+		holidays = h
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (s *PaymentService) SaveHoliday(ctx context.Context, req types.HolidayRequest) (*types.Holiday, error) {
+	if err := tasks.ValidateHoliday(&req); err != nil {
+		return nil, err
+	}
+	var holiday *types.Holiday
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		h, err := s.Tasks.SaveHoliday(ctx, tx, &req)
+		if err != nil {
+			return err
+		}
+		holiday = h
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to save holiday: %v", err)
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func (s *PaymentService) DeleteHoliday(ctx context.Context, date string) (*types.Holiday, error) {
+	var holiday *types.Holiday
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		h, err := s.Tasks.DeleteHoliday(ctx, tx, date)
+		if err != nil {
+			return err
+		}
+		holiday = h
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func (s *PaymentService) GetInvoice(ctx context.Context, id string) (*types.Invoice, error) {
+	var invoice *types.Invoice
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		inv, err := s.Tasks.FetchInvoice(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		invoice = inv
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (s *PaymentService) GetBookingInvoice(ctx context.Context, bookingID string) (*types.Invoice, error) {
+	var invoice *types.Invoice
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		inv, err := s.Tasks.FetchInvoiceByBooking(ctx, tx, bookingID)
+		if err != nil {
+			return err
+		}
+		invoice = inv
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// GetInvoicePDF renders an invoice as a PDF and returns it with the invoice.
+func (s *PaymentService) GetInvoicePDF(ctx context.Context, id string) (*types.Invoice, []byte, error) {
+	invoice, err := s.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invoice, tasks.RenderInvoicePDF(invoice), nil
+}
+
+func (s *PaymentService) VoidInvoice(ctx context.Context, req types.VoidInvoiceRequest) (*types.Invoice, error) {
+	var invoice *types.Invoice
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		inv, err := s.Tasks.VoidInvoice(ctx, tx, req.ID, req.Reason)
+		if err != nil {
+			return err
+		}
+		invoice = inv
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to void invoice %s: %v", req.ID, err)
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// PayBooking charges towards a booking through the payment provider, the balance due unless an
+// amount is given. The payment is recorded before the provider is called and settled after, so the
+// booking is not locked while the provider works. A retry with the same idempotency key gets the
+// first attempt back.
+func (s *PaymentService) PayBooking(ctx context.Context, req types.PayBookingRequest) (*types.PaymentResponse, error) {
+	var (
+		txn     *types.Transaction
+		balance *types.BookingBalance
+		replay  bool
+	)
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		b, err := s.Tasks.FetchBookingBalance(ctx, tx, req.BookingID, true)
+		if err != nil {
+			return err
+		}
+		balance = b
+		if req.IdempotencyKey != "" {
+			existing, err := s.Tasks.FetchTransactionByKey(ctx, tx, req.IdempotencyKey)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				if existing.BookingID != b.BookingID {
+					return fmt.Errorf("%w: idempotency key was used for another booking", types.ErrInvalidPayment)
+				}
+				txn, replay = existing, true
+				return nil
+			}
+		}
+		if b.BookingStatus == types.BookingCancelled {
+			return types.ErrBookingCancelled
+		}
+
+		due := b.Balance.Sub(b.Pending)
+		amount := due
+		if req.Amount != nil {
+			if req.Amount.Currency != b.Total.Currency {
+				return fmt.Errorf("%w: booking is charged in %s", types.ErrInvalidPayment, b.Total.Currency)
+			}
+			if req.Amount.Minor <= 0 {
+				return fmt.Errorf("%w: amount must be positive", types.ErrInvalidPayment)
+			}
+			amount = *req.Amount
+		}
+		if amount.Minor > due.Minor || due.Minor <= 0 {
+			return fmt.Errorf("%w: %s due, %s pending", types.ErrOverpayment, b.Balance, b.Pending)
+		}
+
+		pending := &types.Transaction{
+			BookingID: b.BookingID,
+			Kind:      types.TransactionPayment,
+			Provider:  s.Provider.Name(),
+			Amount:    amount,
+			Method:    req.Method,
+		}
+		if req.IdempotencyKey != "" {
+			pending.IdempotencyKey = &req.IdempotencyKey
+		}
+		txn, err = s.Tasks.CreateTransaction(ctx, tx, pending)
+		return err
+	}); err != nil {
+		s.Logger.Error("Failed to start payment for booking %s: %v", req.BookingID, err)
+		return nil, err
+	}
+
+	if !replay {
+		settled, b, err := s.collect(ctx, txn)
+		if err != nil {
+			return nil, err
+		}
+		txn, balance = settled, b
+	}
+	if txn.Status == types.TransactionFailed {
+		return nil, fmt.Errorf("%w: %s", types.ErrPaymentDeclined, txn.FailureReason)
+	}
+	tasks.ApplyDeposit(s.DepositPolicy, balance)
+	return &types.PaymentResponse{Transaction: *txn, Balance: *balance}, nil
+}
+
+// collect runs a pending payment through the provider and settles it with the outcome. A provider
+// error fails the payment too, and is returned once the failure is recorded.
+func (s *PaymentService) collect(ctx context.Context, txn *types.Transaction) (*types.Transaction, *types.BookingBalance, error) {
+	var ref *string
+	intent, providerErr := s.Provider.CreateIntent(ctx, txn.ID, txn.Amount, txn.Method)
+	if providerErr == nil {
+		ref = &intent.ID
+		// kept before capturing, so a payment whose settlement is lost can be reconciled
+		if err := s.withTx(ctx, func(tx pgx.Tx) error {
+			return s.Tasks.RecordProviderRef(ctx, tx, txn.ID, intent.ID)
+		}); err != nil {
+			s.Logger.Error("Failed to record provider ref of transaction %s: %v", txn.ID, err)
+		}
+		if intent.Status == types.IntentRequiresCapture {
+			intent, providerErr = s.Provider.Capture(ctx, intent.ID)
+		}
+	}
+	status, reason := tasks.CaptureOutcome(intent, providerErr)
+
+	var (
+		settled *types.Transaction
+		balance *types.BookingBalance
+	)
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		t, err := s.Tasks.SettleTransaction(ctx, tx, txn.ID, ref, status, reason)
+		if err != nil {
+			return err
+		}
+		settled = t
+		balance, err = s.Tasks.RefreshPaymentStatus(ctx, tx, txn.BookingID)
+		return err
+	}); err != nil {
+		s.Logger.Error("Failed to settle transaction %s as %s: %v", txn.ID, status, err)
+		return nil, nil, errors.Join(err, providerErr)
+	}
+	if providerErr != nil {
+		s.Logger.Error("Payment provider failed on transaction %s: %v", txn.ID, providerErr)
+		return nil, nil, fmt.Errorf("payment provider: %w", providerErr)
+	}
+	s.Logger.Info("Payment %s of %s for booking %s %s", txn.ID, txn.Amount, txn.BookingID, status)
+	return settled, balance, nil
+}
+
+// RunPaymentReconciler settles payments and refunds left PENDING for longer than timeout every
+// interval until ctx is done.
+func (s *PaymentService) RunPaymentReconciler(ctx context.Context, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.ReconcilePayments(ctx, time.Now().Add(-timeout)); err != nil {
+			s.Logger.Error("payment reconciliation failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReconcilePayments settles the payments and refunds still PENDING that were started before
+// olderThan, whose outcome was lost when the process died, the provider hung or settling failed.
+// Each is settled from what the provider has on record (see tasks.StalePaymentOutcome and
+// tasks.StaleRefundOutcome), one at a time, so one failure does not hold up the others.
+// Transactions the provider cannot be asked about are retried on the next run.
+func (s *PaymentService) ReconcilePayments(ctx context.Context, olderThan time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var stale []types.Transaction
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		t, err := s.Tasks.FetchStaleTransactions(ctx, tx, olderThan)
+		if err != nil {
+			return err
+		}
+		stale = t
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, txn := range stale {
+		if err := s.reconcile(ctx, txn); err != nil {
+			errs = append(errs, fmt.Errorf("reconcile transaction %s: %w", txn.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reconcile settles one stale transaction from the provider's record of its intent.
+func (s *PaymentService) reconcile(ctx context.Context, txn types.Transaction) error {
+	intentID := txn.ProviderRef
+	if txn.Kind == types.TransactionRefund {
+		var payment *types.Transaction
+		if err := s.withTx(ctx, func(tx pgx.Tx) error {
+			p, err := s.Tasks.FetchTransaction(ctx, tx, *txn.PaymentID)
+			payment = p
+			return err
+		}); err != nil {
+			return err
+		}
+		intentID = payment.ProviderRef
+	}
+	var intent *types.PaymentIntent
+	if intentID != nil {
+		i, err := s.Provider.FetchStatus(ctx, *intentID)
+		if err != nil {
+			return fmt.Errorf("fetch status: %w", err)
+		}
+		intent = i
+	}
+
+	var (
+		settled *types.Transaction
+		status  types.TransactionStatus
+	)
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		current, err := s.Tasks.LockTransaction(ctx, tx, txn.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != types.TransactionPending {
+			// settled meanwhile, e.g. by a webhook
+			return nil
+		}
+		var reason string
+		if txn.Kind == types.TransactionRefund {
+			transactions, err := s.Tasks.FetchTransactions(ctx, tx, txn.BookingID)
+			if err != nil {
+				return err
+			}
+			status, reason = tasks.StaleRefundOutcome(*current, intent, transactions)
+		} else {
+			status, reason = tasks.StalePaymentOutcome(intent)
+		}
+		if settled, err = s.Tasks.SettleTransaction(ctx, tx, txn.ID, nil, status, reason); err != nil {
+			return err
+		}
+		if _, err := s.Tasks.RefreshPaymentStatus(ctx, tx, txn.BookingID); err != nil {
+			return err
+		}
+		if txn.Kind == types.TransactionRefund && status == types.TransactionSucceeded {
+			_, err = s.Tasks.IssueCreditNote(ctx, tx, settled, s.InvoicePolicy.CreditNoteSeries)
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+	if settled != nil {
+		s.Logger.Info("Reconciled %s %s of %s for booking %s as %s", txn.Kind, txn.ID, txn.Amount, txn.BookingID, status)
+	}
+	return nil
+}
+
+func (s *PaymentService) GetBookingTransactions(ctx context.Context, bookingID string) ([]types.Transaction, error) {
+	var transactions []types.Transaction
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		if _, err := s.Tasks.FetchBookingBalance(ctx, tx, bookingID, false); err != nil {
+			return err
+		}
+		t, err := s.Tasks.FetchTransactions(ctx, tx, bookingID)
+		if err != nil {
+			return err
+		}
+		transactions = t
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// HandleWebhook verifies a provider webhook against its signature and applies its event once.
+func (s *PaymentService) HandleWebhook(ctx context.Context, body []byte, signature string) (*types.WebhookResponse, error) {
+	if !utils.VerifyWebhookSignature(s.WebhookSecret, body, signature) {
+		return nil, types.ErrInvalidSignature
+	}
+	var event types.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", types.ErrInvalidWebhook, err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, fmt.Errorf("%w: id and type are required", types.ErrInvalidWebhook)
+	}
+
+	var resp *types.WebhookResponse
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		r, err := s.Tasks.ApplyWebhookEvent(ctx, tx, s.Provider.Name(), &event, body)
+		if err != nil {
+			return err
+		}
+		resp = r
+		if r.Outcome != types.WebhookApplied || event.Type != types.EventRefundSucceeded {
+			return nil
+		}
+		refund, err := s.Tasks.FetchTransaction(ctx, tx, *r.TransactionID)
+		if err != nil {
+			return err
+		}
+		_, err = s.Tasks.IssueCreditNote(ctx, tx, refund, s.InvoicePolicy.CreditNoteSeries)
+		return err
+	}); err != nil {
+		s.Logger.Error("Failed to apply webhook event %s: %v", event.ID, err)
+		return nil, err
+	}
+	if resp.Outcome == types.WebhookDiscrepancy && !resp.Duplicate {
+		s.Logger.Error("Webhook event %s (%s) contradicts settled transaction %s; recorded for review", event.ID, event.Type, *resp.TransactionID)
+	}
+	s.Logger.Info("Webhook event %s (%s): %s, duplicate %t", event.ID, event.Type, resp.Outcome, resp.Duplicate)
+	return resp, nil
+}
+
+// GetBookingBalance returns what a booking costs, what is paid and what is due now.
+func (s *PaymentService) GetBookingBalance(ctx context.Context, bookingID string) (*types.BookingBalance, error) {
+	var balance *types.BookingBalance
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		b, err := s.Tasks.FetchBookingBalance(ctx, tx, bookingID, false)
+		if err != nil {
+			return err
+		}
+		balance = b
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	tasks.ApplyDeposit(s.DepositPolicy, balance)
+	return balance, nil
+}
+
+// CheckDeposit fails with types.ErrDepositRequired while a booking's deposit is not fully paid.
+// Payments still pending at the provider do not count.
+func (s *PaymentService) CheckDeposit(ctx context.Context, tx pgx.Tx, bookingID string) error {
+	balance, err := s.Tasks.FetchBookingBalance(ctx, tx, bookingID, false)
+	if err != nil {
+		return err
+	}
+	tasks.ApplyDeposit(s.DepositPolicy, balance)
+	if balance.DepositOutstanding.Minor > 0 {
+		return fmt.Errorf("%w: %s of %s deposit outstanding", types.ErrDepositRequired, balance.DepositOutstanding, balance.Deposit)
+	}
+	return nil
+}
+
+// RefundableAmount totals what was captured for a booking and not yet refunded, within the booking's transaction.
+func (s *PaymentService) RefundableAmount(ctx context.Context, tx pgx.Tx, bookingID string) (types.Money, error) {
+	transactions, err := s.Tasks.FetchTransactions(ctx, tx, bookingID)
+	if err != nil {
+		return types.Money{}, err
+	}
+	total := types.Money{Currency: types.DefaultCurrency}
+	for _, p := range tasks.RefundablePayments(transactions) {
+		total = total.Add(p.Refundable)
+	}
+	return total, nil
+}
+
+// RefundBooking returns money paid for a booking through the payment provider, everything still
+// refundable unless an amount is given. The amount is taken from the newest payments first, one
+// refund transaction per payment, so no payment gives back more than it captured. Each refund that
+// succeeds is credited on the booking's invoice, if it has one. A retry with the same idempotency
+// key gets the first attempt back.
+func (s *PaymentService) RefundBooking(ctx context.Context, req types.RefundBookingRequest) (*types.RefundResponse, error) {
+	if !slices.Contains(types.RefundReasons, req.Reason) {
+		return nil, fmt.Errorf("%w: unknown reason %q", types.ErrInvalidRefund, req.Reason)
+	}
+	var (
+		refunds  []types.Transaction
+		payments = map[string]types.Transaction{}
+		replay   bool
+	)
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		b, err := s.Tasks.FetchBookingBalance(ctx, tx, req.BookingID, true)
+		if err != nil {
+			return err
+		}
+		if req.IdempotencyKey != "" {
+			existing, err := s.Tasks.FetchRefundsByKey(ctx, tx, req.IdempotencyKey)
+			if err != nil {
+				return err
+			}
+			if len(existing) > 0 {
+				if existing[0].BookingID != b.BookingID {
+					return fmt.Errorf("%w: idempotency key was used for another booking", types.ErrInvalidRefund)
+				}
+				refunds, replay = existing, true
+				return nil
+			}
+		}
+
+		transactions, err := s.Tasks.FetchTransactions(ctx, tx, b.BookingID)
+		if err != nil {
+			return err
+		}
+		refundable := tasks.RefundablePayments(transactions)
+		available := types.Money{Currency: b.Total.Currency}
+		for _, p := range refundable {
+			available = available.Add(p.Refundable)
+		}
+		amount := available
+		if req.Amount != nil {
+			if req.Amount.Currency != b.Total.Currency {
+				return fmt.Errorf("%w: booking is charged in %s", types.ErrInvalidRefund, b.Total.Currency)
+			}
+			if req.Amount.Minor <= 0 {
+				return fmt.Errorf("%w: amount must be positive", types.ErrInvalidRefund)
+			}
+			amount = *req.Amount
+		}
+		if amount.Minor > available.Minor || available.Minor <= 0 {
+			return fmt.Errorf("%w: %s can be refunded", types.ErrRefundExceedsCaptured, available)
+		}
+
+		for _, p := range refundable {
+			if amount.Minor <= 0 {
+				break
+			}
+			slice := p.Refundable
+			if slice.Minor > amount.Minor {
+				slice = amount
+			}
+			amount = amount.Sub(slice)
+			pending := &types.Transaction{
+				BookingID: b.BookingID,
+				Kind:      types.TransactionRefund,
+				Provider:  s.Provider.Name(),
+				Amount:    slice,
+				Method:    p.Payment.Method,
+				PaymentID: &p.Payment.ID,
+				Reason:    &req.Reason,
+				Note:      req.Note,
+			}
+			if req.IdempotencyKey != "" {
+				pending.RequestKey = &req.IdempotencyKey
+			}
+			txn, err := s.Tasks.CreateTransaction(ctx, tx, pending)
+			if err != nil {
+				return err
+			}
+			refunds = append(refunds, *txn)
+			payments[txn.ID] = p.Payment
+		}
+		return nil
+	}); err != nil {
+		s.Logger.Error("Failed to start refund for booking %s: %v", req.BookingID, err)
+		return nil, err
+	}
+
+	if !replay {
+		// one refund at a time, each settled on its own: a refund that cannot be settled stays
+		// PENDING for the reconciler and does not keep the others from going through
+		var errs []error
+		for i, txn := range refunds {
+			settled, err := s.refund(ctx, &txn, payments[txn.ID])
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			refunds[i] = *settled
+		}
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+	}
+
+	resp := &types.RefundResponse{Refunds: refunds}
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		ids := make([]string, len(refunds))
+		for i, txn := range refunds {
+			ids[i] = txn.ID
+		}
+		notes, err := s.Tasks.FetchCreditNotesByTransactions(ctx, tx, ids)
+		if err != nil {
+			return err
+		}
+		resp.CreditNotes = notes
+		b, err := s.Tasks.FetchBookingBalance(ctx, tx, req.BookingID, false)
+		if err != nil {
+			return err
+		}
+		resp.Balance = *b
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	tasks.ApplyDeposit(s.DepositPolicy, &resp.Balance)
+
+	if !slices.ContainsFunc(refunds, func(txn types.Transaction) bool { return txn.Status != types.TransactionFailed }) {
+		return nil, fmt.Errorf("%w: %s", types.ErrRefundFailed, refunds[0].FailureReason)
+	}
+	return resp, nil
+}
+
+// refund returns a pending refund through the provider, settles it with the outcome and credits
+// the invoice when it succeeded. A provider error fails the refund, and is returned once the
+// failure is recorded.
+func (s *PaymentService) refund(ctx context.Context, txn *types.Transaction, payment types.Transaction) (*types.Transaction, error) {
+	var (
+		ref    *string
+		status = types.TransactionSucceeded
+		reason string
+	)
+	returned, providerErr := s.Provider.Refund(ctx, *payment.ProviderRef, txn.Amount)
+	if providerErr != nil {
+		status, reason = types.TransactionFailed, providerErr.Error()
+	} else {
+		ref = &returned.ID
+	}
+
+	var settled *types.Transaction
+	if err := s.withTx(ctx, func(tx pgx.Tx) error {
+		t, err := s.Tasks.SettleTransaction(ctx, tx, txn.ID, ref, status, reason)
+		if err != nil {
+			return err
+		}
+		settled = t
+		if _, err := s.Tasks.RefreshPaymentStatus(ctx, tx, txn.BookingID); err != nil {
+			return err
+		}
+		if status == types.TransactionSucceeded {
+			_, err = s.Tasks.IssueCreditNote(ctx, tx, settled, s.InvoicePolicy.CreditNoteSeries)
+		}
+		return err
+	}); err != nil {
+		s.Logger.Error("Failed to settle refund %s as %s: %v", txn.ID, status, err)
+		return nil, errors.Join(err, providerErr)
+	}
+	if providerErr != nil {
+		s.Logger.Error("Payment provider failed on refund %s: %v", txn.ID, providerErr)
+	}
+	s.Logger.Info("Refund %s of %s for booking %s %s", txn.ID, txn.Amount, txn.BookingID, status)
+	return settled, nil
+}
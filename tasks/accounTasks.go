@@ -84,13 +84,31 @@ func (t *AccountTasks) FetchCustomerData(c context.Context, tx pgx.Tx, ID string
 	var customer types.Customer
 
 	if err := tx.QueryRow(c,
-		`SELECT id, account_id FROM account.customers WHERE id = $1`,
+		`SELECT id, account_id, tax_status, tax_id FROM account.customers WHERE id = $1`,
 		ID,
-	).Scan(&customer.ID, &customer.Account.ID); err != nil {
+	).Scan(&customer.ID, &customer.Account.ID, &customer.TaxStatus, &customer.TaxID); err != nil {
 		return nil, fmt.Errorf("could not query customer table: %w", err)
 	}
 	return &customer, nil
 }
+func (t *AccountTasks) UpdateCustomerTax(c context.Context, tx pgx.Tx, id string, status types.TaxStatus, taxID string) (*types.Customer, error) {
+	var customer types.Customer
+	if err := tx.QueryRow(c,
+		`UPDATE account.customers
+		 SET tax_status = $2, tax_id = $3
+		 WHERE id = $1
+		 RETURNING id, account_id, tax_status, tax_id`,
+		id, status, taxID,
+	).Scan(&customer.ID, &customer.Account.ID, &customer.TaxStatus, &customer.TaxID); err != nil {
+		return nil, fmt.Errorf("could not update customer tax status: %w", err)
+	}
+	acc, err := t.FetchAccountData(c, tx, customer.Account.ID)
+	if err != nil {
+		return nil, err
+	}
+	customer.Account = *acc
+	return &customer, nil
+}
 func (t *AccountTasks) FetchEmployeeData(c context.Context, tx pgx.Tx, ID string) (*types.Employee, error) {
 	var emp types.Employee
 
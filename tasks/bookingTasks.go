@@ -1,23 +1,46 @@
 package tasks
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"handworks-api/types"
 	"handworks-api/utils"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"golang.org/x/sync/errgroup"
 )
 
 type BookingTasks struct {}
 type PaymentPort interface {
-	GetQuotePrices(ctx context.Context, quoteId string) (*types.CleaningPrices, error)
+	// GetQuotePrices returns the prices of the booking's quote once it is known to be bookable by the
+	// booking's customer and to match its services, re-priced.
+	GetQuotePrices(ctx context.Context, req *types.CreateBookingRequest) (*types.CleaningPrices, error)
+	// ConsumeQuote spends the quote on a booking inside the booking's transaction.
+	ConsumeQuote(ctx context.Context, tx pgx.Tx, quoteId, bookingID string) error
+	// ReplaceQuote moves a booking onto a new quote for its edited job inside the booking's transaction.
+	ReplaceQuote(ctx context.Context, tx pgx.Tx, oldQuoteId, newQuoteId, bookingID string) error
+	// CheckQuoteSchedule fails with types.ErrQuoteSchedule if the quote's time based pricing does not fit a booking starting at startSched.
+	CheckQuoteSchedule(ctx context.Context, tx pgx.Tx, quoteId string, startSched time.Time) error
+	// IssueInvoice bills a completed booking inside the transaction that completes it.
+	IssueInvoice(ctx context.Context, tx pgx.Tx, bookingID string) error
+	// CheckDeposit fails with types.ErrDepositRequired while the booking's deposit is unpaid.
+	CheckDeposit(ctx context.Context, tx pgx.Tx, bookingID string) error
+	// RefundableAmount is what was captured for a booking and not yet refunded.
+	RefundableAmount(ctx context.Context, tx pgx.Tx, bookingID string) (types.Money, error)
+	// RefundBooking returns money through the payment provider, in its own transactions.
+	RefundBooking(ctx context.Context, req types.RefundBookingRequest) (*types.RefundResponse, error)
 }
 
-func (t *BookingTasks) AllocateAll(ctx context.Context, paymentPort PaymentPort, req *types.CreateBookingRequest) (*types.BookingAllocation, error) {
+func (t *BookingTasks) AllocateAll(ctx context.Context, tx pgx.Tx, paymentPort PaymentPort, req *types.CreateBookingRequest) (*types.BookingAllocation, error) {
     g, c := errgroup.WithContext(ctx)
 
     var (
@@ -28,19 +51,18 @@ func (t *BookingTasks) AllocateAll(ctx context.Context, paymentPort PaymentPort,
 
     g.Go(func() error {
         var err error
-        prices, err = paymentPort.GetQuotePrices(c, req.Base.QuoteId)
+        prices, err = paymentPort.GetQuotePrices(c, req)
         return err
     })
 
+    // a pgx.Tx cannot run queries concurrently, so allocations share one goroutine
     g.Go(func() error {
         var err error
-        alloc, err = t.AllocateEquipmentAndResources(c, req)
-        return err
-    })
-
-    g.Go(func() error {
-        var err error
-        cleaners, err = t.AllocateCleaners(c, req)
+        alloc, err = t.AllocateEquipmentAndResources(c, tx, req)
+        if err != nil {
+            return err
+        }
+        cleaners, err = t.AllocateCleaners(c, tx, req)
         return err
     })
 
@@ -64,28 +86,383 @@ func (t *BookingTasks) AllocateAll(ctx context.Context, paymentPort PaymentPort,
 }
 
 
-func (t * BookingTasks) AllocateEquipmentAndResources(ctx context.Context, req *types.CreateBookingRequest) (*types.CleaningAllocation, error) {
-	// FOR TESTING PA NI, I HAVE NOT IMPLEMENTED THE REAL LOGIC YET
-	// TODO: Automation logic for resource and equipment allocation
-	equipments := []types.CleaningEquipment{
-		{ID: "7849f478-f70b-42a7-82d2-aadc81d3e6d6", Name: "Vacuum Cleaner", Type: "Electrical", PhotoURL: "https://example.com/vacuum.jpg"},
-		{ID: "a4cd7e23-787b-4344-80d7-c50199d85ecd", Name: "Mop", Type: "Manual", PhotoURL: "https://example.com/mop.jpg"},
+// lockAllocation serializes allocation so two bookings committing at once cannot take the same cleaner or equipment.
+func lockAllocation(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('booking.allocation'))`); err != nil {
+		return fmt.Errorf("could not lock allocation: %w", err)
+	}
+	return nil
+}
+
+// requestedServices returns the main service followed by every addon of a booking request.
+func requestedServices(req *types.CreateBookingRequest) []types.ServicesRequest {
+	services := []types.ServicesRequest{req.MainService}
+	for _, addon := range req.Addons {
+		services = append(services, addon.ServiceDetail)
+	}
+	return services
+}
+
+// couchSeats reads the seat count from a couch type; OTTOMAN, LAZBOY and CHAIR count as one seat.
+func couchSeats(couchType string) int32 {
+	if rest, ok := strings.CutPrefix(couchType, "SEATER_"); ok {
+		if n, err := strconv.Atoi(strings.SplitN(rest, "_", 2)[0]); err == nil && n > 0 {
+			return int32(n)
+		}
+	}
+	return 1
+}
+
+// basisUnits counts how many units of a consumption basis a service covers.
+func basisUnits(service types.ServicesRequest, basis types.ConsumptionBasis) float64 {
+	d := service.Details
+	switch basis {
+	case types.PerJob:
+		return 1
+	case types.PerSQM:
+		if d.General != nil {
+			return float64(d.General.SQM)
+		}
+		if d.Post != nil {
+			return float64(d.Post.SQM)
+		}
+	case types.PerCouchSeat:
+		if d.Couch != nil {
+			var seats int32
+			for _, spec := range d.Couch.CleaningSpecs {
+				seats += couchSeats(spec.CouchType) * spec.Quantity
+			}
+			return float64(seats)
+		}
+	case types.PerMattress:
+		if d.Mattress != nil {
+			var units int32
+			for _, spec := range d.Mattress.CleaningSpecs {
+				units += spec.Quantity
+			}
+			return float64(units)
+		}
+	case types.PerCar:
+		if d.Car != nil {
+			var units int32
+			for _, spec := range d.Car.CleaningSpecs {
+				units += spec.Quantity
+			}
+			return float64(units)
+		}
+	}
+	return 0
+}
+
+type resourceNeed struct {
+	name     string
+	quantity int32
+}
+
+// resourceNeeds totals what every service of a booking consumes per resource, rounded up to whole units.
+func resourceNeeds(services []types.ServicesRequest) []resourceNeed {
+	totals := map[string]float64{}
+	var order []string
+	names := map[string]string{}
+	for _, service := range services {
+		for _, rule := range types.ResourceConsumptionTable[service.ServiceType] {
+			amount := rule.Amount * basisUnits(service, rule.Basis)
+			if amount <= 0 {
+				continue
+			}
+			key := strings.ToLower(rule.ItemName)
+			if _, seen := names[key]; !seen {
+				names[key] = rule.ItemName
+				order = append(order, key)
+			}
+			totals[key] += amount
+		}
+	}
+
+	needs := make([]resourceNeed, 0, len(order))
+	for _, key := range order {
+		needs = append(needs, resourceNeed{name: names[key], quantity: int32(math.Ceil(totals[key]))})
+	}
+	return needs
+}
+
+// AllocateEquipmentAndResources picks free EQUIPMENT for the booking window and RESOURCE items with enough stock.
+func (t *BookingTasks) AllocateEquipmentAndResources(ctx context.Context, tx pgx.Tx, req *types.CreateBookingRequest) (*types.CleaningAllocation, error) {
+	if err := lockAllocation(ctx, tx); err != nil {
+		return nil, err
+	}
+	services := requestedServices(req)
+
+	var equipmentNames []string
+	seen := map[string]bool{}
+	for _, service := range services {
+		for _, name := range types.EquipmentRequirements[service.ServiceType] {
+			if !seen[strings.ToLower(name)] {
+				seen[strings.ToLower(name)] = true
+				equipmentNames = append(equipmentNames, name)
+			}
+		}
+	}
+
+	var missing []string
+	equipments := []types.CleaningEquipment{}
+	if len(equipmentNames) > 0 {
+		lowered := make([]string, len(equipmentNames))
+		for i, name := range equipmentNames {
+			lowered[i] = strings.ToLower(name)
+		}
+		// an equipment row with quantity n can go out to n overlapping bookings
+		rows, err := tx.Query(ctx, `
+			SELECT i.id, i.name, i.category, i.image_url
+			FROM inventory.items i
+			LEFT JOIN booking.bookings b ON i.id = ANY(b.equipment_ids)
+			LEFT JOIN booking.basebookings bb ON bb.id = b.base_booking_id
+				AND bb.status IN ('PENDING', 'CONFIRMED', 'EN_ROUTE', 'IN_PROGRESS')
+				AND bb.start_sched < @endSched
+				AND bb.end_sched > @startSched
+				AND bb.id::text <> @baseBookingId
+			WHERE i.type = 'EQUIPMENT'
+			  AND i.is_available
+			  AND lower(i.name) = ANY(@names)
+			GROUP BY i.id
+			HAVING i.quantity - COUNT(bb.id) > 0
+			ORDER BY i.quantity - COUNT(bb.id) DESC, i.created_at
+		`, pgx.NamedArgs{
+			"startSched":    req.Base.StartSched,
+			"endSched":      req.Base.EndSched,
+			"baseBookingId": req.Base.ID,
+			"names":         lowered,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch available equipment: %w", err)
+		}
+		free := map[string]types.CleaningEquipment{}
+		for rows.Next() {
+			var eq types.CleaningEquipment
+			if err := rows.Scan(&eq.ID, &eq.Name, &eq.Type, &eq.PhotoURL); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("could not scan equipment row: %w", err)
+			}
+			if _, ok := free[strings.ToLower(eq.Name)]; !ok {
+				free[strings.ToLower(eq.Name)] = eq
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("could not fetch available equipment: %w", err)
+		}
+
+		for _, name := range equipmentNames {
+			eq, ok := free[strings.ToLower(name)]
+			if !ok {
+				missing = append(missing, fmt.Sprintf("%s (none free)", name))
+				continue
+			}
+			equipments = append(equipments, eq)
+		}
+	}
+
+	resources := []types.CleaningResources{}
+	for _, need := range resourceNeeds(services) {
+		var (
+			res   types.CleaningResources
+			stock int32
+		)
+		// stock already reserved by other bookings is not available; a rescheduled booking may reuse its own
+		err := tx.QueryRow(ctx, `
+			SELECT i.id, i.name, i.category, i.image_url, i.unit, i.quantity - COALESCE((
+				SELECT SUM(r.quantity)
+				FROM inventory.reservations r
+				JOIN booking.bookings b ON b.id = r.booking_id
+				WHERE r.item_id = i.id
+				  AND r.status = 'RESERVED'
+				  AND b.base_booking_id::text <> $2
+			), 0) AS available
+			FROM inventory.items i
+			WHERE i.type = 'RESOURCE'
+			  AND i.is_available
+			  AND lower(i.name) = lower($1)
+			ORDER BY available DESC
+			LIMIT 1
+		`, need.name, req.Base.ID).Scan(&res.ID, &res.Name, &res.Type, &res.PhotoURL, &res.Unit, &stock)
+		if errors.Is(err, pgx.ErrNoRows) {
+			missing = append(missing, fmt.Sprintf("%s (not stocked)", need.name))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch resource %s: %w", need.name, err)
+		}
+		if stock < need.quantity {
+			missing = append(missing, fmt.Sprintf("%s (need %d %s, have %d)", need.name, need.quantity, res.Unit, stock))
+			continue
+		}
+		res.Quantity = need.quantity
+		resources = append(resources, res)
 	}
-	resources := []types.CleaningResources{
-		{ID: "d1e94940-838d-4916-bf2b-bb09b77d7c46", Name: "Detergent", Type: "Chemical", PhotoURL: "https://example.com/detergent.jpg"},
+
+	if len(missing) > 0 {
+		return nil, &types.InsufficientStockError{Missing: missing}
 	}
+
 	return &types.CleaningAllocation{
 		CleaningEquipment: equipments,
 		CleaningResources: resources,
 	}, nil
 }
 
-func (t* BookingTasks) AllocateCleaners(ctx context.Context, req *types.CreateBookingRequest) ([]types.CleanerAssigned, error) {
-	// FOR TESTING PA NI, I HAVE NOT IMPLEMENTED THE REAL LOGIC YET
-	// TODO: Automation logic for cleaner assignment
-	cleaners := []types.CleanerAssigned{
-		{ID: "7aa794fa-e3f9-446f-8368-bcb55518bc29", CleanerFirstName: "Charles", CleanerLastName: "Boquecosa"},
-		{ID: "cb32d23a-31a8-4461-ba3e-228d418ba6f3", CleanerFirstName: "Clarence", CleanerLastName: "Diangco"},
+const (
+	maxCrewSize = 6
+	// each upcoming job weighs this much against a cleaner's performance score
+	workloadPenalty = 0.5
+)
+
+// CrewSize estimates how many cleaners a job needs from its services, their size and the dirty
+// scale. Add-ons are worked in the same window as the main service, so their labour adds to it
+// rather than being done by a crew of its own.
+func CrewSize(main types.ServicesRequest, addons []types.AddOnRequest, dirtyScale int32) int {
+	load := crewLoad(main)
+	for _, a := range addons {
+		load += crewLoad(a.ServiceDetail)
+	}
+	// a little slack so fractions adding up to a whole number do not round up past it
+	crew := int(math.Ceil(load - 1e-9))
+	if main.ServiceType == types.PostCleaning {
+		crew = max(2, crew)
+	}
+
+	// heavily soiled jobs get an extra pair of hands
+	if dirtyScale >= 4 {
+		crew++
+	}
+	return min(max(crew, 1), maxCrewSize)
+}
+
+// crewLoad is how many cleaners one service keeps busy, as a fraction: one per 40 sqm of general
+// cleaning, 30 sqm of post-construction, 4 couches, 3 mattresses or 2 cars.
+func crewLoad(service types.ServicesRequest) float64 {
+	var units, perCleaner int32
+	switch service.ServiceType {
+	case types.GeneralCleaning:
+		if service.Details.General != nil {
+			units, perCleaner = service.Details.General.SQM, 40
+		}
+	case types.PostCleaning:
+		if service.Details.Post != nil {
+			units, perCleaner = service.Details.Post.SQM, 30
+		}
+	case types.CouchCleaning:
+		if service.Details.Couch != nil {
+			for _, spec := range service.Details.Couch.CleaningSpecs {
+				units += spec.Quantity
+			}
+			perCleaner = 4
+		}
+	case types.MattressCleaning:
+		if service.Details.Mattress != nil {
+			for _, spec := range service.Details.Mattress.CleaningSpecs {
+				units += spec.Quantity
+			}
+			perCleaner = 3
+		}
+	case types.CarCleaning:
+		if service.Details.Car != nil {
+			for _, spec := range service.Details.Car.CleaningSpecs {
+				units += spec.Quantity
+			}
+			perCleaner = 2
+		}
+	}
+	if units <= 0 || perCleaner == 0 {
+		return 0
+	}
+	return float64(units) / float64(perCleaner)
+}
+
+type cleanerCandidate struct {
+	cleaner          types.CleanerAssigned
+	performanceScore float32
+	workload         int32
+}
+
+func (c cleanerCandidate) rank() float64 {
+	return float64(c.performanceScore) - workloadPenalty*float64(c.workload)
+}
+
+// AllocateCleaners picks the best ranked ACTIVE employees who are free for the whole booking window.
+func (t *BookingTasks) AllocateCleaners(ctx context.Context, tx pgx.Tx, req *types.CreateBookingRequest) ([]types.CleanerAssigned, error) {
+	crew := CrewSize(req.MainService, req.Addons, req.Base.DirtyScale)
+
+	if err := lockAllocation(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT e.id, a.first_name, a.last_name, e.performance_score, COUNT(bb.id) AS workload
+		FROM account.employees e
+		JOIN account.accounts a ON a.id = e.account_id
+		LEFT JOIN booking.bookings b ON e.id = ANY(b.cleaner_ids)
+		LEFT JOIN booking.basebookings bb ON bb.id = b.base_booking_id
+			AND bb.status IN ('PENDING', 'CONFIRMED', 'EN_ROUTE', 'IN_PROGRESS')
+			AND bb.end_sched >= NOW()
+		WHERE e.status = 'ACTIVE'
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM booking.bookings ob
+			JOIN booking.basebookings obb ON obb.id = ob.base_booking_id
+			WHERE e.id = ANY(ob.cleaner_ids)
+			  AND obb.status IN ('PENDING', 'CONFIRMED', 'EN_ROUTE', 'IN_PROGRESS')
+			  AND obb.start_sched < @endSched
+			  AND obb.end_sched > @startSched
+			  AND obb.id::text <> @baseBookingId
+		  )
+		GROUP BY e.id, a.first_name, a.last_name, e.performance_score
+	`, pgx.NamedArgs{
+		"startSched":    req.Base.StartSched,
+		"endSched":      req.Base.EndSched,
+		"baseBookingId": req.Base.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch available cleaners: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []cleanerCandidate
+	for rows.Next() {
+		var c cleanerCandidate
+		if err := rows.Scan(
+			&c.cleaner.ID,
+			&c.cleaner.CleanerFirstName,
+			&c.cleaner.CleanerLastName,
+			&c.performanceScore,
+			&c.workload,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan cleaner row: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not fetch available cleaners: %w", err)
+	}
+
+	if len(candidates) < crew {
+		return nil, &types.NoCapacityError{
+			Required:   crew,
+			Available:  len(candidates),
+			StartSched: req.Base.StartSched,
+			EndSched:   req.Base.EndSched,
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b cleanerCandidate) int {
+		if byRank := cmp.Compare(b.rank(), a.rank()); byRank != 0 {
+			return byRank
+		}
+		return cmp.Compare(a.cleaner.ID, b.cleaner.ID)
+	})
+
+	cleaners := make([]types.CleanerAssigned, 0, crew)
+	for _, c := range candidates[:crew] {
+		cleaners = append(cleaners, c.cleaner)
 	}
 	return cleaners, nil
 }
@@ -101,6 +478,7 @@ func (t *BookingTasks) MakeBaseBooking(
 	startSched time.Time,
 	endSched time.Time,
 	dirtyScale int32,
+	modifiers []string,
 	photos []string,
 	quoteId string,
 ) (*types.BaseBookingDetails, error) {
@@ -116,15 +494,17 @@ func (t *BookingTasks) MakeBaseBooking(
             start_sched,
             end_sched,
             dirty_scale,
+            modifiers,
             payment_status,
             review_status,
             photos,
             created_at,
             updated_at,
-            quote_id
+            quote_id,
+            status
         )
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-        RETURNING id, cust_id, customer_first_name, customer_last_name, address, start_sched, end_sched, dirty_scale, payment_status, review_status, photos, created_at, updated_at, quote_id`,
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+        RETURNING id, cust_id, customer_first_name, customer_last_name, address, start_sched, end_sched, dirty_scale, modifiers, status, payment_status, review_status, photos, created_at, updated_at, quote_id`,
 		custID,
 		customerFirstName,
 		customerLastName,
@@ -132,12 +512,14 @@ func (t *BookingTasks) MakeBaseBooking(
 		startSched,
 		endSched,
 		dirtyScale,
-		"UNPAID",
+		NormalizeModifiers(modifiers),
+		types.PaymentUnpaid,
 		"PENDING",
 		photos,
 		time.Now(),
 		time.Now(),
 		quoteId,
+		types.BookingPending,
 	).Scan(
 		&createdBaseBook.ID,
 		&createdBaseBook.CustID,
@@ -147,6 +529,8 @@ func (t *BookingTasks) MakeBaseBooking(
 		&createdBaseBook.StartSched,
 		&createdBaseBook.EndSched,
 		&createdBaseBook.DirtyScale,
+		&createdBaseBook.Modifiers,
+		&createdBaseBook.Status,
 		&createdBaseBook.PaymentStatus,
 		&createdBaseBook.ReviewStatus,
 		&createdBaseBook.Photos,
@@ -182,7 +566,19 @@ func insertServiceDetails[T any](ctx context.Context, tx pgx.Tx, serviceType typ
 	}
 	log.Debug("Raw JSON: %s", string(raw))
 
-	// Unmarshal dynamically using factory
+	out, err := decodeServiceDetails(serviceType, raw)
+	if err != nil {
+		log.Error("Unmarshal error: %v", err)
+		return nil, err
+	}
+	svc.Details = out
+	b, _ := json.MarshalIndent(svc.Details, "", "  ")
+	log.Debug("Post-Unmarshal JSON: %s", string(b))
+	return &svc, nil
+}
+
+// decodeServiceDetails unmarshals a booking.services details column using the registered factory.
+func decodeServiceDetails(serviceType types.DetailType, raw []byte) (any, error) {
 	factory, ok := types.DetailFactories[serviceType]
 	if !ok {
 		return nil, fmt.Errorf("no factory registered for service type %s", serviceType)
@@ -190,13 +586,9 @@ func insertServiceDetails[T any](ctx context.Context, tx pgx.Tx, serviceType typ
 
 	out := factory()
 	if err := json.Unmarshal(raw, out); err != nil {
-		log.Error("Unmarshal error: %v", err)
 		return nil, fmt.Errorf("unmarshal details: %w", err)
 	}
-	svc.Details = out
-	b, _ := json.MarshalIndent(svc.Details, "", "  ")
-	log.Debug("Post-Unmarshal JSON: %s", string(b))
-	return &svc, nil
+	return out, nil
 }
 
 // createMainServiceBooking converts types.ServiceDetail into DB records by using insertServiceDetails.
@@ -276,7 +668,7 @@ func (t *BookingTasks) CreateAddOn(
 	tx pgx.Tx,
 	logger * utils.Logger,
 	addonReq types.AddOnRequest,
-	addOnPrice float32,
+	addOnPrice types.Money,
 ) (*types.AddOns, error) {
 	// create underlying service row
 	addOnServiceDetails, err := t.CreateMainServiceBooking(ctx, tx, logger, addonReq.ServiceDetail.Details)
@@ -311,19 +703,20 @@ func (t *BookingTasks) CreateAddOn(
 	return createdAddon, nil
 }
 
-// saveBooking persists the booking composite row and returns the booking id.
+// saveBooking persists the booking composite row and returns the booking id. The total price is price.Gross.
 func (t *BookingTasks) SaveBooking(
 	ctx context.Context,
 	tx pgx.Tx,
 	baseBookingID, mainServiceID string,
 	addonIDs, equipmentIDs, resourceIDs, cleanerIDs []string,
-	totalPrice float32,
+	price types.TaxBreakdown,
 ) (string, error) {
 	var id string
 	query := `
 		INSERT INTO booking.bookings 
-		(base_booking_id, main_service_id, addon_ids, equipment_ids, resource_ids, cleaner_ids, total_price)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		(base_booking_id, main_service_id, addon_ids, equipment_ids, resource_ids, cleaner_ids, total_price,
+		 net_amount, tax_amount, tax_rate_bps, tax_status, prices_include_tax)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id`
 
 	err := tx.QueryRow(ctx, query,
@@ -333,11 +726,712 @@ func (t *BookingTasks) SaveBooking(
 		equipmentIDs,
 		resourceIDs,
 		cleanerIDs,
-		totalPrice,
+		price.Gross,
+		price.Net,
+		price.Tax,
+		price.RateBasisPoints,
+		price.Status,
+		price.PricesIncludeTax,
 	).Scan(&id)
 	if err != nil {
 		return "", fmt.Errorf("saveBooking: %w", err)
 	}
 
 	return id, nil
-}
\ No newline at end of file
+}
+const bookingColumns = `b.id, b.total_price, b.net_amount, b.tax_amount, b.tax_rate_bps, b.tax_status, b.prices_include_tax,
+	b.addon_ids, b.equipment_ids, b.resource_ids, b.cleaner_ids,
+	bb.id, bb.cust_id, bb.customer_first_name, bb.customer_last_name, bb.address,
+	bb.start_sched, bb.end_sched, bb.dirty_scale, bb.modifiers, bb.status, bb.payment_status, bb.review_status,
+	bb.photos, bb.created_at, bb.updated_at, bb.quote_id,
+	s.id, s.service_type, s.details
+	FROM booking.bookings b
+	JOIN booking.basebookings bb ON bb.id = b.base_booking_id
+	JOIN booking.services s ON s.id = b.main_service_id`
+
+// bookingRefs are the ids a booking row points to; the rows behind them are loaded separately.
+type bookingRefs struct {
+	addonIDs, equipmentIDs, resourceIDs, cleanerIDs []string
+}
+
+// scanBooking reads a row of bookingColumns, without the addons and allocations it refers to.
+func scanBooking(row pgx.Row) (*types.Booking, *bookingRefs, error) {
+	var (
+		booking    types.Booking
+		refs       bookingRefs
+		rawDetails []byte
+	)
+	if err := row.Scan(
+		&booking.ID,
+		&booking.TotalPrice,
+		&booking.Tax.Net,
+		&booking.Tax.Tax,
+		&booking.Tax.RateBasisPoints,
+		&booking.Tax.Status,
+		&booking.Tax.PricesIncludeTax,
+		&refs.addonIDs,
+		&refs.equipmentIDs,
+		&refs.resourceIDs,
+		&refs.cleanerIDs,
+		&booking.Base.ID,
+		&booking.Base.CustID,
+		&booking.Base.CustomerFirstName,
+		&booking.Base.CustomerLastName,
+		&booking.Base.Address,
+		&booking.Base.StartSched,
+		&booking.Base.EndSched,
+		&booking.Base.DirtyScale,
+		&booking.Base.Modifiers,
+		&booking.Base.Status,
+		&booking.Base.PaymentStatus,
+		&booking.Base.ReviewStatus,
+		&booking.Base.Photos,
+		&booking.Base.CreatedAt,
+		&booking.Base.UpdatedAt,
+		&booking.Base.QuoteId,
+		&booking.MainService.ID,
+		&booking.MainService.ServiceType,
+		&rawDetails,
+	); err != nil {
+		return nil, nil, err
+	}
+	booking.Tax.Gross = booking.TotalPrice
+
+	details, err := decodeServiceDetails(types.DetailType(booking.MainService.ServiceType), rawDetails)
+	if err != nil {
+		return nil, nil, err
+	}
+	booking.MainService.Details = details
+	return &booking, &refs, nil
+}
+
+// FetchBookingByID loads booking.bookings with its base booking, services, addons and allocations.
+// An unknown or malformed id fails with types.ErrBookingNotFound.
+func (t *BookingTasks) FetchBookingByID(ctx context.Context, tx pgx.Tx, id string) (*types.Booking, error) {
+	if err := new(pgtype.UUID).Scan(id); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrBookingNotFound, id)
+	}
+	booking, refs, err := scanBooking(tx.QueryRow(ctx, `SELECT `+bookingColumns+` WHERE b.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", types.ErrBookingNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch booking with id %s: %w", id, err)
+	}
+
+	if booking.Addons, err = t.fetchAddOns(ctx, tx, refs.addonIDs); err != nil {
+		return nil, err
+	}
+	if booking.Equipments, err = t.fetchEquipments(ctx, tx, refs.equipmentIDs); err != nil {
+		return nil, err
+	}
+	if booking.Resources, err = t.fetchResources(ctx, tx, booking.ID, refs.resourceIDs); err != nil {
+		return nil, err
+	}
+	if booking.Cleaners, err = t.fetchCleaners(ctx, tx, refs.cleanerIDs); err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// FetchBookingsByIDs loads several bookings like FetchBookingByID, in the order of ids, with one
+// query for the bookings and one for each kind of row they refer to. Unknown ids are skipped.
+func (t *BookingTasks) FetchBookingsByIDs(ctx context.Context, tx pgx.Tx, ids []string) ([]types.Booking, error) {
+	bookings := []types.Booking{}
+	if len(ids) == 0 {
+		return bookings, nil
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT `+bookingColumns+`
+		WHERE b.id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], b.id)
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch bookings: %w", err)
+	}
+	var (
+		refs                                            []*bookingRefs
+		addonIDs, equipmentIDs, cleanerIDs, bookingIDs []string
+	)
+	for rows.Next() {
+		booking, r, err := scanBooking(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("could not scan booking row: %w", err)
+		}
+		bookings = append(bookings, *booking)
+		refs = append(refs, r)
+		bookingIDs = append(bookingIDs, booking.ID)
+		addonIDs = append(addonIDs, r.addonIDs...)
+		equipmentIDs = append(equipmentIDs, r.equipmentIDs...)
+		cleanerIDs = append(cleanerIDs, r.cleanerIDs...)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not fetch bookings: %w", err)
+	}
+
+	addons, err := t.fetchAddOns(ctx, tx, addonIDs)
+	if err != nil {
+		return nil, err
+	}
+	equipments, err := t.fetchEquipments(ctx, tx, equipmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	cleaners, err := t.fetchCleaners(ctx, tx, cleanerIDs)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := t.fetchResourcesByBooking(ctx, tx, bookingIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	addonsByID := make(map[string]types.AddOns, len(addons))
+	for _, a := range addons {
+		addonsByID[a.ID] = a
+	}
+	equipmentsByID := make(map[string]types.CleaningEquipment, len(equipments))
+	for _, eq := range equipments {
+		equipmentsByID[eq.ID] = eq
+	}
+	cleanersByID := make(map[string]types.CleanerAssigned, len(cleaners))
+	for _, c := range cleaners {
+		cleanersByID[c.ID] = c
+	}
+	for i := range bookings {
+		b := &bookings[i]
+		b.Addons = pickByID(refs[i].addonIDs, addonsByID)
+		b.Equipments = pickByID(refs[i].equipmentIDs, equipmentsByID)
+		b.Cleaners = pickByID(refs[i].cleanerIDs, cleanersByID)
+		b.Resources = resources[b.ID]
+		if b.Resources == nil {
+			b.Resources = []types.CleaningResources{}
+		}
+	}
+	return bookings, nil
+}
+
+// pickByID returns the rows of ids found in byID, in the order of ids.
+func pickByID[T any](ids []string, byID map[string]T) []T {
+	picked := make([]T, 0, len(ids))
+	for _, id := range ids {
+		if row, ok := byID[id]; ok {
+			picked = append(picked, row)
+		}
+	}
+	return picked
+}
+
+// FetchBookingIDsByCustomer returns one page of a customer's booking ids, newest schedule first, and the total match count.
+func (t *BookingTasks) FetchBookingIDsByCustomer(
+	ctx context.Context,
+	tx pgx.Tx,
+	custID string,
+	startDate, endDate *time.Time,
+	paymentStatus, reviewStatus string,
+	limit, offset int32,
+) ([]string, int32, error) {
+	args := pgx.NamedArgs{
+		"custId":        custID,
+		"startDate":     startDate,
+		"endDate":       endDate,
+		"paymentStatus": paymentStatus,
+		"reviewStatus":  reviewStatus,
+		"limit":         limit,
+		"offset":        offset,
+	}
+	where := `
+		WHERE bb.cust_id = @custId
+		  AND (@startDate::timestamptz IS NULL OR bb.start_sched >= @startDate)
+		  AND (@endDate::timestamptz IS NULL OR bb.start_sched < @endDate)
+		  AND (@paymentStatus = '' OR bb.payment_status = @paymentStatus)
+		  AND (@reviewStatus = '' OR bb.review_status = @reviewStatus)`
+
+	var total int32
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM booking.bookings b
+		JOIN booking.basebookings bb ON bb.id = b.base_booking_id`+where, args).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("could not count bookings: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT b.id
+		FROM booking.bookings b
+		JOIN booking.basebookings bb ON bb.id = b.base_booking_id`+where+`
+		ORDER BY bb.start_sched DESC, b.id
+		LIMIT @limit OFFSET @offset`, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not fetch bookings: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, 0, fmt.Errorf("could not scan booking row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("could not fetch bookings: %w", err)
+	}
+
+	return ids, total, nil
+}
+
+func (t *BookingTasks) fetchAddOns(ctx context.Context, tx pgx.Tx, ids []string) ([]types.AddOns, error) {
+	addons := []types.AddOns{}
+	if len(ids) == 0 {
+		return addons, nil
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT a.id, a.price, s.id, s.service_type, s.details
+		FROM booking.addons a
+		JOIN booking.services s ON s.id = a.service_id
+		WHERE a.id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], a.id)
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch addons: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var addon types.AddOns
+		var raw []byte
+		if err := rows.Scan(
+			&addon.ID,
+			&addon.Price,
+			&addon.ServiceDetail.ID,
+			&addon.ServiceDetail.ServiceType,
+			&raw,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan addon row: %w", err)
+		}
+		details, err := decodeServiceDetails(types.DetailType(addon.ServiceDetail.ServiceType), raw)
+		if err != nil {
+			return nil, err
+		}
+		addon.ServiceDetail.Details = details
+		addons = append(addons, addon)
+	}
+	return addons, rows.Err()
+}
+
+func (t *BookingTasks) fetchEquipments(ctx context.Context, tx pgx.Tx, ids []string) ([]types.CleaningEquipment, error) {
+	equipments := []types.CleaningEquipment{}
+	if len(ids) == 0 {
+		return equipments, nil
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT id, name, category, image_url
+		FROM inventory.items
+		WHERE id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], id)
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch equipments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eq types.CleaningEquipment
+		if err := rows.Scan(&eq.ID, &eq.Name, &eq.Type, &eq.PhotoURL); err != nil {
+			return nil, fmt.Errorf("could not scan equipment row: %w", err)
+		}
+		equipments = append(equipments, eq)
+	}
+	return equipments, rows.Err()
+}
+
+func (t *BookingTasks) fetchResources(ctx context.Context, tx pgx.Tx, bookingID string, ids []string) ([]types.CleaningResources, error) {
+	resources := []types.CleaningResources{}
+	if len(ids) == 0 {
+		return resources, nil
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT i.id, i.name, i.category, i.image_url, i.unit, COALESCE((
+			SELECT SUM(r.quantity)
+			FROM inventory.reservations r
+			WHERE r.item_id = i.id AND r.booking_id = $2 AND r.status <> 'RELEASED'
+		), 0)
+		FROM inventory.items i
+		WHERE i.id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], i.id)
+	`, ids, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch resources: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var res types.CleaningResources
+		if err := rows.Scan(&res.ID, &res.Name, &res.Type, &res.PhotoURL, &res.Unit, &res.Quantity); err != nil {
+			return nil, fmt.Errorf("could not scan resource row: %w", err)
+		}
+		resources = append(resources, res)
+	}
+	return resources, rows.Err()
+}
+
+func (t *BookingTasks) fetchCleaners(ctx context.Context, tx pgx.Tx, ids []string) ([]types.CleanerAssigned, error) {
+	cleaners := []types.CleanerAssigned{}
+	if len(ids) == 0 {
+		return cleaners, nil
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT e.id, a.first_name, a.last_name
+		FROM account.employees e
+		JOIN account.accounts a ON a.id = e.account_id
+		WHERE e.id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], e.id)
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch cleaners: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cleaner types.CleanerAssigned
+		if err := rows.Scan(&cleaner.ID, &cleaner.CleanerFirstName, &cleaner.CleanerLastName); err != nil {
+			return nil, fmt.Errorf("could not scan cleaner row: %w", err)
+		}
+		cleaners = append(cleaners, cleaner)
+	}
+	return cleaners, rows.Err()
+}
+
+// fetchResourcesByBooking loads the resources allocated to several bookings with what each has
+// reserved of them, by booking id, like fetchResources does for one booking.
+func (t *BookingTasks) fetchResourcesByBooking(ctx context.Context, tx pgx.Tx, bookingIDs []string) (map[string][]types.CleaningResources, error) {
+	resources := map[string][]types.CleaningResources{}
+	if len(bookingIDs) == 0 {
+		return resources, nil
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT b.id, i.id, i.name, i.category, i.image_url, i.unit, COALESCE((
+			SELECT SUM(r.quantity)
+			FROM inventory.reservations r
+			WHERE r.item_id = i.id AND r.booking_id = b.id AND r.status <> 'RELEASED'
+		), 0)
+		FROM booking.bookings b
+		CROSS JOIN LATERAL unnest(b.resource_ids) WITH ORDINALITY AS u(item_id, ord)
+		JOIN inventory.items i ON i.id = u.item_id::uuid
+		WHERE b.id = ANY($1::uuid[])
+		ORDER BY b.id, u.ord
+	`, bookingIDs)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch resources: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			bookingID string
+			res       types.CleaningResources
+		)
+		if err := rows.Scan(&bookingID, &res.ID, &res.Name, &res.Type, &res.PhotoURL, &res.Unit, &res.Quantity); err != nil {
+			return nil, fmt.Errorf("could not scan resource row: %w", err)
+		}
+		resources[bookingID] = append(resources[bookingID], res)
+	}
+	return resources, rows.Err()
+}
+
+// ServiceRequestFromDetails turns a decoded booking.services row back into the request shape used for allocation and pricing.
+func ServiceRequestFromDetails(svc types.ServiceDetails) types.ServicesRequest {
+	req := types.ServicesRequest{ServiceType: types.MainServiceType(svc.ServiceType)}
+	switch d := svc.Details.(type) {
+	case *types.GeneralCleaningDetails:
+		req.Details.General = d
+	case *types.CouchCleaningDetails:
+		req.Details.Couch = d
+	case *types.MattressCleaningDetails:
+		req.Details.Mattress = d
+	case *types.CarCleaningDetails:
+		req.Details.Car = d
+	case *types.PostConstructionDetails:
+		req.Details.Post = d
+	}
+	return req
+}
+
+// UpdateBaseBooking overwrites the editable columns of booking.basebookings.
+func (t *BookingTasks) UpdateBaseBooking(
+	ctx context.Context,
+	tx pgx.Tx,
+	id string,
+	address types.Address,
+	startSched time.Time,
+	endSched time.Time,
+	dirtyScale int32,
+	modifiers []string,
+	photos []string,
+	quoteId string,
+) (*types.BaseBookingDetails, error) {
+	var base types.BaseBookingDetails
+
+	err := tx.QueryRow(ctx, `
+		UPDATE booking.basebookings
+		SET address = $2, start_sched = $3, end_sched = $4, dirty_scale = $5, modifiers = $6, photos = $7, quote_id = $8, updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, cust_id, customer_first_name, customer_last_name, address, start_sched, end_sched, dirty_scale, modifiers, status, payment_status, review_status, photos, created_at, updated_at, quote_id`,
+		id,
+		address,
+		startSched,
+		endSched,
+		dirtyScale,
+		NormalizeModifiers(modifiers),
+		photos,
+		quoteId,
+	).Scan(
+		&base.ID,
+		&base.CustID,
+		&base.CustomerFirstName,
+		&base.CustomerLastName,
+		&base.Address,
+		&base.StartSched,
+		&base.EndSched,
+		&base.DirtyScale,
+		&base.Modifiers,
+		&base.Status,
+		&base.PaymentStatus,
+		&base.ReviewStatus,
+		&base.Photos,
+		&base.CreatedAt,
+		&base.UpdatedAt,
+		&base.QuoteId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update base booking: %w", err)
+	}
+
+	return &base, nil
+}
+
+// DeleteAddOns removes addon rows together with their underlying service rows.
+func (t *BookingTasks) DeleteAddOns(ctx context.Context, tx pgx.Tx, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	rows, err := tx.Query(ctx, `
+		DELETE FROM booking.addons
+		WHERE id = ANY($1::uuid[])
+		RETURNING service_id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to delete addons: %w", err)
+	}
+	var serviceIDs []string
+	for rows.Next() {
+		var serviceID string
+		if err := rows.Scan(&serviceID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan deleted addon: %w", err)
+		}
+		serviceIDs = append(serviceIDs, serviceID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to delete addons: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM booking.services WHERE id = ANY($1::uuid[])`, serviceIDs); err != nil {
+		return fmt.Errorf("failed to delete addon services: %w", err)
+	}
+	return nil
+}
+
+// UpdateBookingAllocation replaces the addon, allocation arrays and price of booking.bookings.
+func (t *BookingTasks) UpdateBookingAllocation(
+	ctx context.Context,
+	tx pgx.Tx,
+	bookingID string,
+	addonIDs, equipmentIDs, resourceIDs, cleanerIDs []string,
+	price types.TaxBreakdown,
+) error {
+	cmdTag, err := tx.Exec(ctx, `
+		UPDATE booking.bookings
+		SET addon_ids = $2, equipment_ids = $3, resource_ids = $4, cleaner_ids = $5, total_price = $6,
+			net_amount = $7, tax_amount = $8, tax_rate_bps = $9, tax_status = $10, prices_include_tax = $11
+		WHERE id = $1`,
+		bookingID,
+		addonIDs,
+		equipmentIDs,
+		resourceIDs,
+		cleanerIDs,
+		price.Gross,
+		price.Net,
+		price.Tax,
+		price.RateBasisPoints,
+		price.Status,
+		price.PricesIncludeTax,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update booking allocation: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("no booking found with id %s", bookingID)
+	}
+	return nil
+}
+
+// CalculateRefund picks the policy tier with the longest notice the cancellation still qualifies for
+// and applies its percentage to refundable, what was captured for the booking and not yet refunded.
+func (t *BookingTasks) CalculateRefund(policy types.CancellationPolicy, startSched, now time.Time, refundable types.Money) (int32, types.Money) {
+	tiers := slices.Clone(policy.Tiers)
+	slices.SortFunc(tiers, func(a, b types.CancellationTier) int {
+		return cmp.Compare(b.MinHoursBefore, a.MinHoursBefore)
+	})
+
+	hoursBefore := startSched.Sub(now).Hours()
+	for _, tier := range tiers {
+		if hoursBefore >= tier.MinHoursBefore {
+			return tier.RefundPercent, refundable.Percent(int64(tier.RefundPercent))
+		}
+	}
+	return 0, types.Money{Currency: refundable.Currency}
+}
+
+// CancelBooking releases the allocation of a booking already moved to CANCELLED and records the cancellation.
+func (t *BookingTasks) CancelBooking(
+	ctx context.Context,
+	tx pgx.Tx,
+	booking *types.Booking,
+	cancelledBy, reason string,
+	refundPercent int32,
+	refundAmount types.Money,
+) (time.Time, error) {
+	// keep what was released so the cancellation record shows the original allocation
+	var equipmentIDs, resourceIDs, cleanerIDs []string
+	if err := tx.QueryRow(ctx, `
+		SELECT equipment_ids, resource_ids, cleaner_ids
+		FROM booking.bookings
+		WHERE id = $1
+		FOR UPDATE`, booking.ID,
+	).Scan(&equipmentIDs, &resourceIDs, &cleanerIDs); err != nil {
+		return time.Time{}, fmt.Errorf("failed to fetch booking allocation: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE booking.bookings
+		SET equipment_ids = '{}', resource_ids = '{}', cleaner_ids = '{}'
+		WHERE id = $1`, booking.ID); err != nil {
+		return time.Time{}, fmt.Errorf("failed to release booking allocation: %w", err)
+	}
+
+	var cancelledAt time.Time
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO booking.cancellations
+		(booking_id, cancelled_by, reason, refund_percent, refund_amount, released_equipment_ids, released_resource_ids, released_cleaner_ids)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING cancelled_at`,
+		booking.ID,
+		cancelledBy,
+		reason,
+		refundPercent,
+		refundAmount,
+		equipmentIDs,
+		resourceIDs,
+		cleanerIDs,
+	).Scan(&cancelledAt); err != nil {
+		return time.Time{}, fmt.Errorf("failed to record cancellation: %w", err)
+	}
+
+	return cancelledAt, nil
+}
+
+// ValidateTransition checks a status change against types.BookingTransitions.
+func (t *BookingTasks) ValidateTransition(from, to types.BookingStatus) error {
+	if slices.Contains(types.BookingTransitions[from], to) {
+		return nil
+	}
+	return &types.IllegalTransitionError{From: from, To: to}
+}
+
+// UpdateBookingStatus moves a booking between statuses, guarding against a concurrent change of the current status.
+func (t *BookingTasks) UpdateBookingStatus(
+	ctx context.Context,
+	tx pgx.Tx,
+	baseBookingID string,
+	from, to types.BookingStatus,
+) error {
+	if err := t.ValidateTransition(from, to); err != nil {
+		return err
+	}
+	cmdTag, err := tx.Exec(ctx, `
+		UPDATE booking.basebookings
+		SET status = $3, updated_at = NOW()
+		WHERE id = $1 AND status = $2`,
+		baseBookingID, from, to,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update booking status: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return &types.IllegalTransitionError{From: from, To: to}
+	}
+	return nil
+}
+
+// RecordStatusChange appends an entry to booking.status_history.
+func (t *BookingTasks) RecordStatusChange(
+	ctx context.Context,
+	tx pgx.Tx,
+	bookingID string,
+	from, to types.BookingStatus,
+	actor, note string,
+) (*types.BookingStatusChange, error) {
+	change := types.BookingStatusChange{
+		BookingID:  bookingID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		Note:       note,
+	}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO booking.status_history (booking_id, from_status, to_status, actor, note)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		bookingID, from, to, actor, note,
+	).Scan(&change.ID, &change.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to record status change: %w", err)
+	}
+	return &change, nil
+}
+
+// FetchStatusHistory returns the status trail of a booking, oldest first.
+func (t *BookingTasks) FetchStatusHistory(ctx context.Context, tx pgx.Tx, bookingID string) ([]types.BookingStatusChange, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, booking_id, from_status, to_status, actor, note, created_at
+		FROM booking.status_history
+		WHERE booking_id = $1
+		ORDER BY created_at, id
+	`, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []types.BookingStatusChange{}
+	for rows.Next() {
+		var change types.BookingStatusChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.BookingID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.Actor,
+			&change.Note,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("could not scan status history row: %w", err)
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
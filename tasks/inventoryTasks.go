@@ -2,10 +2,17 @@ package tasks
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"handworks-api/types"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 
@@ -15,16 +22,17 @@ func (t *InventoryTasks) CreateInventoryItem(
 	c context.Context,
 	tx pgx.Tx,
 	name, itemType, unit, category, imageUrl string,
-	quantity, maxQuantity int32,
+	maxQuantity int32,
+	reorderPoint *int32,
 ) (*types.InventoryItem, error) {
 	var item types.InventoryItem
 
 	if err := tx.QueryRow(c,
 		`INSERT INTO inventory.items
-		 (name, type, unit, quantity, max_quantity, category ,image_url, is_available)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, true)
-		 RETURNING id, name, type, status, unit, category, quantity, max_quantity, image_url, is_available, created_at, updated_at`,
-		name, itemType, unit, quantity, maxQuantity, category, imageUrl,
+		 (name, type, unit, quantity, max_quantity, reorder_point, category ,image_url, is_available, status)
+		 VALUES ($1, $2, $3, 0, $4, $5, $6, $7, true, $8)
+		 RETURNING id, name, type, status, unit, category, quantity, max_quantity, reorder_point, image_url, is_available, created_at, updated_at`,
+		name, itemType, unit, maxQuantity, reorderPoint, category, imageUrl, types.ItemStatusOutOfStock,
 	).Scan(
 		&item.ID,
 		&item.Name,
@@ -34,6 +42,7 @@ func (t *InventoryTasks) CreateInventoryItem(
 		&item.Category,
 		&item.Quantity,
 		&item.MaxQuantity,
+		&item.ReorderPoint,
 		&item.ImageURL,
 		&item.IsAvailable,
 		&item.CreatedAt,
@@ -51,8 +60,11 @@ func (t *InventoryTasks) FetchInventoryItem(
 ) (*types.InventoryItem, error) {
 	var item types.InventoryItem
 
+	if err := new(pgtype.UUID).Scan(id); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrItemNotFound, id)
+	}
 	if err := tx.QueryRow(c,
-		`SELECT id, name, type, status, unit, quantity, max_quantity, is_available, created_at, updated_at
+		`SELECT id, name, type, status, unit, category, quantity, max_quantity, reorder_point, image_url, is_available, created_at, updated_at
 		 FROM inventory.items
 		 WHERE id = $1`,
 		id,
@@ -62,165 +74,156 @@ func (t *InventoryTasks) FetchInventoryItem(
 		&item.Type,
 		&item.Status,
 		&item.Unit,
+		&item.Category,
 		&item.Quantity,
 		&item.MaxQuantity,
+		&item.ReorderPoint,
+		&item.ImageURL,
 		&item.IsAvailable,
 		&item.CreatedAt,
 		&item.UpdatedAt,
 	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", types.ErrItemNotFound, id)
+		}
 		return nil, fmt.Errorf("could not fetch inventory item with id %s: %w", id, err)
 	}
 
 	return &item, nil
 }
-func (t *InventoryTasks) FetchInventoryItems(
-	ctx context.Context,
-	tx pgx.Tx,
-) ([]*types.InventoryItem, error) {
-	rows, err := tx.Query(ctx, `
-        SELECT id, name, type, status, unit, category, quantity, max_quantity, is_available, created_at, updated_at
-        FROM inventory.items
-        ORDER BY created_at DESC
-    `)
-	if err != nil {
-		return nil, fmt.Errorf("could not fetch inventory items: %w", err)
-	}
-	defer rows.Close()
+// inventorySorts maps a sort key to its column and the type a cursor value for it is cast to.
+var inventorySorts = map[string][2]string{
+	"name":       {"name", "text"},
+	"quantity":   {"quantity", "int"},
+	"status":     {"status", "text"},
+	"created_at": {"created_at", "timestamptz"},
+	"updated_at": {"updated_at", "timestamptz"},
+}
 
-	var items []*types.InventoryItem
-	for rows.Next() {
-		var item types.InventoryItem
-		if err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&item.Type,
-			&item.Status,
-			&item.Unit,
-			&item.Category,
-			&item.Quantity,
-			&item.MaxQuantity,
-			&item.IsAvailable,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("could not scan inventory row: %w", err)
-		}
-		items = append(items, &item)
-	}
+const defaultInventorySort = "-created_at"
 
-	return items, nil
+// inventoryCursor marks the last item of a page: its sort value and id, plus the sort it belongs to.
+type inventoryCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
 }
-func (t *InventoryTasks) FetchFilter(ctx context.Context, tx pgx.Tx, filter * types.InventoryFilter) ([]*types.InventoryItem, error) {
-	var items []*types.InventoryItem
-	var err error
-	if filter.Type != nil && *filter.Type != "" {
-    items, err = t.FetchInventoryItemsByType(ctx, tx, *filter.Type)
-	} else if filter.Status != nil && *filter.Status != "" {
-		items, err = t.FetchInventoryItemsByStatus(ctx, tx, *filter.Status)
-	} else if filter.Category != nil && *filter.Category != "" {
-		items, err = t.FetchInventoryItemsByCategory(ctx, tx, *filter.Category)
-	} else {
-		items, err = t.FetchInventoryItems(ctx, tx)
-	}
-
-	return items, err
-	
+
+// parseInventorySort splits a sort such as "-quantity" into its key and direction.
+func parseInventorySort(sort string) (string, bool, error) {
+	if sort == "" {
+		sort = defaultInventorySort
+	}
+	key, desc := strings.CutPrefix(sort, "-")
+	if _, ok := inventorySorts[key]; !ok {
+		return "", false, fmt.Errorf("%w: unknown sort %q", types.ErrInvalidInventoryFilter, sort)
+	}
+	return key, desc, nil
 }
-func (t *InventoryTasks) FetchInventoryItemsByType(
-	ctx context.Context,
-	tx pgx.Tx,
-	itemType string,
-) ([]*types.InventoryItem, error) {
-	rows, err := tx.Query(ctx, `
-        SELECT id, name, type, status, unit, category, quantity, max_quantity, is_available, created_at, updated_at
-        FROM inventory.items
-		WHERE type = $1
-        ORDER BY created_at DESC
-    `, itemType)
-	if err != nil {
-		return nil, fmt.Errorf("could not fetch inventory items: %w", err)
+
+func inventorySortValue(item *types.InventoryItem, key string) string {
+	switch key {
+	case "name":
+		return item.Name
+	case "quantity":
+		return strconv.Itoa(int(item.Quantity))
+	case "status":
+		return string(item.Status)
+	case "updated_at":
+		return item.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return item.CreatedAt.Format(time.RFC3339Nano)
 	}
-	defer rows.Close()
+}
 
-	var items []*types.InventoryItem
-	for rows.Next() {
-		var item types.InventoryItem
-		if err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&item.Type,
-			&item.Status,
-			&item.Unit,
-			&item.Category,
-			&item.Quantity,
-			&item.MaxQuantity,
-			&item.IsAvailable,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("could not scan inventory row: %w", err)
+// filterValues splits a comma separated filter into upper-cased values.
+func filterValues(raw string) []string {
+	values := []string{}
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, strings.ToUpper(v))
 		}
-		items = append(items, &item)
 	}
-
-	return items, nil
+	return values
 }
-func (t *InventoryTasks) FetchInventoryItemsByCategory(
+
+// SearchInventoryItems returns one page of items matching every set field of the filter, in the
+// filter's sort order, and the cursor for the next page ("" on the last page).
+func (t *InventoryTasks) SearchInventoryItems(
 	ctx context.Context,
 	tx pgx.Tx,
-	category string,
-) ([]*types.InventoryItem, error) {
-	rows, err := tx.Query(ctx, `
-        SELECT id, name, type, status, unit, category, quantity, max_quantity, is_available, created_at, updated_at
-        FROM inventory.items
-		WHERE category = $1
-        ORDER BY created_at DESC
-    `, category)
+	filter *types.InventoryFilter,
+) ([]*types.InventoryItem, string, error) {
+	sortKey, desc, err := parseInventorySort(filter.Sort)
 	if err != nil {
-		return nil, fmt.Errorf("could not fetch inventory items: %w", err)
+		return nil, "", err
+	}
+	column, cast := inventorySorts[sortKey][0], inventorySorts[sortKey][1]
+	sort := sortKey
+	if desc {
+		sort = "-" + sortKey
 	}
-	defer rows.Close()
 
-	var items []*types.InventoryItem
-	for rows.Next() {
-		var item types.InventoryItem
-		if err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&item.Type,
-			&item.Status,
-			&item.Unit,
-			&item.Category,
-			&item.Quantity,
-			&item.MaxQuantity,
-			&item.IsAvailable,
-			&item.CreatedAt,
-			&item.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("could not scan inventory row: %w", err)
+	var after *inventoryCursor
+	if filter.Cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: malformed cursor", types.ErrInvalidInventoryFilter)
+		}
+		after = &inventoryCursor{}
+		if err := json.Unmarshal(raw, after); err != nil || after.ID == "" {
+			return nil, "", fmt.Errorf("%w: malformed cursor", types.ErrInvalidInventoryFilter)
+		}
+		if after.Sort != sort {
+			return nil, "", fmt.Errorf("%w: cursor was issued for sort %q", types.ErrInvalidInventoryFilter, after.Sort)
 		}
-		items = append(items, &item)
 	}
 
-	return items, nil
-}
-func (t *InventoryTasks) FetchInventoryItemsByStatus(
-	ctx context.Context,
-	tx pgx.Tx,
-	status string,
-) ([]*types.InventoryItem, error) {
-	rows, err := tx.Query(ctx, `
-        SELECT id, name, type, status, unit, category, quantity, max_quantity, is_available, created_at, updated_at
-        FROM inventory.items
-		WHERE status = $1
-        ORDER BY created_at DESC
-    `, status)
+	var name *string
+	if filter.Name != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(filter.Name)
+		name = &escaped
+	}
+	args := pgx.NamedArgs{
+		"types":       filterValues(filter.Type),
+		"statuses":    filterValues(filter.Status),
+		"categories":  filterValues(filter.Category),
+		"available":   filter.Available,
+		"name":        name,
+		"minQuantity": filter.MinQuantity,
+		"maxQuantity": filter.MaxQuantity,
+		"cursorValue": nil,
+		"cursorId":    nil,
+		"limit":       filter.Limit + 1,
+	}
+	if after != nil {
+		args["cursorValue"] = after.Value
+		args["cursorId"] = after.ID
+	}
+
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		SELECT id, name, type, status, unit, category, quantity, max_quantity, reorder_point, image_url, is_available, created_at, updated_at
+		FROM inventory.items
+		WHERE (cardinality(@types::text[]) = 0 OR type = ANY(@types))
+		  AND (cardinality(@statuses::text[]) = 0 OR status = ANY(@statuses))
+		  AND (cardinality(@categories::text[]) = 0 OR category = ANY(@categories))
+		  AND (@available::bool IS NULL OR is_available = @available)
+		  AND (@name::text IS NULL OR name ILIKE '%%' || @name || '%%')
+		  AND (@minQuantity::int IS NULL OR quantity >= @minQuantity)
+		  AND (@maxQuantity::int IS NULL OR quantity <= @maxQuantity)
+		  AND (@cursorId::uuid IS NULL OR (%[1]s, id) %[3]s (@cursorValue::%[2]s, @cursorId))
+		ORDER BY %[1]s %[4]s, id %[4]s
+		LIMIT @limit`, column, cast, comparison, direction), args)
 	if err != nil {
-		return nil, fmt.Errorf("could not fetch inventory items: %w", err)
+		return nil, "", fmt.Errorf("could not fetch inventory items: %w", err)
 	}
 	defer rows.Close()
 
-	var items []*types.InventoryItem
+	items := []*types.InventoryItem{}
 	for rows.Next() {
 		var item types.InventoryItem
 		if err := rows.Scan(
@@ -232,16 +235,30 @@ func (t *InventoryTasks) FetchInventoryItemsByStatus(
 			&item.Category,
 			&item.Quantity,
 			&item.MaxQuantity,
+			&item.ReorderPoint,
+			&item.ImageURL,
 			&item.IsAvailable,
 			&item.CreatedAt,
 			&item.UpdatedAt,
 		); err != nil {
-			return nil, fmt.Errorf("could not scan inventory row: %w", err)
+			return nil, "", fmt.Errorf("could not scan inventory row: %w", err)
 		}
 		items = append(items, &item)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("could not fetch inventory items: %w", err)
+	}
 
-	return items, nil
+	if int32(len(items)) <= filter.Limit {
+		return items, "", nil
+	}
+	items = items[:filter.Limit]
+	last := items[len(items)-1]
+	raw, err := json.Marshal(inventoryCursor{Sort: sort, Value: inventorySortValue(last, sortKey), ID: last.ID})
+	if err != nil {
+		return nil, "", fmt.Errorf("could not encode cursor: %w", err)
+	}
+	return items, base64.RawURLEncoding.EncodeToString(raw), nil
 }
 func (t *InventoryTasks) UpdateInventoryItem(
 	ctx context.Context,
@@ -249,13 +266,12 @@ func (t *InventoryTasks) UpdateInventoryItem(
 	in *types.UpdateItemRequest,
 ) (*types.InventoryItem, error) {
 	args := pgx.NamedArgs{
-		"id":           in.ID,
-		"name":         in.Name,
-		"type":         in.Type,
-		"status":       in.Status,
-		"category":     in.Category,
-		"quantity":     in.Quantity,
-		"max_quantity": in.MaxQuantity,
+		"id":            in.ID,
+		"name":          in.Name,
+		"type":          in.Type,
+		"category":      in.Category,
+		"max_quantity":  in.MaxQuantity,
+		"reorder_point": in.ReorderPoint,
 	}
 
 	row := tx.QueryRow(ctx, `
@@ -263,13 +279,12 @@ func (t *InventoryTasks) UpdateInventoryItem(
 		SET
 			name = COALESCE(NULLIF(@name, ''), name),
 			type = COALESCE(NULLIF(@type, ''), type),
-			status = COALESCE(NULLIF(@status, ''), status),
 			category = COALESCE(NULLIF(@category, ''), category),
-			quantity = COALESCE(NULLIF(@quantity, '')::int, quantity),
-			max_quantity = COALESCE(NULLIF(@max_quantity, '')::int, max_quantity),
+			max_quantity = COALESCE(NULLIF(@max_quantity::int, 0), max_quantity),
+			reorder_point = COALESCE(@reorder_point::int, reorder_point),
 			updated_at = NOW()
 		WHERE id = @id
-		RETURNING id, name, type, status, unit, category, quantity, max_quantity, is_available, created_at, updated_at
+		RETURNING id, name, type, status, unit, category, quantity, max_quantity, reorder_point, is_available, created_at, updated_at
 	`, args)
 
 	var item types.InventoryItem
@@ -282,6 +297,7 @@ func (t *InventoryTasks) UpdateInventoryItem(
 		&item.Category,
 		&item.Quantity,
 		&item.MaxQuantity,
+		&item.ReorderPoint,
 		&item.IsAvailable,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -289,6 +305,12 @@ func (t *InventoryTasks) UpdateInventoryItem(
 		return nil, fmt.Errorf("could not update inventory item: %w", err)
 	}
 
+	status, err := t.RefreshItemStatus(ctx, tx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	item.Status = status
+
 	return &item, nil
 }
 func (t *InventoryTasks) DeleteInventoryItem(
@@ -301,7 +323,7 @@ func (t *InventoryTasks) DeleteInventoryItem(
 	err := tx.QueryRow(ctx, `
 		DELETE FROM inventory.items
 		WHERE id = $1
-		RETURNING id, name, type, status, unit, category, quantity, max_quantity, is_available, created_at, updated_at
+		RETURNING id, name, type, status, unit, category, quantity, max_quantity, reorder_point, is_available, created_at, updated_at
 	`, id).Scan(
 		&item.ID,
 		&item.Name,
@@ -311,6 +333,7 @@ func (t *InventoryTasks) DeleteInventoryItem(
 		&item.Category,
 		&item.Quantity,
 		&item.MaxQuantity,
+		&item.ReorderPoint,
 		&item.IsAvailable,
 		&item.CreatedAt,
 		&item.UpdatedAt,
@@ -321,4 +344,332 @@ func (t *InventoryTasks) DeleteInventoryItem(
 	}
 
 	return &item, nil
+}
+
+// ComputeItemStatus derives the stock level from how full an item is relative to its MaxQuantity.
+func ComputeItemStatus(quantity, maxQuantity int32) types.ItemStatus {
+	if quantity <= 0 {
+		return types.ItemStatusOutOfStock
+	}
+	if maxQuantity <= 0 {
+		return types.ItemStatusHigh
+	}
+	ratio := float64(quantity) / float64(maxQuantity)
+	switch {
+	case ratio <= 0.2:
+		return types.ItemStatusDanger
+	case ratio <= 0.5:
+		return types.ItemStatusLow
+	default:
+		return types.ItemStatusHigh
+	}
+}
+
+// RefreshItemStatus recomputes and stores the status of an item from its current quantity.
+func (t *InventoryTasks) RefreshItemStatus(ctx context.Context, tx pgx.Tx, id string) (types.ItemStatus, error) {
+	var quantity, maxQuantity int32
+	if err := tx.QueryRow(ctx, `
+		SELECT quantity, max_quantity
+		FROM inventory.items
+		WHERE id = $1
+		FOR UPDATE`, id,
+	).Scan(&quantity, &maxQuantity); err != nil {
+		return "", fmt.Errorf("could not fetch inventory item with id %s: %w", id, err)
+	}
+
+	status := ComputeItemStatus(quantity, maxQuantity)
+	if _, err := tx.Exec(ctx, `
+		UPDATE inventory.items
+		SET status = $2
+		WHERE id = $1 AND status IS DISTINCT FROM $2`, id, status); err != nil {
+		return "", fmt.Errorf("could not update inventory item status: %w", err)
+	}
+	return status, nil
+}
+
+// ReserveStock holds resource quantities for a booking, failing if any item lacks unreserved stock.
+func (t *InventoryTasks) ReserveStock(ctx context.Context, tx pgx.Tx, bookingID string, resources []types.CleaningResources) ([]types.StockReservation, error) {
+	var (
+		missing      []string
+		reservations []types.StockReservation
+	)
+	for _, res := range resources {
+		if res.Quantity <= 0 {
+			continue
+		}
+		var available int32
+		if err := tx.QueryRow(ctx, `
+			SELECT i.quantity - COALESCE((
+				SELECT SUM(r.quantity)
+				FROM inventory.reservations r
+				WHERE r.item_id = i.id AND r.status = 'RESERVED'
+			), 0)
+			FROM inventory.items i
+			WHERE i.id = $1
+			FOR UPDATE OF i`, res.ID,
+		).Scan(&available); err != nil {
+			return nil, fmt.Errorf("could not fetch stock of %s: %w", res.Name, err)
+		}
+		if available < res.Quantity {
+			missing = append(missing, fmt.Sprintf("%s (need %d %s, have %d)", res.Name, res.Quantity, res.Unit, available))
+			continue
+		}
+
+		reservation := types.StockReservation{
+			ItemID:    res.ID,
+			BookingID: bookingID,
+			Quantity:  res.Quantity,
+		}
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO inventory.reservations (item_id, booking_id, quantity, status)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, status, created_at, updated_at`,
+			res.ID, bookingID, res.Quantity, types.ReservationReserved,
+		).Scan(&reservation.ID, &reservation.Status, &reservation.CreatedAt, &reservation.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("could not reserve %s: %w", res.Name, err)
+		}
+		reservations = append(reservations, reservation)
+
+		if _, err := t.RecordStockMovement(ctx, tx, res.ID, types.MovementReserve, res.Quantity, "reserved for booking", bookingID, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, &types.InsufficientStockError{Missing: missing}
+	}
+	return reservations, nil
+}
+
+// closeReservations moves a booking's open reservations to the given status and returns them.
+func (t *InventoryTasks) closeReservations(ctx context.Context, tx pgx.Tx, bookingID string, status types.ReservationStatus) ([]types.StockReservation, error) {
+	rows, err := tx.Query(ctx, `
+		UPDATE inventory.reservations
+		SET status = $2, updated_at = NOW()
+		WHERE booking_id = $1 AND status = 'RESERVED'
+		RETURNING id, item_id, booking_id, quantity, status, created_at, updated_at`,
+		bookingID, status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not update reservations: %w", err)
+	}
+	defer rows.Close()
+
+	var reservations []types.StockReservation
+	for rows.Next() {
+		var r types.StockReservation
+		if err := rows.Scan(&r.ID, &r.ItemID, &r.BookingID, &r.Quantity, &r.Status, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan reservation row: %w", err)
+		}
+		reservations = append(reservations, r)
+	}
+	return reservations, rows.Err()
+}
+
+// ShortfallReason starts the reason of the ADJUST movements that cover consumption beyond the stock
+// on hand, so they can be found when reconciling against physical counts.
+const ShortfallReason = "STOCK_SHORTFALL"
+
+// ConsumeReservations turns a completed booking's reservations into consumption, taking them off stock.
+func (t *InventoryTasks) ConsumeReservations(ctx context.Context, tx pgx.Tx, bookingID string) error {
+	reservations, err := t.closeReservations(ctx, tx, bookingID, types.ReservationConsumed)
+	if err != nil {
+		return err
+	}
+	for _, r := range reservations {
+		if _, err := t.RecordStockMovement(ctx, tx, r.ItemID, types.MovementReserve, -r.Quantity, "consumed by booking", bookingID, ""); err != nil {
+			return err
+		}
+
+		// stock may have been adjusted down since the reservation was made. The job still used the
+		// full quantity, so the missing stock is booked back in with a flagged ADJUST for a recount
+		// and the consumption is recorded in full.
+		var onHand int32
+		if err := tx.QueryRow(ctx, `SELECT quantity FROM inventory.items WHERE id = $1`, r.ItemID).Scan(&onHand); err != nil {
+			return fmt.Errorf("could not fetch stock of %s: %w", r.ItemID, err)
+		}
+		if shortfall := r.Quantity - max(onHand, 0); shortfall > 0 {
+			reason := fmt.Sprintf("%s: booking used %d with %d on hand, recount needed", ShortfallReason, r.Quantity, onHand)
+			if _, err := t.RecordStockMovement(ctx, tx, r.ItemID, types.MovementAdjust, shortfall, reason, bookingID, ""); err != nil {
+				return err
+			}
+		}
+		if _, err := t.RecordStockMovement(ctx, tx, r.ItemID, types.MovementConsume, -r.Quantity, "used on booking", bookingID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleaseReservations frees the stock held by a cancelled or rescheduled booking.
+func (t *InventoryTasks) ReleaseReservations(ctx context.Context, tx pgx.Tx, bookingID string) error {
+	reservations, err := t.closeReservations(ctx, tx, bookingID, types.ReservationReleased)
+	if err != nil {
+		return err
+	}
+	for _, r := range reservations {
+		if _, err := t.RecordStockMovement(ctx, tx, r.ItemID, types.MovementReserve, -r.Quantity, "released by booking", bookingID, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MovementDelta turns the positive quantity of a manual movement into the signed change it makes
+// to stock. ADJUST is already signed. RESERVE is left to bookings.
+func MovementDelta(movementType types.MovementType, quantity int32) (int32, error) {
+	switch movementType {
+	case types.MovementRestock, types.MovementReturn:
+		if quantity <= 0 {
+			return 0, fmt.Errorf("%w: %s quantity must be positive", types.ErrInvalidStockMovement, movementType)
+		}
+		return quantity, nil
+	case types.MovementConsume, types.MovementDamage:
+		if quantity <= 0 {
+			return 0, fmt.Errorf("%w: %s quantity must be positive", types.ErrInvalidStockMovement, movementType)
+		}
+		return -quantity, nil
+	case types.MovementAdjust:
+		if quantity == 0 {
+			return 0, fmt.Errorf("%w: ADJUST quantity must not be zero", types.ErrInvalidStockMovement)
+		}
+		return quantity, nil
+	case types.MovementReserve:
+		return 0, fmt.Errorf("%w: RESERVE movements are only made by bookings", types.ErrInvalidStockMovement)
+	default:
+		return 0, fmt.Errorf("%w: unknown movement type %q", types.ErrInvalidStockMovement, movementType)
+	}
+}
+
+// RecordStockMovement appends a movement to the ledger and applies its signed delta to the item,
+// refusing to take stock below zero. RESERVE movements only track holds and leave quantity alone.
+func (t *InventoryTasks) RecordStockMovement(
+	ctx context.Context,
+	tx pgx.Tx,
+	itemID string,
+	movementType types.MovementType,
+	delta int32,
+	reason, referenceID, actor string,
+) (*types.StockMovement, error) {
+	if delta == 0 {
+		return nil, fmt.Errorf("%w: quantity must not be zero", types.ErrInvalidStockMovement)
+	}
+
+	if movementType != types.MovementReserve {
+		var quantity int32
+		if err := tx.QueryRow(ctx, `
+			SELECT quantity
+			FROM inventory.items
+			WHERE id = $1
+			FOR UPDATE`, itemID,
+		).Scan(&quantity); err != nil {
+			return nil, fmt.Errorf("could not fetch inventory item with id %s: %w", itemID, err)
+		}
+		if quantity+delta < 0 {
+			return nil, fmt.Errorf("%w: item %s has %d, cannot apply %d", types.ErrNegativeStock, itemID, quantity, delta)
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE inventory.items
+			SET quantity = quantity + $2, updated_at = NOW()
+			WHERE id = $1`, itemID, delta); err != nil {
+			return nil, fmt.Errorf("could not update stock of %s: %w", itemID, err)
+		}
+		if _, err := t.RefreshItemStatus(ctx, tx, itemID); err != nil {
+			return nil, err
+		}
+	}
+
+	var m types.StockMovement
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO inventory.stock_movements (item_id, movement_type, quantity, reason, reference_id, actor)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6)
+		RETURNING id, item_id, movement_type, quantity, reason, reference_id::text, actor, created_at`,
+		itemID, movementType, delta, reason, referenceID, actor,
+	).Scan(&m.ID, &m.ItemID, &m.Type, &m.Quantity, &m.Reason, &m.ReferenceID, &m.Actor, &m.CreatedAt); err != nil {
+		return nil, fmt.Errorf("could not record stock movement: %w", err)
+	}
+	return &m, nil
+}
+
+// FetchStockMovements returns an item's most recent movements, newest first.
+func (t *InventoryTasks) FetchStockMovements(ctx context.Context, tx pgx.Tx, itemID string, limit int) ([]types.StockMovement, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, item_id, movement_type, quantity, reason, reference_id::text, actor, created_at
+		FROM inventory.stock_movements
+		WHERE item_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2`, itemID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	movements := []types.StockMovement{}
+	for rows.Next() {
+		var m types.StockMovement
+		if err := rows.Scan(&m.ID, &m.ItemID, &m.Type, &m.Quantity, &m.Reason, &m.ReferenceID, &m.Actor, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("could not scan stock movement row: %w", err)
+		}
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+// FetchLedgerTotals sums an item's ledger into its on-hand quantity and what is still held for bookings.
+func (t *InventoryTasks) FetchLedgerTotals(ctx context.Context, tx pgx.Tx, itemID string) (onHand, reserved int32, err error) {
+	if err := tx.QueryRow(ctx, `
+		SELECT
+			COALESCE(SUM(quantity) FILTER (WHERE movement_type <> 'RESERVE'), 0),
+			COALESCE(SUM(quantity) FILTER (WHERE movement_type = 'RESERVE'), 0)
+		FROM inventory.stock_movements
+		WHERE item_id = $1`, itemID,
+	).Scan(&onHand, &reserved); err != nil {
+		return 0, 0, fmt.Errorf("could not sum stock movements: %w", err)
+	}
+	return onHand, reserved, nil
+}
+
+// EffectiveReorderPoint is the item's reorder point, or half of MaxQuantity (where it turns LOW) when unset.
+func EffectiveReorderPoint(reorderPoint *int32, maxQuantity int32) int32 {
+	if reorderPoint != nil {
+		return *reorderPoint
+	}
+	return maxQuantity / 2
+}
+
+// FetchStockLevels returns every item's stock, its reservations and how much was consumed since the given time.
+func (t *InventoryTasks) FetchStockLevels(ctx context.Context, tx pgx.Tx, since time.Time) ([]types.StockLevel, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT
+			i.id, i.name, i.unit, i.status, i.quantity, i.max_quantity, i.reorder_point,
+			COALESCE((
+				SELECT SUM(r.quantity)
+				FROM inventory.reservations r
+				WHERE r.item_id = i.id AND r.status = 'RESERVED'
+			), 0),
+			COALESCE((
+				SELECT -SUM(m.quantity)
+				FROM inventory.stock_movements m
+				WHERE m.item_id = i.id AND m.movement_type = 'CONSUME' AND m.created_at >= $1
+			), 0)
+		FROM inventory.items i
+		ORDER BY i.name`, since)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch stock levels: %w", err)
+	}
+	defer rows.Close()
+
+	var levels []types.StockLevel
+	for rows.Next() {
+		var (
+			l            types.StockLevel
+			reorderPoint *int32
+		)
+		if err := rows.Scan(&l.ItemID, &l.Name, &l.Unit, &l.Status, &l.Quantity, &l.MaxQuantity, &reorderPoint, &l.Reserved, &l.Consumed); err != nil {
+			return nil, fmt.Errorf("could not scan stock level row: %w", err)
+		}
+		l.ReorderPoint = EffectiveReorderPoint(reorderPoint, l.MaxQuantity)
+		levels = append(levels, l)
+	}
+	return levels, rows.Err()
 }
\ No newline at end of file
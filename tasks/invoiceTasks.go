@@ -0,0 +1,481 @@
+package tasks
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"handworks-api/types"
+	"handworks-api/utils"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// IssueInvoice bills a booking with the next number of the policy's series. The lines are the
+// booking's main service and addons and the adjustment lines of its quote; the tax is what the
+// booking was charged. A booking paid in full gets a PAID invoice. A booking is invoiced once, so
+// issuing again returns its invoice.
+func (t *PaymentTasks) IssueInvoice(ctx context.Context, tx pgx.Tx, bookingID string, policy types.InvoicePolicy) (*types.Invoice, error) {
+	if existing, err := t.FetchInvoiceByBooking(ctx, tx, bookingID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, types.ErrInvoiceNotFound) {
+		return nil, err
+	}
+
+	var (
+		inv         types.Invoice
+		address     types.Address
+		first, last string
+		quoteID     string
+		serviceType string
+		mainPrice   types.Money
+		addonIDs    []string
+		paid        types.PaymentStatus
+	)
+	if err := tx.QueryRow(ctx, `
+		SELECT bb.cust_id, bb.customer_first_name, bb.customer_last_name, bb.address, q.id::text,
+			s.service_type, q.subtotal, b.addon_ids, bb.payment_status,
+			b.total_price, b.net_amount, b.tax_amount, b.tax_rate_bps, b.tax_status, b.prices_include_tax
+		FROM booking.bookings b
+		JOIN booking.basebookings bb ON bb.id = b.base_booking_id
+		JOIN booking.services s ON s.id = b.main_service_id
+		JOIN payment.quotes q ON q.id::text = bb.quote_id::text
+		WHERE b.id = $1
+	`, bookingID).Scan(
+		&inv.CustomerID,
+		&first,
+		&last,
+		&address,
+		&quoteID,
+		&serviceType,
+		&mainPrice,
+		&addonIDs,
+		&paid,
+		&inv.Tax.Gross,
+		&inv.Tax.Net,
+		&inv.Tax.Tax,
+		&inv.Tax.RateBasisPoints,
+		&inv.Tax.Status,
+		&inv.Tax.PricesIncludeTax,
+	); err != nil {
+		return nil, fmt.Errorf("fetch booking %s to invoice: %w", bookingID, err)
+	}
+	inv.BookingID = bookingID
+	inv.CustomerName = strings.TrimSpace(first + " " + last)
+	inv.BillingAddress = address.AddressHuman
+	inv.Status = types.InvoiceIssued
+
+	inv.Lines = []types.InvoiceLine{{
+		Kind:        types.InvoiceLineService,
+		Description: serviceLabel(serviceType),
+		Amount:      mainPrice,
+	}}
+	rows, err := tx.Query(ctx, `
+		SELECT s.service_type, a.price
+		FROM booking.addons a
+		JOIN booking.services s ON s.id = a.service_id
+		WHERE a.id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], a.id)
+	`, addonIDs)
+	if err != nil {
+		return nil, fmt.Errorf("fetch addons to invoice: %w", err)
+	}
+	for rows.Next() {
+		var (
+			addonType string
+			price     types.Money
+		)
+		if err := rows.Scan(&addonType, &price); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan addon to invoice: %w", err)
+		}
+		inv.Lines = append(inv.Lines, types.InvoiceLine{
+			Kind:        types.InvoiceLineAddon,
+			Description: "Add-on: " + serviceLabel(addonType),
+			Amount:      price,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("fetch addons to invoice: %w", err)
+	}
+	adjustments, err := t.fetchQuoteAdjustments(ctx, tx, []string{quoteID})
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range adjustments[quoteID] {
+		inv.Lines = append(inv.Lines, types.InvoiceLine{
+			Kind:        types.InvoiceLineKind(a.Kind),
+			Description: a.Label,
+			Amount:      a.Amount,
+		})
+	}
+	for _, l := range inv.Lines {
+		inv.Subtotal = inv.Subtotal.Add(l.Amount)
+	}
+
+	number, err := t.nextInvoiceNumber(ctx, tx, policy.Series)
+	if err != nil {
+		return nil, err
+	}
+	inv.Series = policy.Series
+	inv.Number = fmt.Sprintf("%s-%06d", policy.Series, number)
+	inv.IssuedAt = time.Now()
+	inv.DueAt = inv.IssuedAt.Add(policy.DueIn)
+	if paid == types.PaymentPaid {
+		inv.Status = types.InvoicePaid
+		inv.PaidAt = &inv.IssuedAt
+	}
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO payment.invoices (series, number, invoice_no, booking_id, customer_id, customer_name, billing_address,
+			subtotal, net_amount, tax_amount, total_amount, tax_rate_bps, tax_status, prices_include_tax, issued_at, due_at, status, paid_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		RETURNING id
+	`,
+		inv.Series,
+		number,
+		inv.Number,
+		inv.BookingID,
+		inv.CustomerID,
+		inv.CustomerName,
+		inv.BillingAddress,
+		inv.Subtotal,
+		inv.Tax.Net,
+		inv.Tax.Tax,
+		inv.Tax.Gross,
+		inv.Tax.RateBasisPoints,
+		inv.Tax.Status,
+		inv.Tax.PricesIncludeTax,
+		inv.IssuedAt,
+		inv.DueAt,
+		inv.Status,
+		inv.PaidAt,
+	).Scan(&inv.ID); err != nil {
+		return nil, fmt.Errorf("insert invoice: %w", err)
+	}
+	for i, l := range inv.Lines {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO payment.invoice_lines (invoice_id, position, kind, description, amount)
+			VALUES ($1, $2, $3, $4, $5)
+		`, inv.ID, i+1, l.Kind, l.Description, l.Amount); err != nil {
+			return nil, fmt.Errorf("insert invoice line: %w", err)
+		}
+	}
+	return &inv, nil
+}
+
+// nextInvoiceNumber takes the next number of a series. The series row stays locked until the
+// caller's transaction ends, so numbers are handed out in order and a rollback gives its number back.
+func (t *PaymentTasks) nextInvoiceNumber(ctx context.Context, tx pgx.Tx, series string) (int64, error) {
+	var number int64
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO payment.invoice_series (series, last_number)
+		VALUES ($1, 1)
+		ON CONFLICT (series) DO UPDATE SET last_number = payment.invoice_series.last_number + 1
+		RETURNING last_number
+	`, series).Scan(&number); err != nil {
+		return 0, fmt.Errorf("next invoice number in %s: %w", series, err)
+	}
+	return number, nil
+}
+
+// serviceLabel turns a service type such as GENERAL_CLEANING into "General Cleaning".
+func serviceLabel(serviceType string) string {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(serviceType, "_", " ")))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+const invoiceColumns = `id, invoice_no, series, booking_id::text, customer_id, customer_name, billing_address, status,
+	subtotal, net_amount, tax_amount, total_amount, tax_rate_bps, tax_status, prices_include_tax,
+	issued_at, due_at, paid_at, voided_at, void_reason`
+
+func scanInvoice(row pgx.Row) (*types.Invoice, error) {
+	var inv types.Invoice
+	if err := row.Scan(
+		&inv.ID,
+		&inv.Number,
+		&inv.Series,
+		&inv.BookingID,
+		&inv.CustomerID,
+		&inv.CustomerName,
+		&inv.BillingAddress,
+		&inv.Status,
+		&inv.Subtotal,
+		&inv.Tax.Net,
+		&inv.Tax.Tax,
+		&inv.Tax.Gross,
+		&inv.Tax.RateBasisPoints,
+		&inv.Tax.Status,
+		&inv.Tax.PricesIncludeTax,
+		&inv.IssuedAt,
+		&inv.DueAt,
+		&inv.PaidAt,
+		&inv.VoidedAt,
+		&inv.VoidReason,
+	); err != nil {
+		return nil, err
+	}
+	inv.Lines = []types.InvoiceLine{}
+	inv.CreditNotes = []types.CreditNote{}
+	return &inv, nil
+}
+
+// fetchInvoice loads one invoice matching where, which takes $1, with its lines.
+func (t *PaymentTasks) fetchInvoice(ctx context.Context, tx pgx.Tx, where, arg string) (*types.Invoice, error) {
+	if err := new(pgtype.UUID).Scan(arg); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrInvoiceNotFound, arg)
+	}
+	inv, err := scanInvoice(tx.QueryRow(ctx, `SELECT `+invoiceColumns+` FROM payment.invoices WHERE `+where, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", types.ErrInvoiceNotFound, arg)
+		}
+		return nil, fmt.Errorf("fetch invoice: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT kind, description, amount
+		FROM payment.invoice_lines
+		WHERE invoice_id = $1
+		ORDER BY position
+	`, inv.ID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch invoice lines: %w", err)
+	}
+	for rows.Next() {
+		var l types.InvoiceLine
+		if err := rows.Scan(&l.Kind, &l.Description, &l.Amount); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan invoice line: %w", err)
+		}
+		inv.Lines = append(inv.Lines, l)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("fetch invoice lines: %w", err)
+	}
+	inv.CreditNotes, err = t.fetchCreditNotes(ctx, tx, "c.invoice_id = $1", inv.ID)
+	if err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+func (t *PaymentTasks) FetchInvoice(ctx context.Context, tx pgx.Tx, id string) (*types.Invoice, error) {
+	return t.fetchInvoice(ctx, tx, "id = $1", id)
+}
+
+func (t *PaymentTasks) FetchInvoiceByBooking(ctx context.Context, tx pgx.Tx, bookingID string) (*types.Invoice, error) {
+	return t.fetchInvoice(ctx, tx, "booking_id = $1", bookingID)
+}
+
+// VoidInvoice cancels an issued invoice. Its number stays used, so the series has no gaps.
+func (t *PaymentTasks) VoidInvoice(ctx context.Context, tx pgx.Tx, id, reason string) (*types.Invoice, error) {
+	inv, err := t.FetchInvoice(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := tx.Exec(ctx, `
+		UPDATE payment.invoices
+		SET status = $2, voided_at = NOW(), void_reason = $3, updated_at = NOW()
+		WHERE id = $1 AND status = $4
+	`, id, types.InvoiceVoid, reason, types.InvoiceIssued)
+	if err != nil {
+		return nil, fmt.Errorf("void invoice: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, fmt.Errorf("%w: %s is %s", types.ErrInvoiceNotVoidable, inv.Number, inv.Status)
+	}
+	return t.FetchInvoice(ctx, tx, id)
+}
+
+// IssueCreditNote credits the booking's invoice with a succeeded refund, numbered next in series,
+// and splits it into net and tax in the invoice's proportion. A refund is credited once, so issuing
+// again returns its credit note. Without an invoice, or with a void one, there is nothing to
+// credit and the result is nil.
+func (t *PaymentTasks) IssueCreditNote(ctx context.Context, tx pgx.Tx, refund *types.Transaction, series string) (*types.CreditNote, error) {
+	if refund.Kind != types.TransactionRefund || refund.Status != types.TransactionSucceeded || refund.Reason == nil {
+		return nil, fmt.Errorf("only succeeded refunds are credited, transaction %s is a %s %s", refund.ID, refund.Status, refund.Kind)
+	}
+	existing, err := t.fetchCreditNotes(ctx, tx, "c.transaction_id = $1", refund.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return &existing[0], nil
+	}
+	inv, err := t.FetchInvoiceByBooking(ctx, tx, refund.BookingID)
+	if errors.Is(err, types.ErrInvoiceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status == types.InvoiceVoid {
+		return nil, nil
+	}
+
+	note := types.CreditNote{
+		InvoiceID:     inv.ID,
+		InvoiceNumber: inv.Number,
+		BookingID:     refund.BookingID,
+		TransactionID: refund.ID,
+		Reason:        *refund.Reason,
+		Amount:        refund.Amount,
+		Tax:           types.Money{Currency: refund.Amount.Currency},
+	}
+	if inv.Tax.Gross.Minor > 0 {
+		note.Tax = refund.Amount.Ratio(inv.Tax.Tax.Minor, inv.Tax.Gross.Minor)
+	}
+	note.Net = note.Amount.Sub(note.Tax)
+
+	number, err := t.nextInvoiceNumber(ctx, tx, series)
+	if err != nil {
+		return nil, err
+	}
+	note.Number = fmt.Sprintf("%s-%06d", series, number)
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO payment.credit_notes (series, number, credit_note_no, invoice_id, booking_id, transaction_id, reason,
+			net_amount, tax_amount, total_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, issued_at
+	`,
+		series,
+		number,
+		note.Number,
+		note.InvoiceID,
+		note.BookingID,
+		note.TransactionID,
+		note.Reason,
+		note.Net,
+		note.Tax,
+		note.Amount,
+	).Scan(&note.ID, &note.IssuedAt); err != nil {
+		return nil, fmt.Errorf("insert credit note: %w", err)
+	}
+	return &note, nil
+}
+
+// FetchCreditNotesByTransactions returns the credit notes of the given refunds, in number order.
+func (t *PaymentTasks) FetchCreditNotesByTransactions(ctx context.Context, tx pgx.Tx, transactionIDs []string) ([]types.CreditNote, error) {
+	return t.fetchCreditNotes(ctx, tx, "c.transaction_id = ANY($1::uuid[])", transactionIDs)
+}
+
+// fetchCreditNotes loads the credit notes matching where, which takes $1, in number order.
+func (t *PaymentTasks) fetchCreditNotes(ctx context.Context, tx pgx.Tx, where string, arg any) ([]types.CreditNote, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT c.id, c.credit_note_no, c.invoice_id::text, i.invoice_no, c.booking_id::text, c.transaction_id::text,
+			c.reason, c.total_amount, c.net_amount, c.tax_amount, c.issued_at
+		FROM payment.credit_notes c
+		JOIN payment.invoices i ON i.id = c.invoice_id
+		WHERE `+where+`
+		ORDER BY c.series, c.number
+	`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("fetch credit notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []types.CreditNote{}
+	for rows.Next() {
+		var n types.CreditNote
+		if err := rows.Scan(
+			&n.ID,
+			&n.Number,
+			&n.InvoiceID,
+			&n.InvoiceNumber,
+			&n.BookingID,
+			&n.TransactionID,
+			&n.Reason,
+			&n.Amount,
+			&n.Net,
+			&n.Tax,
+			&n.IssuedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan credit note: %w", err)
+		}
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
+
+// invoiceLinesPerPage keeps the lines clear of the totals and footer on each page.
+const invoiceLinesPerPage = 30
+
+// RenderInvoicePDF lays an invoice out as a PDF: header, billing details, one row per line and
+// the totals on the last page.
+func RenderInvoicePDF(inv *types.Invoice) []byte {
+	const (
+		left   = 56.0
+		right  = utils.PageWidth - 56
+		amount = 10.0
+	)
+	pdf := utils.NewPDF()
+	header := func(page, pages int) float64 {
+		pdf.Text(left, 72, utils.FontBold, 20, "Handworks Cleaning Services")
+		pdf.Text(left, 96, utils.FontBold, 14, "INVOICE "+inv.Number)
+		if inv.Status != types.InvoiceIssued {
+			pdf.Text(right-120, 96, utils.FontBold, 14, string(inv.Status))
+		}
+		pdf.Text(left, 124, utils.FontRegular, amount, "Billed to: "+inv.CustomerName)
+		pdf.Text(left, 138, utils.FontRegular, amount, inv.BillingAddress)
+		pdf.Text(left, 152, utils.FontRegular, amount, "Booking: "+inv.BookingID)
+		pdf.Text(right-170, 124, utils.FontRegular, amount, "Issued: "+inv.IssuedAt.In(PricingZone).Format("Jan 2, 2006"))
+		pdf.Text(right-170, 138, utils.FontRegular, amount, "Due: "+inv.DueAt.In(PricingZone).Format("Jan 2, 2006"))
+		pdf.Text(right-170, 152, utils.FontRegular, amount, fmt.Sprintf("Page %d of %d", page, pages))
+
+		pdf.Text(left, 184, utils.FontBold, amount, "Description")
+		pdf.Text(right-60, 184, utils.FontBold, amount, "Amount")
+		pdf.Line(left, 190, right, 190)
+		return 206
+	}
+
+	pages := max(1, (len(inv.Lines)+invoiceLinesPerPage-1)/invoiceLinesPerPage)
+	y := header(1, pages)
+	for i, l := range inv.Lines {
+		if i > 0 && i%invoiceLinesPerPage == 0 {
+			pdf.AddPage()
+			y = header(i/invoiceLinesPerPage+1, pages)
+		}
+		pdf.Text(left, y, utils.FontRegular, amount, l.Description)
+		pdf.TextRight(right, y, amount, l.Amount.String())
+		y += 16
+	}
+
+	pdf.Line(left, y-6, right, y-6)
+	y += 8
+	totals := [][2]string{{"Subtotal", inv.Subtotal.String()}}
+	if inv.Tax.Status == types.TaxVatable {
+		totals = append(totals,
+			[2]string{"VATable sales", inv.Tax.Net.String()},
+			[2]string{fmt.Sprintf("VAT %g%%", float64(inv.Tax.RateBasisPoints)/100), inv.Tax.Tax.String()})
+	} else {
+		totals = append(totals, [2]string{strings.ReplaceAll(string(inv.Tax.Status), "_", "-") + " sales", inv.Tax.Net.String()})
+	}
+	totals = append(totals, [2]string{"Total due (" + cmp.Or(inv.Tax.Gross.Currency, types.DefaultCurrency) + ")", inv.Tax.Gross.String()})
+	for i, row := range totals {
+		font := utils.FontRegular
+		if i == len(totals)-1 {
+			font = utils.FontBold
+		}
+		pdf.Text(right-220, y, font, amount, row[0])
+		pdf.TextRight(right, y, amount, row[1])
+		y += 16
+	}
+	for _, n := range inv.CreditNotes {
+		pdf.Text(right-220, y, utils.FontRegular, amount, "Credit note "+n.Number)
+		pdf.TextRight(right, y, amount, n.Amount.Mul(-1).String())
+		y += 16
+	}
+	if inv.Status == types.InvoiceVoid && inv.VoidReason != "" {
+		pdf.Text(left, y+16, utils.FontRegular, amount, "Voided: "+inv.VoidReason)
+	}
+	return pdf.Bytes()
+}
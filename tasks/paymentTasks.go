@@ -3,112 +3,113 @@ package tasks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"handworks-api/types"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type PaymentTasks struct {}
 
 
-func CalculateGeneralCleaning(details *types.GeneralCleaningDetails) float32 {
+func CalculateGeneralCleaning(details *types.GeneralCleaningDetails, rates *types.PriceRates) types.Money {
 	if details == nil {
-		return 0.0
+		return types.Money{}
 	}
 	sqm := details.SQM
 	homeType := details.HomeType
 
-	switch {
-	case homeType == "CONDO_ROOM" || (sqm > 0 && sqm <= 30):
-		return 2000.00
-	case homeType == "HOUSE" || (sqm > 30 && sqm <= 50):
-		return 2500.00
-	case sqm > 50 && sqm <= 100:
-		return 5000.00
-	default:
-		return float32(sqm * 50)
+	for _, tier := range rates.GeneralTiers {
+		if (tier.HomeType != "" && homeType == tier.HomeType) || (sqm > tier.MinSQM && sqm <= tier.MaxSQM) {
+			return tier.Price
+		}
 	}
+	return rates.GeneralPerSQM.Mul(int64(sqm))
 }
 
-func CalculateCarCleaning(details *types.CarCleaningDetails) float32 {
+func CalculateCarCleaning(details *types.CarCleaningDetails, rates *types.PriceRates) types.Money {
 	if details == nil {
-		return 0.0
+		return types.Money{}
 	}
 
-	var total float32
+	var total types.Money
 	for _, spec := range details.CleaningSpecs {
-		price := types.CarPrices[spec.CarType]
-		total += price * float32(spec.Quantity)
+		price := rates.Car[spec.CarType]
+		total = total.Add(price.Mul(int64(spec.Quantity)))
 	}
 
 	if details.ChildSeats > 0 {
-		total += float32(details.ChildSeats) * 250.00
+		total = total.Add(rates.ChildSeat.Mul(int64(details.ChildSeats)))
 	}
 
 	return total
 }
 
-func CalculateCouchCleaning(details *types.CouchCleaningDetails) float32 {
+func CalculateCouchCleaning(details *types.CouchCleaningDetails, rates *types.PriceRates) types.Money {
 	if details == nil {
-		return 0.0
+		return types.Money{}
 	}
 
-	var total float32
+	var total types.Money
 	for _, spec := range details.CleaningSpecs {
-		price := types.CouchPrices[spec.CouchType]
-		total += price * float32(spec.Quantity)
+		price := rates.Couch[spec.CouchType]
+		total = total.Add(price.Mul(int64(spec.Quantity)))
 	}
 
 	if details.BedPillows > 0 {
-		total += float32(details.BedPillows) * 100.00
+		total = total.Add(rates.BedPillow.Mul(int64(details.BedPillows)))
 	}
 
 	return total
 }
 
-func CalculateMattressCleaning(details *types.MattressCleaningDetails) float32 {
+func CalculateMattressCleaning(details *types.MattressCleaningDetails, rates *types.PriceRates) types.Money {
 	if details == nil {
-		return 0.0
+		return types.Money{}
 	}
 
-	var total float32
+	var total types.Money
 	for _, spec := range details.CleaningSpecs {
-		price := types.MattressPrices[spec.BedType]
-		total += price * float32(spec.Quantity)
+		price := rates.Mattress[spec.BedType]
+		total = total.Add(price.Mul(int64(spec.Quantity)))
 	}
 	return total
 }
-func CalculatePostConstructionCleaning(details *types.PostConstructionDetails) float32 {
+func CalculatePostConstructionCleaning(details *types.PostConstructionDetails, rates *types.PriceRates) types.Money {
 	if details == nil {
-		return 0.0
+		return types.Money{}
 	}
-	return float32(details.SQM * 50.00)
+	return rates.PostConstructionPerSQM.Mul(int64(details.SQM))
 }
 
-func (t *PaymentTasks) CalculatePriceByServiceType(service *types.ServicesRequest) float32 {
+func (t *PaymentTasks) CalculatePriceByServiceType(service *types.ServicesRequest, rates *types.PriceRates) types.Money {
 	if service == nil {
-		return 0
+		return types.Money{}
 	}
 
-	var calculatedPrice float32 = 0.00
+	var calculatedPrice types.Money
 
 	switch service.ServiceType {
 	case types.GeneralCleaning:
-		calculatedPrice = CalculateGeneralCleaning(service.Details.General)
+		calculatedPrice = CalculateGeneralCleaning(service.Details.General, rates)
 
 	case types.CouchCleaning:
-		calculatedPrice = CalculateCouchCleaning(service.Details.Couch)
+		calculatedPrice = CalculateCouchCleaning(service.Details.Couch, rates)
 
 	case types.MattressCleaning:
-		calculatedPrice = CalculateMattressCleaning(service.Details.Mattress)
+		calculatedPrice = CalculateMattressCleaning(service.Details.Mattress, rates)
 
 	case types.CarCleaning:
-		calculatedPrice = CalculateCarCleaning(service.Details.Car)
+		calculatedPrice = CalculateCarCleaning(service.Details.Car, rates)
 
 	case types.PostCleaning:
-		calculatedPrice = CalculatePostConstructionCleaning(service.Details.Post)
+		calculatedPrice = CalculatePostConstructionCleaning(service.Details.Post, rates)
 
 	default:
 		// no default action
@@ -117,7 +118,7 @@ func (t *PaymentTasks) CalculatePriceByServiceType(service *types.ServicesReques
 	return calculatedPrice
 }
 
-func (t *PaymentTasks) CalculateQuotePreview(c context.Context, in *types.QuoteRequest) (*types.Quote, error) {
+func (t *PaymentTasks) CalculateQuotePreview(c context.Context, tx pgx.Tx, in *types.QuoteRequest, catalog *types.PriceCatalog, tax types.TaxPolicy) (*types.Quote, error) {
 	var dbQuote types.Quote
 	var dbAddons []*types.QuoteAddon
 
@@ -126,22 +127,22 @@ func (t *PaymentTasks) CalculateQuotePreview(c context.Context, in *types.QuoteR
 		Details:     in.Service.Details,
 	}
 
-	subtotal := t.CalculatePriceByServiceType(mainService)
-	var addonTotal float32 = 0
+	subtotal := t.CalculatePriceByServiceType(mainService, &catalog.Rates)
+	var addonTotal types.Money
 
 	for _, addon := range in.Addons {
 		addonService := &types.ServicesRequest{
 			ServiceType: addon.ServiceDetail.ServiceType,
 			Details:     addon.ServiceDetail.Details,
 		}
-		addonPrice := t.CalculatePriceByServiceType(addonService)
+		addonPrice := t.CalculatePriceByServiceType(addonService, &catalog.Rates)
 
 		serviceDetail, err := json.Marshal(addon.ServiceDetail)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal addon service: %v", err)
 		}
 
-		addonTotal += addonPrice
+		addonTotal = addonTotal.Add(addonPrice)
 		dbAddon := &types.QuoteAddon{
 			ServiceType:   string(addon.ServiceDetail.ServiceType),
 			ServiceDetail: serviceDetail,
@@ -152,32 +153,336 @@ func (t *PaymentTasks) CalculateQuotePreview(c context.Context, in *types.QuoteR
 	}
 
 	dbQuote = types.Quote{
-		ID: 		"",	
-		CustomerID:  in.CustomerID, // will be empty
-		MainService: string(in.Service.ServiceType),
-		Subtotal:    subtotal,
-		AddonTotal:  addonTotal,
-		TotalPrice:  subtotal + addonTotal,
-		IsValid:     false, // marked as preview only so di siya valid
-		CreatedAt:   time.Now(),
-		Addons:      dbAddons,
+		ID:             "",
+		CustomerID:     in.CustomerID, // will be empty
+		MainService:    string(in.Service.ServiceType),
+		Subtotal:       subtotal,
+		AddonTotal:     addonTotal,
+		IsValid:        false, // marked as preview only so di siya valid
+		CatalogID:      catalog.ID,
+		CatalogVersion: catalog.Version,
+		CreatedAt:      time.Now(),
+		Addons:         dbAddons,
+	}
+	if err := t.priceAdjustments(c, tx, in, &dbQuote, &catalog.Rates, dbQuote.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := t.applyTax(c, tx, tax, &dbQuote); err != nil {
+		return nil, err
 	}
 
 	return &dbQuote, nil
 }
+
+// priceAdjustments adds the quote's lines on top of its services, surcharges for the job's condition,
+// time window surcharges or discounts for the desired slot, the travel fee and then the promo discount, and totals the quote. Subtotal, AddonTotal and Addons must already be priced.
+func (t *PaymentTasks) priceAdjustments(ctx context.Context, tx pgx.Tx, in *types.QuoteRequest, quote *types.Quote, rates *types.PriceRates, at time.Time) error {
+	if in.DirtyScale < 0 {
+		return fmt.Errorf("%w: dirtyScale cannot be negative", types.ErrInvalidQuoteRequest)
+	}
+	quote.DirtyScale = in.DirtyScale
+	quote.Modifiers = NormalizeModifiers(in.Modifiers)
+
+	surcharges, err := PriceSurcharges(rates, quote.Subtotal.Add(quote.AddonTotal), quote.DirtyScale, quote.Modifiers)
+	if err != nil {
+		return err
+	}
+	quote.Adjustments = surcharges
+
+	if in.StartSched != nil {
+		if in.StartSched.Before(at) {
+			return fmt.Errorf("%w: startSched is in the past", types.ErrInvalidQuoteRequest)
+		}
+		quote.StartSched = in.StartSched
+		windows, err := t.priceTimeWindows(ctx, tx, rates, quote.Subtotal.Add(quote.AddonTotal), *in.StartSched)
+		if err != nil {
+			return err
+		}
+		quote.Adjustments = append(quote.Adjustments, windows...)
+	}
+
+	if err := t.priceTravel(ctx, tx, in.Address, quote); err != nil {
+		return err
+	}
+
+	if code := normalizePromoCode(in.PromoCode); code != "" {
+		promo, discount, err := t.PricePromotion(ctx, tx, code, quote, at)
+		if err != nil {
+			return err
+		}
+		quote.PromotionID = &promo.ID
+		quote.Adjustments = append(quote.Adjustments, *discount)
+	}
+
+	var adjustmentTotal types.Money
+	for _, a := range quote.Adjustments {
+		adjustmentTotal = adjustmentTotal.Add(a.Amount)
+	}
+	quote.AdjustmentTotal = adjustmentTotal
+	quote.TotalPrice = quote.Subtotal.Add(quote.AddonTotal).Add(adjustmentTotal)
+	return nil
+}
+
+// priceTravel finds the depot serving the quote's address and adds its travel fee line. Without any
+// active depot there is no service area and travel is free.
+func (t *PaymentTasks) priceTravel(ctx context.Context, tx pgx.Tx, address *types.Address, quote *types.Quote) error {
+	depots, err := t.FetchDepots(ctx, tx, true)
+	if err != nil {
+		return err
+	}
+	if len(depots) == 0 {
+		return nil
+	}
+	if address == nil || (address.AddressLat == 0 && address.AddressLng == 0) {
+		return fmt.Errorf("%w: address coordinates are required", types.ErrInvalidQuoteRequest)
+	}
+	quote.Address = address
+
+	travel, ok := NearestDepot(depots, address.AddressLat, address.AddressLng)
+	if !ok {
+		return fmt.Errorf("%w: %s", types.ErrOutsideServiceArea, address.AddressHuman)
+	}
+	quote.Travel = travel
+	if !travel.Fee.IsZero() {
+		quote.Adjustments = append(quote.Adjustments, types.QuoteAdjustment{
+			Kind:   types.AdjustmentTravelFee,
+			Code:   "TRAVEL",
+			Label:  fmt.Sprintf("Travel fee (%.1f km from %s)", float64(travel.DistanceMeters)/1000, travel.DepotName),
+			Amount: travel.Fee,
+		})
+	}
+	return nil
+}
+
+const earthRadiusKm = 6371.0088
+
+// HaversineKm returns the great-circle distance between two coordinates in km.
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLng := rad(lat2-lat1), rad(lng2-lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// NearestDepot returns the travel fee from the nearest depot whose bands cover the coordinates,
+// or false if none does.
+func NearestDepot(depots []types.Depot, lat, lng float64) (*types.TravelQuote, bool) {
+	var (
+		best     *types.TravelQuote
+		bestDist float64
+	)
+	for _, d := range depots {
+		km := HaversineKm(d.Lat, d.Lng, lat, lng)
+		fee, ok := travelFee(d.Bands, km)
+		if !ok || (best != nil && km >= bestDist) {
+			continue
+		}
+		best, bestDist = &types.TravelQuote{
+			DepotID:        d.ID,
+			DepotName:      d.Name,
+			DistanceMeters: int32(math.Round(km * 1000)),
+			Fee:            fee,
+		}, km
+	}
+	return best, best != nil
+}
+
+// travelFee prices a distance with the first band reaching it, or reports it is out of range.
+func travelFee(bands []types.TravelBand, km float64) (types.Money, bool) {
+	var from float64
+	for _, b := range bands {
+		if km <= b.MaxKm {
+			startedKm := int64(math.Ceil(km - from))
+			return b.Fee.Add(b.PerKm.Mul(max(startedKm, 0))), true
+		}
+		from = b.MaxKm
+	}
+	return types.Money{}, false
+}
+
+// NormalizeModifiers upper-cases modifier codes and drops blanks and duplicates, keeping their order.
+func NormalizeModifiers(modifiers []string) []string {
+	normalized := []string{}
+	for _, m := range modifiers {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if m != "" && !slices.Contains(normalized, m) {
+			normalized = append(normalized, m)
+		}
+	}
+	return normalized
+}
+
+// PriceSurcharges returns a line for the dirty scale tier the job falls in, if any, and one per
+// modifier, each priced on the services total. Modifiers the catalog does not know are rejected.
+func PriceSurcharges(rates *types.PriceRates, services types.Money, dirtyScale int32, modifiers []string) ([]types.QuoteAdjustment, error) {
+	lines := []types.QuoteAdjustment{}
+
+	var tier *types.DirtyScaleSurcharge
+	for i, s := range rates.DirtyScaleSurcharges {
+		if dirtyScale >= s.MinScale && (tier == nil || s.MinScale > tier.MinScale) {
+			tier = &rates.DirtyScaleSurcharges[i]
+		}
+	}
+	if tier != nil {
+		if amount := services.Percent(int64(tier.Percent)).Add(tier.Flat); !amount.IsZero() {
+			lines = append(lines, types.QuoteAdjustment{
+				Kind:   types.AdjustmentSurcharge,
+				Code:   fmt.Sprintf("DIRTY_SCALE_%d", tier.MinScale),
+				Label:  fmt.Sprintf("Dirty scale %d and above", tier.MinScale),
+				Amount: amount,
+			})
+		}
+	}
+
+	for _, code := range modifiers {
+		m, ok := rates.Modifiers[code]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown modifier %s", types.ErrInvalidQuoteRequest, code)
+		}
+		lines = append(lines, types.QuoteAdjustment{
+			Kind:   types.AdjustmentSurcharge,
+			Code:   code,
+			Label:  m.Label,
+			Amount: services.Percent(int64(m.Percent)).Add(m.Flat),
+		})
+	}
+	return lines, nil
+}
+
+// PricingZone is the local time that time rules and the holiday calendar are written in.
+var PricingZone = time.FixedZone("PHT", 8*60*60)
+
+// PriceTimeRules returns a line for every rule matching a job starting at start, each priced on the
+// services total. On a holiday, rules see the day as HOLIDAY rather than its weekday.
+func PriceTimeRules(rules []types.TimePricingRule, services types.Money, start time.Time, holiday bool) []types.QuoteAdjustment {
+	local := start.In(PricingZone)
+	day := strings.ToUpper(local.Weekday().String())
+	if holiday {
+		day = holidayDay
+	}
+	lines := []types.QuoteAdjustment{}
+	for _, r := range rules {
+		if len(r.Days) > 0 && !slices.Contains(r.Days, day) {
+			continue
+		}
+		if !inHourWindow(int32(local.Hour()), r.StartHour, r.EndHour) {
+			continue
+		}
+		lines = append(lines, types.QuoteAdjustment{
+			Kind:   types.AdjustmentTimeWindow,
+			Code:   r.Code,
+			Label:  r.Label,
+			Amount: services.Percent(int64(r.Percent)).Add(r.Flat),
+		})
+	}
+	return lines
+}
+
+const holidayDay = "HOLIDAY"
+
+// inHourWindow reports whether hour falls in [start, end), wrapping past midnight when start > end.
+// A window with start equal to end covers the whole day.
+func inHourWindow(hour, start, end int32) bool {
+	switch {
+	case start == end:
+		return true
+	case start < end:
+		return hour >= start && hour < end
+	default:
+		return hour >= start || hour < end
+	}
+}
+
+// priceTimeWindows prices the catalog's time rules for a job starting at start.
+func (t *PaymentTasks) priceTimeWindows(ctx context.Context, tx pgx.Tx, rates *types.PriceRates, services types.Money, start time.Time) ([]types.QuoteAdjustment, error) {
+	if len(rates.TimeRules) == 0 {
+		return []types.QuoteAdjustment{}, nil
+	}
+	holiday, err := t.isHoliday(ctx, tx, start)
+	if err != nil {
+		return nil, err
+	}
+	return PriceTimeRules(rates.TimeRules, services, start, holiday), nil
+}
+
+func (t *PaymentTasks) isHoliday(ctx context.Context, tx pgx.Tx, at time.Time) (bool, error) {
+	var holiday bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM payment.holidays WHERE date = $1::date)
+	`, at.In(PricingZone).Format(time.DateOnly)).Scan(&holiday); err != nil {
+		return false, fmt.Errorf("check holiday: %w", err)
+	}
+	return holiday, nil
+}
+
+// applyTax splits the quote total into net, tax and gross for its customer and makes the gross the total.
+func (t *PaymentTasks) applyTax(ctx context.Context, tx pgx.Tx, policy types.TaxPolicy, quote *types.Quote) error {
+	status, err := t.fetchCustomerTaxStatus(ctx, tx, quote.CustomerID)
+	if err != nil {
+		return err
+	}
+	quote.Tax = ComputeTax(policy, status, quote.TotalPrice)
+	quote.TotalPrice = quote.Tax.Gross
+	return nil
+}
+
+// fetchCustomerTaxStatus returns a customer's tax status. Unknown customers, such as those asking
+// for a preview, are VATABLE.
+func (t *PaymentTasks) fetchCustomerTaxStatus(ctx context.Context, tx pgx.Tx, customerID string) (types.TaxStatus, error) {
+	if err := new(pgtype.UUID).Scan(customerID); err != nil {
+		return types.TaxVatable, nil
+	}
+	var status types.TaxStatus
+	err := tx.QueryRow(ctx, `
+		SELECT tax_status
+		FROM account.customers
+		WHERE id = $1`, customerID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return types.TaxVatable, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("fetch customer tax status: %w", err)
+	}
+	return status, nil
+}
+
+// ComputeTax splits an amount priced under policy into net, tax and gross. Exempt and zero-rated
+// customers pay the net amount, so with tax-inclusive prices the VAT is taken out of the price.
+func ComputeTax(policy types.TaxPolicy, status types.TaxStatus, amount types.Money) types.TaxBreakdown {
+	b := types.TaxBreakdown{
+		Status:           status,
+		PricesIncludeTax: policy.PricesIncludeTax,
+		Net:              amount,
+		Tax:              types.Money{Currency: amount.Currency},
+	}
+	if policy.PricesIncludeTax {
+		b.Net = amount.Ratio(10000, 10000+policy.RateBasisPoints)
+	}
+	if status == types.TaxVatable {
+		b.RateBasisPoints = int32(policy.RateBasisPoints)
+		if policy.PricesIncludeTax {
+			// the remainder, so net + tax is exactly the catalog price
+			b.Tax = amount.Sub(b.Net)
+		} else {
+			b.Tax = amount.Ratio(policy.RateBasisPoints, 10000)
+		}
+	}
+	b.Gross = b.Net.Add(b.Tax)
+	return b
+}
 func (t* PaymentTasks) MapAddonstoAddonBreakdown(addons* []*types.QuoteAddon) []types.AddOnBreakdown {
 	var breakdowns []types.AddOnBreakdown
 	for _, addon := range *addons {
 		breakdown := types.AddOnBreakdown{
 			AddonID:   addon.ID,
 			AddonName: addon.ServiceType,
-			Price:     float64(addon.AddonPrice),
+			Price:     addon.AddonPrice,
 		}
 		breakdowns = append(breakdowns, breakdown)
 	}
 	return breakdowns
 }
-func (p *PaymentTasks) CreateQuote(c context.Context, tx pgx.Tx, in *types.QuoteRequest) (*types.Quote, error) {
+func (p *PaymentTasks) CreateQuote(c context.Context, tx pgx.Tx, in *types.QuoteRequest, ttl time.Duration, catalog *types.PriceCatalog, tax types.TaxPolicy) (*types.Quote, error) {
 	var dbQuote types.Quote
 	var dbAddons []*types.QuoteAddon
 
@@ -187,8 +492,8 @@ func (p *PaymentTasks) CreateQuote(c context.Context, tx pgx.Tx, in *types.Quote
 	}
 
 	// Calc subtotal for main service
-	subtotal := p.CalculatePriceByServiceType(mainService)
-	var addonTotal float32 = 0
+	subtotal := p.CalculatePriceByServiceType(mainService, &catalog.Rates)
+	var addonTotal types.Money
 
 	// Calculate each addon price
 	for _, addon := range in.Addons {
@@ -197,7 +502,7 @@ func (p *PaymentTasks) CreateQuote(c context.Context, tx pgx.Tx, in *types.Quote
 			ServiceType: addon.ServiceDetail.ServiceType,
 			Details:     addon.ServiceDetail.Details,
 		}
-		addonPrice := p.CalculatePriceByServiceType(addonService)
+		addonPrice := p.CalculatePriceByServiceType(addonService, &catalog.Rates)
 
 		// serialize the full addon service detail
 		serviceDetail, err := json.Marshal(addon.ServiceDetail)
@@ -205,7 +510,7 @@ func (p *PaymentTasks) CreateQuote(c context.Context, tx pgx.Tx, in *types.Quote
 			return nil, fmt.Errorf("failed to marshal addon service: %v", err)
 		}
 
-		addonTotal += addonPrice
+		addonTotal = addonTotal.Add(addonPrice)
 
 		dbAddon := &types.QuoteAddon{
 			ServiceType:   string(addon.ServiceDetail.ServiceType),
@@ -216,33 +521,100 @@ func (p *PaymentTasks) CreateQuote(c context.Context, tx pgx.Tx, in *types.Quote
 		dbAddons = append(dbAddons, dbAddon)
 	}
 
-	totalPrice := subtotal + addonTotal
+	dbQuote.CustomerID = in.CustomerID
+	dbQuote.MainService = string(in.Service.ServiceType)
+	dbQuote.Subtotal = subtotal
+	dbQuote.AddonTotal = addonTotal
+	dbQuote.Addons = dbAddons
+	if err := p.priceAdjustments(c, tx, in, &dbQuote, &catalog.Rates, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := p.applyTax(c, tx, tax, &dbQuote); err != nil {
+		return nil, err
+	}
+	adjustments := dbQuote.Adjustments
+	var (
+		depotID        *string
+		distanceMeters *int32
+	)
+	if dbQuote.Travel != nil {
+		depotID, distanceMeters = &dbQuote.Travel.DepotID, &dbQuote.Travel.DistanceMeters
+	}
+
+	mainServiceDetail, err := json.Marshal(in.Service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal main service: %v", err)
+	}
 
 	// Insert into quote table
-	err := tx.QueryRow(c, `
-		INSERT INTO payment.quotes (customer_id, main_service_type, subtotal, addon_total, total_price, is_valid)
-		VALUES ($1, $2, $3, $4, $5, TRUE)
-		RETURNING id, customer_id, main_service_type, subtotal, addon_total, total_price, is_valid, created_at, updated_at
+	err = tx.QueryRow(c, `
+		INSERT INTO payment.quotes (customer_id, main_service_type, main_service_detail, subtotal, addon_total, adjustment_total, total_price,
+			net_amount, tax_amount, tax_rate_bps, tax_status, prices_include_tax, is_valid, expires_at, catalog_id, catalog_version, promotion_id,
+			dirty_scale, modifiers, address, depot_id, travel_distance_m, start_sched)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, TRUE, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+		RETURNING id, customer_id, main_service_type, subtotal, addon_total, adjustment_total, total_price,
+			net_amount, tax_amount, tax_rate_bps, tax_status, prices_include_tax, is_valid, expires_at, catalog_id, catalog_version, promotion_id::text,
+			dirty_scale, modifiers, start_sched, created_at, updated_at
 	`,
 		in.CustomerID,
 		in.Service.ServiceType,
+		mainServiceDetail,
 		subtotal,
 		addonTotal,
-		totalPrice,
+		dbQuote.AdjustmentTotal,
+		dbQuote.TotalPrice,
+		dbQuote.Tax.Net,
+		dbQuote.Tax.Tax,
+		dbQuote.Tax.RateBasisPoints,
+		dbQuote.Tax.Status,
+		dbQuote.Tax.PricesIncludeTax,
+		time.Now().Add(ttl),
+		catalog.ID,
+		catalog.Version,
+		dbQuote.PromotionID,
+		dbQuote.DirtyScale,
+		dbQuote.Modifiers,
+		dbQuote.Address,
+		depotID,
+		distanceMeters,
+		dbQuote.StartSched,
 	).Scan(
 		&dbQuote.ID,
 		&dbQuote.CustomerID,
 		&dbQuote.MainService,
 		&dbQuote.Subtotal,
 		&dbQuote.AddonTotal,
+		&dbQuote.AdjustmentTotal,
 		&dbQuote.TotalPrice,
+		&dbQuote.Tax.Net,
+		&dbQuote.Tax.Tax,
+		&dbQuote.Tax.RateBasisPoints,
+		&dbQuote.Tax.Status,
+		&dbQuote.Tax.PricesIncludeTax,
 		&dbQuote.IsValid,
+		&dbQuote.ExpiresAt,
+		&dbQuote.CatalogID,
+		&dbQuote.CatalogVersion,
+		&dbQuote.PromotionID,
+		&dbQuote.DirtyScale,
+		&dbQuote.Modifiers,
+		&dbQuote.StartSched,
 		&dbQuote.CreatedAt,
 		&dbQuote.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert quote: %v", err)
 	}
+	dbQuote.Tax.Gross = dbQuote.TotalPrice
+
+	for _, a := range adjustments {
+		if _, err := tx.Exec(c, `
+			INSERT INTO payment.quote_adjustments (quote_id, kind, code, label, amount)
+			VALUES ($1, $2, $3, $4, $5)
+		`, dbQuote.ID, a.Kind, a.Code, a.Label, a.Amount); err != nil {
+			return nil, fmt.Errorf("failed to insert adjustment: %v", err)
+		}
+	}
 
 	// insert addons
 	for _, addon := range dbAddons {
@@ -264,53 +636,1422 @@ func (p *PaymentTasks) CreateQuote(c context.Context, tx pgx.Tx, in *types.Quote
 	}
 
 	dbQuote.Addons = dbAddons
+	dbQuote.Adjustments = adjustments
 	return &dbQuote, nil
 }
-func(t* PaymentTasks) VerifyQuoteAndFetchPrices(ctx context.Context, tx pgx.Tx, quoteId string) (*types.CleaningPrices, error) {
-	var prices types.CleaningPrices
-
-	var dbQuote types.Quote
-	err := tx.QueryRow(ctx, `
-		SELECT total_price, is_valid
+// VerifyQuoteAndFetchPrices returns the prices of the quote a booking request uses, after checking
+// that the quote can still be booked, belongs to the booking's customer and quoted the job the
+// booking asks for. The submitted services are re-priced with the quote's catalog and compared line
+// by line, and the dirty scale, modifiers and address must be those the quote was priced with. A
+// quote not yet consumed also has its travel fee recomputed from the active depots, so an address
+// no longer covered, or served by another depot, is caught before the quote is spent. Any
+// difference fails with a *types.QuoteMismatchError listing them. A quote already consumed by the
+// booking stays usable for it, so rescheduling can re-read its prices.
+func (t *PaymentTasks) VerifyQuoteAndFetchPrices(ctx context.Context, tx pgx.Tx, req *types.CreateBookingRequest) (*types.CleaningPrices, error) {
+	quoteId := req.Base.QuoteId
+	if err := t.checkQuoteUsable(ctx, tx, quoteId, req.Base.ID, false); err != nil {
+		return nil, err
+	}
+	var (
+		prices          types.CleaningPrices
+		customerID      string
+		catalogID       *string
+		mainService     string
+		addonTotal      types.Money
+		adjustmentTotal types.Money
+		quoted          quotedConditions
+	)
+	if err := tx.QueryRow(ctx, `
+		SELECT customer_id, catalog_id::text, main_service_type, subtotal, addon_total, adjustment_total,
+			total_price, net_amount, tax_amount, tax_rate_bps, tax_status, prices_include_tax,
+			dirty_scale, modifiers, address, depot_id::text, consumed_at IS NULL
 		FROM payment.quotes
 		WHERE id = $1
 	`, quoteId).Scan(
-		&dbQuote.TotalPrice,
-		&dbQuote.IsValid,
+		&customerID,
+		&catalogID,
+		&mainService,
+		&prices.MainServicePrice,
+		&addonTotal,
+		&adjustmentTotal,
+		&prices.Tax.Gross,
+		&prices.Tax.Net,
+		&prices.Tax.Tax,
+		&prices.Tax.RateBasisPoints,
+		&prices.Tax.Status,
+		&prices.Tax.PricesIncludeTax,
+		&quoted.DirtyScale,
+		&quoted.Modifiers,
+		&quoted.Address,
+		&quoted.DepotID,
+		&quoted.Unconsumed,
+	); err != nil {
+		return nil, fmt.Errorf("fetch main quote: %w", err)
+	}
+	if customerID != req.Base.CustID {
+		return nil, fmt.Errorf("%w: %s", types.ErrQuoteNotOwned, quoteId)
+	}
+
+	addons, err := t.fetchQuoteAddons(ctx, tx, []string{quoteId})
+	if err != nil {
+		return nil, err
+	}
+	quotedAddons := make([]types.AddonCleaningPrice, 0, len(addons[quoteId]))
+	for _, a := range addons[quoteId] {
+		quotedAddons = append(quotedAddons, types.AddonCleaningPrice{AddonName: a.ServiceType, AddonPrice: a.Price})
+	}
+	adjustments, err := t.fetchQuoteAdjustments(ctx, tx, []string{quoteId})
+	if err != nil {
+		return nil, err
+	}
+	prices.Adjustments = adjustments[quoteId]
+
+	// quotes priced before catalogs cannot be re-priced, so only their services are compared
+	var rates *types.PriceRates
+	if catalogID != nil {
+		catalog, err := t.FetchCatalog(ctx, tx, *catalogID)
+		if err != nil {
+			return nil, err
+		}
+		rates = &catalog.Rates
+	}
+	submittedMain := types.AddonCleaningPrice{AddonName: string(req.MainService.ServiceType)}
+	submittedAddons := make([]types.AddonCleaningPrice, 0, len(req.Addons))
+	if rates != nil {
+		submittedMain.AddonPrice = t.CalculatePriceByServiceType(&req.MainService, rates)
+	}
+	for _, a := range req.Addons {
+		submitted := types.AddonCleaningPrice{AddonName: string(a.ServiceDetail.ServiceType)}
+		if rates != nil {
+			submitted.AddonPrice = t.CalculatePriceByServiceType(&a.ServiceDetail, rates)
+		}
+		submittedAddons = append(submittedAddons, submitted)
+	}
+
+	diff, matched := DiffQuote(
+		types.AddonCleaningPrice{AddonName: mainService, AddonPrice: prices.MainServicePrice},
+		submittedMain,
+		quotedAddons,
+		submittedAddons,
+		rates != nil,
+	)
+	prices.AddonPrices = matched
+	diff = append(diff, diffQuoteTotal(&prices, prices.MainServicePrice.Add(addonTotal).Add(adjustmentTotal))...)
+	diff = append(diff, diffQuoteConditions(&quoted, &req.Base)...)
+	if quoted.Unconsumed {
+		depotDiff, err := t.diffQuoteTravel(ctx, tx, &quoted, prices.Adjustments, &req.Base.Address)
+		if err != nil {
+			return nil, err
+		}
+		diff = append(diff, depotDiff...)
+	}
+	if len(diff) > 0 {
+		return nil, &types.QuoteMismatchError{QuoteID: quoteId, Diff: diff}
+	}
+	return &prices, nil
+}
+
+// DiffQuote compares the main service and addons submitted for a booking, re-priced, with those of
+// its quote. Addons are matched by service type and price in any order. Without comparePrices only
+// the service types are compared. It also returns the quoted price of each submitted addon, in
+// submission order; addons that were not quoted get a zero price.
+func DiffQuote(
+	quotedMain, submittedMain types.AddonCleaningPrice,
+	quotedAddons, submittedAddons []types.AddonCleaningPrice,
+	comparePrices bool,
+) ([]types.QuoteDiff, []types.AddonCleaningPrice) {
+	diff := []types.QuoteDiff{}
+	line := func(item, serviceType string, quoted, submitted *types.Money, reason string) {
+		diff = append(diff, types.QuoteDiff{Item: item, ServiceType: serviceType, Quoted: quoted, Submitted: submitted, Reason: reason})
+	}
+
+	switch {
+	case quotedMain.AddonName != submittedMain.AddonName:
+		line(types.QuoteDiffMainService, submittedMain.AddonName, nil, nil, "quoted for "+quotedMain.AddonName)
+	case comparePrices && !quotedMain.AddonPrice.Equal(submittedMain.AddonPrice):
+		line(types.QuoteDiffMainService, submittedMain.AddonName, &quotedMain.AddonPrice, &submittedMain.AddonPrice, "details priced differently than quoted")
+	}
+
+	used := make([]bool, len(quotedAddons))
+	find := func(a types.AddonCleaningPrice, samePrice bool) int {
+		for i, q := range quotedAddons {
+			if !used[i] && q.AddonName == a.AddonName && (!samePrice || q.AddonPrice.Equal(a.AddonPrice)) {
+				return i
+			}
+		}
+		return -1
+	}
+	matched := make([]types.AddonCleaningPrice, len(submittedAddons))
+	var repriced []int
+	for i, a := range submittedAddons {
+		matched[i] = types.AddonCleaningPrice{AddonName: a.AddonName}
+		if j := find(a, comparePrices); j >= 0 {
+			used[j] = true
+			matched[i].AddonPrice = quotedAddons[j].AddonPrice
+			continue
+		}
+		repriced = append(repriced, i)
+	}
+	// an addon of a quoted type but priced differently has different details
+	for _, i := range repriced {
+		a := submittedAddons[i]
+		if j := find(a, false); j >= 0 {
+			used[j] = true
+			matched[i].AddonPrice = quotedAddons[j].AddonPrice
+			line(types.QuoteDiffAddon, a.AddonName, &quotedAddons[j].AddonPrice, &submittedAddons[i].AddonPrice, "details priced differently than quoted")
+			continue
+		}
+		var submitted *types.Money
+		if comparePrices {
+			submitted = &submittedAddons[i].AddonPrice
+		}
+		line(types.QuoteDiffAddon, a.AddonName, nil, submitted, "not quoted")
+	}
+	for j, q := range quotedAddons {
+		if !used[j] {
+			line(types.QuoteDiffAddon, q.AddonName, &quotedAddons[j].AddonPrice, nil, "quoted but not submitted")
+		}
+	}
+	return diff, matched
+}
+
+// quotedConditions are the job conditions a quote was priced with.
+type quotedConditions struct {
+	DirtyScale int32
+	Modifiers  []string
+	Address    *types.Address // nil when the quote was priced without a service area
+	DepotID    *string
+	Unconsumed bool
+}
+
+// coordinateTolerance is how far apart, in degrees (about a metre), two coordinates may be and
+// still be the same address.
+const coordinateTolerance = 1e-5
+
+// SameLocation reports whether two addresses are at the same coordinates, whatever their text.
+func SameLocation(a, b types.Address) bool {
+	return math.Abs(a.AddressLat-b.AddressLat) <= coordinateTolerance &&
+		math.Abs(a.AddressLng-b.AddressLng) <= coordinateTolerance
+}
+
+// diffQuoteConditions compares the dirty scale, modifiers and address of a booking with those its
+// quote was priced with. Modifiers are compared as codes, in any order. Quotes priced without a
+// service area have no address to compare.
+func diffQuoteConditions(quoted *quotedConditions, submitted *types.BaseBookingDetailsRequest) []types.QuoteDiff {
+	diff := []types.QuoteDiff{}
+	if quoted.DirtyScale != submitted.DirtyScale {
+		diff = append(diff, types.QuoteDiff{
+			Item:           types.QuoteDiffDirtyScale,
+			QuotedValue:    quoted.DirtyScale,
+			SubmittedValue: submitted.DirtyScale,
+			Reason:         "priced for another dirty scale",
+		})
+	}
+	quotedModifiers, submittedModifiers := NormalizeModifiers(quoted.Modifiers), NormalizeModifiers(submitted.Modifiers)
+	slices.Sort(quotedModifiers)
+	slices.Sort(submittedModifiers)
+	if !slices.Equal(quotedModifiers, submittedModifiers) {
+		diff = append(diff, types.QuoteDiff{
+			Item:           types.QuoteDiffModifiers,
+			QuotedValue:    quotedModifiers,
+			SubmittedValue: submittedModifiers,
+			Reason:         "priced with other modifiers",
+		})
+	}
+	if a := quoted.Address; a != nil && !SameLocation(*a, submitted.Address) {
+		diff = append(diff, types.QuoteDiff{
+			Item:           types.QuoteDiffAddress,
+			QuotedValue:    *a,
+			SubmittedValue: submitted.Address,
+			Reason:         "priced for another address",
+		})
+	}
+	return diff
+}
+
+// diffQuoteTravel recomputes the travel fee of a booking's address from the active depots and
+// compares it with the quote's depot and travel fee line.
+func (t *PaymentTasks) diffQuoteTravel(
+	ctx context.Context,
+	tx pgx.Tx,
+	quoted *quotedConditions,
+	adjustments []types.QuoteAdjustment,
+	address *types.Address,
+) ([]types.QuoteDiff, error) {
+	depots, err := t.FetchDepots(ctx, tx, true)
+	if err != nil {
+		return nil, err
+	}
+	quotedFee := types.PHP(0)
+	for _, a := range adjustments {
+		if a.Kind == types.AdjustmentTravelFee {
+			quotedFee = quotedFee.Add(a.Amount)
+		}
+	}
+	quotedDepot := ""
+	if quoted.DepotID != nil {
+		quotedDepot = *quoted.DepotID
+	}
+	mismatch := func(submittedDepot string, submittedFee *types.Money, reason string) []types.QuoteDiff {
+		return []types.QuoteDiff{{
+			Item:           types.QuoteDiffDepot,
+			Quoted:         &quotedFee,
+			Submitted:      submittedFee,
+			QuotedValue:    quotedDepot,
+			SubmittedValue: submittedDepot,
+			Reason:         reason,
+		}}
+	}
+
+	if len(depots) == 0 {
+		if quotedDepot != "" {
+			return mismatch("", nil, "no depot serves the address anymore"), nil
+		}
+		return nil, nil
+	}
+	travel, ok := NearestDepot(depots, address.AddressLat, address.AddressLng)
+	switch {
+	case !ok:
+		return mismatch("", nil, "address is outside the service area"), nil
+	case travel.DepotID != quotedDepot:
+		return mismatch(travel.DepotID, &travel.Fee, "address is served by another depot"), nil
+	case !travel.Fee.Equal(quotedFee):
+		return mismatch(travel.DepotID, &travel.Fee, "travel fee differs from the quote"), nil
+	}
+	return nil, nil
+}
+
+// diffQuoteTotal checks that a quote's lines add up to its stored totals and to the amount its tax
+// was computed on, so a booking's total is always its main service, addons and adjustments.
+func diffQuoteTotal(prices *types.CleaningPrices, quoted types.Money) []types.QuoteDiff {
+	total := prices.Total()
+	mismatch := func(reason string) []types.QuoteDiff {
+		return []types.QuoteDiff{{Item: types.QuoteDiffTotal, Quoted: &quoted, Submitted: &total, Reason: reason}}
+	}
+	if !total.Equal(quoted) {
+		return mismatch("quote lines do not add up to its totals")
+	}
+	switch {
+	case !prices.Tax.PricesIncludeTax && !prices.Tax.Net.Equal(total):
+		return mismatch("tax was computed on " + prices.Tax.Net.String())
+	case prices.Tax.PricesIncludeTax && prices.Tax.Status == types.TaxVatable && !prices.Tax.Gross.Equal(total):
+		return mismatch("tax was computed on " + prices.Tax.Gross.String())
+	}
+	return nil
+}
+
+const quoteDetailColumns = `id, customer_id, main_service_type, main_service_detail, dirty_scale, modifiers, address,
+	depot_id::text, (SELECT name FROM payment.depots d WHERE d.id = depot_id), travel_distance_m, start_sched, subtotal, addon_total, adjustment_total, total_price, net_amount, tax_amount, tax_rate_bps, tax_status, prices_include_tax, is_valid, expires_at, consumed_at, consumed_by::text, catalog_version, created_at, updated_at`
+
+func scanQuoteDetail(row pgx.Row) (*types.QuoteDetail, error) {
+	var (
+		q              types.QuoteDetail
+		rawDetail      []byte
+		depotID        *string
+		depotName      *string
+		distanceMeters *int32
 	)
+	if err := row.Scan(
+		&q.ID,
+		&q.CustomerID,
+		&q.MainService,
+		&rawDetail,
+		&q.DirtyScale,
+		&q.Modifiers,
+		&q.Address,
+		&depotID,
+		&depotName,
+		&distanceMeters,
+		&q.StartSched,
+		&q.Subtotal,
+		&q.AddonTotal,
+		&q.AdjustmentTotal,
+		&q.TotalPrice,
+		&q.Tax.Net,
+		&q.Tax.Tax,
+		&q.Tax.RateBasisPoints,
+		&q.Tax.Status,
+		&q.Tax.PricesIncludeTax,
+		&q.IsValid,
+		&q.ExpiresAt,
+		&q.ConsumedAt,
+		&q.ConsumedBy,
+		&q.CatalogVersion,
+		&q.CreatedAt,
+		&q.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if rawDetail != nil {
+		var detail types.ServicesRequest
+		if err := json.Unmarshal(rawDetail, &detail); err != nil {
+			return nil, fmt.Errorf("decode main service of quote %s: %w", q.ID, err)
+		}
+		q.MainServiceDetail = &detail
+	}
+	q.Tax.Gross = q.TotalPrice
+	if depotID != nil {
+		q.Travel = &types.TravelQuote{DepotID: *depotID, DistanceMeters: *distanceMeters}
+		if depotName != nil {
+			q.Travel.DepotName = *depotName
+		}
+	}
+	q.Addons = []types.QuoteAddonDetail{}
+	q.Adjustments = []types.QuoteAdjustment{}
+	return &q, nil
+}
+
+// FetchQuotesByCustomer returns a page of a customer's quotes, newest first, with their addons, and the total count.
+func (t *PaymentTasks) FetchQuotesByCustomer(ctx context.Context, tx pgx.Tx, customerID string, limit, offset int32) ([]types.QuoteDetail, int32, error) {
+	var total int32
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM payment.quotes
+		WHERE customer_id = $1`, customerID,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count quotes: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT `+quoteDetailColumns+`
+		FROM payment.quotes
+		WHERE customer_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3`, customerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("fetch quotes: %w", err)
+	}
+	defer rows.Close()
+
+	quotes := []types.QuoteDetail{}
+	ids := []string{}
+	for rows.Next() {
+		q, err := scanQuoteDetail(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan quote: %w", err)
+		}
+		quotes = append(quotes, *q)
+		ids = append(ids, q.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("fetch quotes: %w", err)
+	}
+	rows.Close()
+
+	addons, err := t.fetchQuoteAddons(ctx, tx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	adjustments, err := t.fetchQuoteAdjustments(ctx, tx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range quotes {
+		if a, ok := addons[quotes[i].ID]; ok {
+			quotes[i].Addons = a
+		}
+		if a, ok := adjustments[quotes[i].ID]; ok {
+			quotes[i].Adjustments = a
+		}
+		setTravelFee(&quotes[i])
+	}
+	return quotes, total, nil
+}
+
+// FetchQuoteByID returns a single quote with its addons.
+func (t *PaymentTasks) FetchQuoteByID(ctx context.Context, tx pgx.Tx, id string) (*types.QuoteDetail, error) {
+	q, err := scanQuoteDetail(tx.QueryRow(ctx, `
+		SELECT `+quoteDetailColumns+`
+		FROM payment.quotes
+		WHERE id = $1`, id))
+	if err != nil {
+		return nil, fmt.Errorf("fetch quote %s: %w", id, err)
+	}
+
+	addons, err := t.fetchQuoteAddons(ctx, tx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if a, ok := addons[id]; ok {
+		q.Addons = a
+	}
+	adjustments, err := t.fetchQuoteAdjustments(ctx, tx, []string{id})
 	if err != nil {
-		return &prices, fmt.Errorf("fetch main quote: %w", err)
+		return nil, err
+	}
+	if a, ok := adjustments[id]; ok {
+		q.Adjustments = a
 	}
-	if !dbQuote.IsValid {
-		return &types.CleaningPrices{}, fmt.Errorf("quote is no longer valied")
+	setTravelFee(q)
+	return q, nil
+}
+
+// fetchQuoteAddons loads the addons of the given quotes, keyed by quote id, in the order they were added.
+func (t *PaymentTasks) fetchQuoteAddons(ctx context.Context, tx pgx.Tx, quoteIDs []string) (map[string][]types.QuoteAddonDetail, error) {
+	addons := map[string][]types.QuoteAddonDetail{}
+	if len(quoteIDs) == 0 {
+		return addons, nil
 	}
 	rows, err := tx.Query(ctx, `
-		SELECT service_type, addon_price
+		SELECT id, quote_id, service_type, service_detail, addon_price, created_at
 		FROM payment.quote_addons
-		WHERE quote_id = $1
-	`, quoteId)
+		WHERE quote_id = ANY($1::uuid[])
+		ORDER BY created_at, id`, quoteIDs)
 	if err != nil {
-		return &prices, fmt.Errorf("fetch addons: %w", err)
+		return nil, fmt.Errorf("fetch addons: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var addon types.QuoteAddon
-		if err := rows.Scan(
-			&addon.ServiceType,
-			&addon.AddonPrice,
-		); err != nil {
-			return &prices, fmt.Errorf("scan addon: %w", err)
+		var (
+			a       types.QuoteAddonDetail
+			quoteID string
+			raw     []byte
+		)
+		if err := rows.Scan(&a.ID, &quoteID, &a.ServiceType, &raw, &a.Price, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan addon: %w", err)
 		}
-		dbQuote.Addons = append(dbQuote.Addons, &addon)
+		if err := json.Unmarshal(raw, &a.ServiceDetail); err != nil {
+			return nil, fmt.Errorf("decode addon %s: %w", a.ID, err)
+		}
+		addons[quoteID] = append(addons[quoteID], a)
 	}
+	return addons, rows.Err()
+}
 
-	for _, a := range dbQuote.Addons {
-		prices.AddonPrices = append(prices.AddonPrices, types.AddonCleaningPrice{
-			AddonName:  a.ServiceType,
-			AddonPrice: a.AddonPrice,
-		})
+// setTravelFee fills in the travel fee of a stored quote from its adjustment lines.
+func setTravelFee(q *types.QuoteDetail) {
+	if q.Travel == nil {
+		return
 	}
-	prices.MainServicePrice = dbQuote.TotalPrice
-	return &prices, nil
-}
\ No newline at end of file
+	for _, a := range q.Adjustments {
+		if a.Kind == types.AdjustmentTravelFee {
+			q.Travel.Fee = q.Travel.Fee.Add(a.Amount)
+		}
+	}
+}
+
+// fetchQuoteAdjustments loads the adjustment lines of the given quotes, keyed by quote id.
+func (t *PaymentTasks) fetchQuoteAdjustments(ctx context.Context, tx pgx.Tx, quoteIDs []string) (map[string][]types.QuoteAdjustment, error) {
+	adjustments := map[string][]types.QuoteAdjustment{}
+	if len(quoteIDs) == 0 {
+		return adjustments, nil
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT quote_id, kind, code, label, amount
+		FROM payment.quote_adjustments
+		WHERE quote_id = ANY($1::uuid[])
+		ORDER BY created_at, id`, quoteIDs)
+	if err != nil {
+		return nil, fmt.Errorf("fetch adjustments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			a       types.QuoteAdjustment
+			quoteID string
+		)
+		if err := rows.Scan(&quoteID, &a.Kind, &a.Code, &a.Label, &a.Amount); err != nil {
+			return nil, fmt.Errorf("scan adjustment: %w", err)
+		}
+		adjustments[quoteID] = append(adjustments[quoteID], a)
+	}
+	return adjustments, rows.Err()
+}
+
+// checkQuoteUsable reports why a quote cannot be booked, if it cannot: ErrQuoteNotFound,
+// ErrQuoteConsumed or ErrQuoteExpired. With lock set the quote row is locked for the caller's tx.
+func (t *PaymentTasks) checkQuoteUsable(ctx context.Context, tx pgx.Tx, quoteId, bookingID string, lock bool) error {
+	if err := new(pgtype.UUID).Scan(quoteId); err != nil {
+		return fmt.Errorf("%w: %s", types.ErrQuoteNotFound, quoteId)
+	}
+	query := `
+		SELECT is_valid, expires_at, consumed_at, consumed_by::text
+		FROM payment.quotes
+		WHERE id = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var (
+		isValid    bool
+		expiresAt  time.Time
+		consumedAt *time.Time
+		consumedBy *string
+	)
+	if err := tx.QueryRow(ctx, query, quoteId).Scan(&isValid, &expiresAt, &consumedAt, &consumedBy); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", types.ErrQuoteNotFound, quoteId)
+		}
+		return fmt.Errorf("fetch main quote: %w", err)
+	}
+	if consumedAt != nil {
+		if bookingID != "" && consumedBy != nil && *consumedBy == bookingID {
+			return nil
+		}
+		return fmt.Errorf("%w: %s on %s", types.ErrQuoteConsumed, quoteId, consumedAt.Format(time.RFC3339))
+	}
+	if !isValid || !expiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: %s on %s", types.ErrQuoteExpired, quoteId, expiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ConsumeQuote spends a quote on a booking so it cannot be booked again, redeeming its promo code.
+// It locks the quote row, so concurrent bookings of the same quote wait and then fail with ErrQuoteConsumed.
+func (t *PaymentTasks) ConsumeQuote(ctx context.Context, tx pgx.Tx, quoteId, bookingID string) error {
+	if err := t.checkQuoteUsable(ctx, tx, quoteId, bookingID, true); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE payment.quotes
+		SET is_valid = FALSE, consumed_at = COALESCE(consumed_at, NOW()), consumed_by = $2, updated_at = NOW()
+		WHERE id = $1`, quoteId, bookingID); err != nil {
+		return fmt.Errorf("consume quote: %w", err)
+	}
+	return t.redeemPromotion(ctx, tx, quoteId, bookingID)
+}
+
+// ReplaceQuote moves a booking onto the new quote of its edited job. The old quote stays consumed by
+// the booking, but its promo code redemption is given back, since the new quote's is redeemed instead.
+func (t *PaymentTasks) ReplaceQuote(ctx context.Context, tx pgx.Tx, oldQuoteId, newQuoteId, bookingID string) error {
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM payment.promotion_redemptions
+		WHERE quote_id::text = $1 AND booking_id::text = $2`, oldQuoteId, bookingID); err != nil {
+		return fmt.Errorf("release promotion redemption: %w", err)
+	}
+	return t.ConsumeQuote(ctx, tx, newQuoteId, bookingID)
+}
+
+// CheckQuoteSchedule rejects booking a quote for a slot its time window lines were not priced for, so
+// a quote priced for a weekday morning cannot be booked for a Sunday evening. The slot's lines are
+// recomputed with the quote's catalog and compared by rule code.
+func (t *PaymentTasks) CheckQuoteSchedule(ctx context.Context, tx pgx.Tx, quoteId string, startSched time.Time) error {
+	if err := new(pgtype.UUID).Scan(quoteId); err != nil {
+		return fmt.Errorf("%w: %s", types.ErrQuoteNotFound, quoteId)
+	}
+	var (
+		catalogID *string
+		services  types.Money
+	)
+	if err := tx.QueryRow(ctx, `
+		SELECT catalog_id::text, subtotal + addon_total
+		FROM payment.quotes
+		WHERE id = $1
+	`, quoteId).Scan(&catalogID, &services); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", types.ErrQuoteNotFound, quoteId)
+		}
+		return fmt.Errorf("fetch quote schedule: %w", err)
+	}
+	if catalogID == nil {
+		// priced before catalogs, so without time rules
+		return nil
+	}
+	catalog, err := t.FetchCatalog(ctx, tx, *catalogID)
+	if err != nil {
+		return err
+	}
+	expected, err := t.priceTimeWindows(ctx, tx, &catalog.Rates, services, startSched)
+	if err != nil {
+		return err
+	}
+	want := []string{}
+	for _, line := range expected {
+		want = append(want, line.Code)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT code
+		FROM payment.quote_adjustments
+		WHERE quote_id = $1 AND kind = $2
+	`, quoteId, types.AdjustmentTimeWindow)
+	if err != nil {
+		return fmt.Errorf("fetch time window lines: %w", err)
+	}
+	quoted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("scan time window lines: %w", err)
+	}
+
+	slices.Sort(want)
+	slices.Sort(quoted)
+	if !slices.Equal(want, quoted) {
+		return fmt.Errorf("%w: quoted %v, %s prices %v", types.ErrQuoteSchedule, quoted, startSched.In(PricingZone).Format(time.RFC3339), want)
+	}
+	return nil
+}
+
+// ExpireQuotes invalidates every unused quote past its expiry and returns how many were swept.
+func (t *PaymentTasks) ExpireQuotes(ctx context.Context, tx pgx.Tx) (int64, error) {
+	tag, err := tx.Exec(ctx, `
+		UPDATE payment.quotes
+		SET is_valid = FALSE, updated_at = NOW()
+		WHERE is_valid AND consumed_at IS NULL AND expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("expire quotes: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ValidatePriceRates rejects negative prices and surcharges, empty or inverted SQM bands,
+// unlabelled or lower case modifiers and malformed time rules.
+func ValidatePriceRates(rates *types.PriceRates) error {
+	if err := types.CheckCurrencies(priceRateAmounts(rates)...); err != nil {
+		return fmt.Errorf("%w: %v", types.ErrInvalidCatalog, err)
+	}
+	if rates.GeneralPerSQM.IsNegative() || rates.PostConstructionPerSQM.IsNegative() || rates.ChildSeat.IsNegative() || rates.BedPillow.IsNegative() {
+		return fmt.Errorf("%w: rates cannot be negative", types.ErrInvalidCatalog)
+	}
+	for i, tier := range rates.GeneralTiers {
+		if tier.Price.IsNegative() {
+			return fmt.Errorf("%w: general tier %d has a negative price", types.ErrInvalidCatalog, i)
+		}
+		if tier.MaxSQM <= tier.MinSQM {
+			return fmt.Errorf("%w: general tier %d needs maxSqm above minSqm", types.ErrInvalidCatalog, i)
+		}
+	}
+	for name, prices := range map[string]map[string]types.Money{"mattress": rates.Mattress, "car": rates.Car, "couch": rates.Couch} {
+		for kind, price := range prices {
+			if price.IsNegative() {
+				return fmt.Errorf("%w: %s price for %s is negative", types.ErrInvalidCatalog, name, kind)
+			}
+		}
+	}
+	for i, s := range rates.DirtyScaleSurcharges {
+		if s.MinScale < 0 || s.Percent < 0 || s.Flat.IsNegative() {
+			return fmt.Errorf("%w: dirty scale surcharge %d cannot be negative", types.ErrInvalidCatalog, i)
+		}
+	}
+	for code, m := range rates.Modifiers {
+		if code != strings.ToUpper(strings.TrimSpace(code)) || code == "" {
+			return fmt.Errorf("%w: modifier code %q must be upper case", types.ErrInvalidCatalog, code)
+		}
+		if m.Label == "" {
+			return fmt.Errorf("%w: modifier %s needs a label", types.ErrInvalidCatalog, code)
+		}
+		if m.Percent < 0 || m.Flat.IsNegative() {
+			return fmt.Errorf("%w: modifier %s cannot be negative", types.ErrInvalidCatalog, code)
+		}
+	}
+	codes := map[string]bool{}
+	for i, r := range rates.TimeRules {
+		if r.Code == "" || r.Code != strings.ToUpper(strings.TrimSpace(r.Code)) || codes[r.Code] {
+			return fmt.Errorf("%w: time rule %d needs a unique upper case code", types.ErrInvalidCatalog, i)
+		}
+		codes[r.Code] = true
+		if r.Label == "" {
+			return fmt.Errorf("%w: time rule %s needs a label", types.ErrInvalidCatalog, r.Code)
+		}
+		for _, day := range r.Days {
+			if day != holidayDay && !slices.ContainsFunc(weekdays, func(d time.Weekday) bool { return strings.ToUpper(d.String()) == day }) {
+				return fmt.Errorf("%w: time rule %s has unknown day %q", types.ErrInvalidCatalog, r.Code, day)
+			}
+		}
+		if r.StartHour < 0 || r.StartHour > 23 || r.EndHour < 0 || r.EndHour > 23 {
+			return fmt.Errorf("%w: time rule %s hours must be between 0 and 23", types.ErrInvalidCatalog, r.Code)
+		}
+		if r.Percent < -100 {
+			return fmt.Errorf("%w: time rule %s cannot discount more than 100%%", types.ErrInvalidCatalog, r.Code)
+		}
+	}
+	return nil
+}
+
+// priceRateAmounts lists every amount in a set of rates.
+func priceRateAmounts(rates *types.PriceRates) []types.Money {
+	amounts := []types.Money{rates.GeneralPerSQM, rates.PostConstructionPerSQM, rates.ChildSeat, rates.BedPillow}
+	for _, tier := range rates.GeneralTiers {
+		amounts = append(amounts, tier.Price)
+	}
+	for _, prices := range []map[string]types.Money{rates.Mattress, rates.Car, rates.Couch} {
+		for _, price := range prices {
+			amounts = append(amounts, price)
+		}
+	}
+	for _, s := range rates.DirtyScaleSurcharges {
+		amounts = append(amounts, s.Flat)
+	}
+	for _, m := range rates.Modifiers {
+		amounts = append(amounts, m.Flat)
+	}
+	for _, r := range rates.TimeRules {
+		amounts = append(amounts, r.Flat)
+	}
+	return amounts
+}
+
+var weekdays = []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+
+const priceCatalogColumns = `id, version, effective_from, notes, rates, created_by, created_at, updated_at`
+
+func scanPriceCatalog(row pgx.Row) (*types.PriceCatalog, error) {
+	var (
+		c   types.PriceCatalog
+		raw []byte
+	)
+	if err := row.Scan(&c.ID, &c.Version, &c.EffectiveFrom, &c.Notes, &raw, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &c.Rates); err != nil {
+		return nil, fmt.Errorf("decode rates of catalog version %d: %w", c.Version, err)
+	}
+	return &c, nil
+}
+
+// FetchActiveCatalog returns the catalog version in effect at the given time.
+func (t *PaymentTasks) FetchActiveCatalog(ctx context.Context, tx pgx.Tx, at time.Time) (*types.PriceCatalog, error) {
+	c, err := scanPriceCatalog(tx.QueryRow(ctx, `
+		SELECT `+priceCatalogColumns+`
+		FROM payment.price_catalogs
+		WHERE effective_from <= $1
+		ORDER BY effective_from DESC, version DESC
+		LIMIT 1`, at))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: none in effect at %s", types.ErrCatalogNotFound, at.Format(time.RFC3339))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch active catalog: %w", err)
+	}
+	return c, nil
+}
+
+// FetchCatalog returns one catalog version by id.
+func (t *PaymentTasks) FetchCatalog(ctx context.Context, tx pgx.Tx, id string) (*types.PriceCatalog, error) {
+	if err := new(pgtype.UUID).Scan(id); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrCatalogNotFound, id)
+	}
+	c, err := scanPriceCatalog(tx.QueryRow(ctx, `
+		SELECT `+priceCatalogColumns+`
+		FROM payment.price_catalogs
+		WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", types.ErrCatalogNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch catalog: %w", err)
+	}
+	return c, nil
+}
+
+// FetchCatalogs returns every catalog version, newest first.
+func (t *PaymentTasks) FetchCatalogs(ctx context.Context, tx pgx.Tx) ([]types.PriceCatalog, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+priceCatalogColumns+`
+		FROM payment.price_catalogs
+		ORDER BY version DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("fetch catalogs: %w", err)
+	}
+	defer rows.Close()
+
+	catalogs := []types.PriceCatalog{}
+	for rows.Next() {
+		c, err := scanPriceCatalog(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan catalog: %w", err)
+		}
+		catalogs = append(catalogs, *c)
+	}
+	return catalogs, rows.Err()
+}
+
+// CreateCatalog adds the next catalog version.
+func (t *PaymentTasks) CreateCatalog(ctx context.Context, tx pgx.Tx, effectiveFrom time.Time, notes string, rates *types.PriceRates, actor string) (*types.PriceCatalog, error) {
+	raw, err := json.Marshal(rates)
+	if err != nil {
+		return nil, fmt.Errorf("encode rates: %w", err)
+	}
+	// serialise version numbering between concurrent creates
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('payment.price_catalogs'))`); err != nil {
+		return nil, fmt.Errorf("lock catalogs: %w", err)
+	}
+	c, err := scanPriceCatalog(tx.QueryRow(ctx, `
+		INSERT INTO payment.price_catalogs (version, effective_from, notes, rates, created_by)
+		SELECT COALESCE(MAX(version), 0) + 1, $1, $2, $3, $4
+		FROM payment.price_catalogs
+		RETURNING `+priceCatalogColumns, effectiveFrom, notes, raw, actor))
+	if err != nil {
+		return nil, fmt.Errorf("insert catalog: %w", err)
+	}
+	return c, nil
+}
+
+// UpdateCatalog replaces a catalog version that has not taken effect yet.
+func (t *PaymentTasks) UpdateCatalog(ctx context.Context, tx pgx.Tx, id string, effectiveFrom time.Time, notes string, rates *types.PriceRates) (*types.PriceCatalog, error) {
+	if err := t.lockPendingCatalog(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(rates)
+	if err != nil {
+		return nil, fmt.Errorf("encode rates: %w", err)
+	}
+	c, err := scanPriceCatalog(tx.QueryRow(ctx, `
+		UPDATE payment.price_catalogs
+		SET effective_from = $2, notes = $3, rates = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+priceCatalogColumns, id, effectiveFrom, notes, raw))
+	if err != nil {
+		return nil, fmt.Errorf("update catalog: %w", err)
+	}
+	return c, nil
+}
+
+// DeleteCatalog removes a catalog version that has not taken effect yet.
+func (t *PaymentTasks) DeleteCatalog(ctx context.Context, tx pgx.Tx, id string) (*types.PriceCatalog, error) {
+	if err := t.lockPendingCatalog(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	c, err := scanPriceCatalog(tx.QueryRow(ctx, `
+		DELETE FROM payment.price_catalogs
+		WHERE id = $1
+		RETURNING `+priceCatalogColumns, id))
+	if err != nil {
+		return nil, fmt.Errorf("delete catalog: %w", err)
+	}
+	return c, nil
+}
+
+// lockPendingCatalog locks a catalog version, failing if it is unknown or already in effect,
+// since quotes may have been priced with it.
+func (t *PaymentTasks) lockPendingCatalog(ctx context.Context, tx pgx.Tx, id string) error {
+	if err := new(pgtype.UUID).Scan(id); err != nil {
+		return fmt.Errorf("%w: %s", types.ErrCatalogNotFound, id)
+	}
+	var effectiveFrom time.Time
+	if err := tx.QueryRow(ctx, `
+		SELECT effective_from
+		FROM payment.price_catalogs
+		WHERE id = $1
+		FOR UPDATE`, id).Scan(&effectiveFrom); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", types.ErrCatalogNotFound, id)
+		}
+		return fmt.Errorf("fetch catalog: %w", err)
+	}
+	if !effectiveFrom.After(time.Now()) {
+		return fmt.Errorf("%w: effective since %s", types.ErrCatalogInEffect, effectiveFrom.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidatePromotion normalizes a promotion request and rejects inconsistent discounts and limits.
+func ValidatePromotion(req *types.PromotionRequest) error {
+	req.Code = normalizePromoCode(req.Code)
+	if req.Code == "" {
+		return fmt.Errorf("%w: code is required", types.ErrInvalidPromotion)
+	}
+	switch req.DiscountType {
+	case types.DiscountPercent:
+		if req.PercentOff == nil || *req.PercentOff < 1 || *req.PercentOff > 100 {
+			return fmt.Errorf("%w: percentOff must be between 1 and 100", types.ErrInvalidPromotion)
+		}
+		req.AmountOff = nil
+	case types.DiscountFixed:
+		if req.AmountOff == nil || req.AmountOff.Minor <= 0 {
+			return fmt.Errorf("%w: amountOff must be positive", types.ErrInvalidPromotion)
+		}
+		req.PercentOff = nil
+	default:
+		return fmt.Errorf("%w: unknown discountType %q", types.ErrInvalidPromotion, req.DiscountType)
+	}
+	if req.MinSpend.IsNegative() {
+		return fmt.Errorf("%w: minSpend cannot be negative", types.ErrInvalidPromotion)
+	}
+	amounts := []types.Money{req.MinSpend}
+	if req.AmountOff != nil {
+		amounts = append(amounts, *req.AmountOff)
+	}
+	if err := types.CheckCurrencies(amounts...); err != nil {
+		return fmt.Errorf("%w: %v", types.ErrInvalidPromotion, err)
+	}
+	if req.UsageLimit != nil && *req.UsageLimit < 1 {
+		return fmt.Errorf("%w: usageLimit must be positive", types.ErrInvalidPromotion)
+	}
+	if req.PerCustomerLimit != nil && *req.PerCustomerLimit < 1 {
+		return fmt.Errorf("%w: perCustomerLimit must be positive", types.ErrInvalidPromotion)
+	}
+	if req.ServiceTypes == nil {
+		req.ServiceTypes = []string{}
+	}
+	for _, st := range req.ServiceTypes {
+		switch types.MainServiceType(st) {
+		case types.GeneralCleaning, types.CouchCleaning, types.MattressCleaning, types.CarCleaning, types.PostCleaning:
+		default:
+			return fmt.Errorf("%w: unknown service type %q", types.ErrInvalidPromotion, st)
+		}
+	}
+	return nil
+}
+
+const promotionColumns = `p.id, p.code, p.description, p.discount_type, p.percent_off, p.amount_off, p.min_spend, p.valid_from, p.valid_until,
+	p.usage_limit, p.per_customer_limit, p.service_types, p.is_active,
+	(SELECT COUNT(*) FROM payment.promotion_redemptions r WHERE r.promotion_id = p.id)::int,
+	p.created_by, p.created_at, p.updated_at`
+
+func scanPromotion(row pgx.Row) (*types.Promotion, error) {
+	var p types.Promotion
+	if err := row.Scan(
+		&p.ID,
+		&p.Code,
+		&p.Description,
+		&p.DiscountType,
+		&p.PercentOff,
+		&p.AmountOff,
+		&p.MinSpend,
+		&p.ValidFrom,
+		&p.ValidUntil,
+		&p.UsageLimit,
+		&p.PerCustomerLimit,
+		&p.ServiceTypes,
+		&p.IsActive,
+		&p.Redemptions,
+		&p.CreatedBy,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// PricePromotion checks a promo code against a priced quote and returns the promotion with its
+// discount line. Every reason the code does not apply is an ErrPromoCodeRejected.
+func (t *PaymentTasks) PricePromotion(ctx context.Context, tx pgx.Tx, code string, quote *types.Quote, at time.Time) (*types.Promotion, *types.QuoteAdjustment, error) {
+	promo, err := scanPromotion(tx.QueryRow(ctx, `
+		SELECT `+promotionColumns+`
+		FROM payment.promotions p
+		WHERE p.code = $1`, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, fmt.Errorf("%w: unknown code %s", types.ErrPromoCodeRejected, code)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch promotion: %w", err)
+	}
+
+	switch {
+	case !promo.IsActive:
+		return nil, nil, fmt.Errorf("%w: %s is no longer active", types.ErrPromoCodeRejected, code)
+	case at.Before(promo.ValidFrom):
+		return nil, nil, fmt.Errorf("%w: %s is valid from %s", types.ErrPromoCodeRejected, code, promo.ValidFrom.Format(time.RFC3339))
+	case promo.ValidUntil != nil && !at.Before(*promo.ValidUntil):
+		return nil, nil, fmt.Errorf("%w: %s expired on %s", types.ErrPromoCodeRejected, code, promo.ValidUntil.Format(time.RFC3339))
+	case promo.UsageLimit != nil && promo.Redemptions >= *promo.UsageLimit:
+		return nil, nil, fmt.Errorf("%w: %s has reached its usage limit", types.ErrPromoCodeRejected, code)
+	}
+
+	if promo.PerCustomerLimit != nil && quote.CustomerID != "" {
+		var used int32
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*)
+			FROM payment.promotion_redemptions
+			WHERE promotion_id = $1 AND customer_id = $2`, promo.ID, quote.CustomerID).Scan(&used); err != nil {
+			return nil, nil, fmt.Errorf("count redemptions: %w", err)
+		}
+		if used >= *promo.PerCustomerLimit {
+			return nil, nil, fmt.Errorf("%w: %s was already used %d times by this customer", types.ErrPromoCodeRejected, code, used)
+		}
+	}
+
+	spend := quote.Subtotal.Add(quote.AddonTotal)
+	if spend.Minor < promo.MinSpend.Minor {
+		return nil, nil, fmt.Errorf("%w: %s needs a minimum spend of %s", types.ErrPromoCodeRejected, code, promo.MinSpend)
+	}
+
+	// with service restrictions only the matching services are discounted
+	eligible := spend
+	if len(promo.ServiceTypes) > 0 {
+		eligible = types.Money{}
+		if slices.Contains(promo.ServiceTypes, quote.MainService) {
+			eligible = eligible.Add(quote.Subtotal)
+		}
+		for _, a := range quote.Addons {
+			if slices.Contains(promo.ServiceTypes, a.ServiceType) {
+				eligible = eligible.Add(a.AddonPrice)
+			}
+		}
+		if eligible.IsZero() {
+			return nil, nil, fmt.Errorf("%w: %s only applies to %s", types.ErrPromoCodeRejected, code, strings.Join(promo.ServiceTypes, ", "))
+		}
+	}
+
+	var discount types.Money
+	switch promo.DiscountType {
+	case types.DiscountPercent:
+		discount = eligible.Percent(int64(*promo.PercentOff))
+	case types.DiscountFixed:
+		discount = *promo.AmountOff
+		if discount.Minor > eligible.Minor {
+			discount = eligible
+		}
+	}
+
+	label := promo.Description
+	if label == "" {
+		label = "Promo " + promo.Code
+	}
+	return promo, &types.QuoteAdjustment{
+		Kind:   types.AdjustmentDiscount,
+		Code:   promo.Code,
+		Label:  label,
+		Amount: types.Money{Currency: discount.Currency}.Sub(discount),
+	}, nil
+}
+
+// redeemPromotion records the redemption of a consumed quote's promo code, once per quote. The
+// discount priced into the quote is honoured, but the usage limits are checked again under a lock
+// on the promotion so concurrent bookings cannot overshoot them.
+func (t *PaymentTasks) redeemPromotion(ctx context.Context, tx pgx.Tx, quoteId, bookingID string) error {
+	var (
+		promotionID *string
+		customerID  string
+		redeemed    bool
+	)
+	if err := tx.QueryRow(ctx, `
+		SELECT promotion_id::text, customer_id,
+			EXISTS (SELECT 1 FROM payment.promotion_redemptions WHERE quote_id = q.id)
+		FROM payment.quotes q
+		WHERE id = $1`, quoteId).Scan(&promotionID, &customerID, &redeemed); err != nil {
+		return fmt.Errorf("fetch quote promotion: %w", err)
+	}
+	if promotionID == nil || redeemed {
+		return nil
+	}
+
+	var (
+		code                         string
+		usageLimit, perCustomerLimit *int32
+	)
+	if err := tx.QueryRow(ctx, `
+		SELECT code, usage_limit, per_customer_limit
+		FROM payment.promotions
+		WHERE id = $1
+		FOR UPDATE`, *promotionID).Scan(&code, &usageLimit, &perCustomerLimit); err != nil {
+		return fmt.Errorf("lock promotion: %w", err)
+	}
+	var total, byCustomer int32
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE customer_id = $2)
+		FROM payment.promotion_redemptions
+		WHERE promotion_id = $1`, *promotionID, customerID).Scan(&total, &byCustomer); err != nil {
+		return fmt.Errorf("count redemptions: %w", err)
+	}
+	if usageLimit != nil && total >= *usageLimit {
+		return fmt.Errorf("%w: %s", types.ErrPromoExhausted, code)
+	}
+	if perCustomerLimit != nil && byCustomer >= *perCustomerLimit {
+		return fmt.Errorf("%w: %s for this customer", types.ErrPromoExhausted, code)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO payment.promotion_redemptions (promotion_id, quote_id, booking_id, customer_id, amount)
+		SELECT $1, $2, $3, $4, COALESCE(-SUM(amount), 0)
+		FROM payment.quote_adjustments
+		WHERE quote_id = $2 AND kind = $5`,
+		*promotionID, quoteId, bookingID, customerID, types.AdjustmentDiscount); err != nil {
+		return fmt.Errorf("record redemption: %w", err)
+	}
+	return nil
+}
+
+// FetchPromotions returns every promotion, newest first.
+func (t *PaymentTasks) FetchPromotions(ctx context.Context, tx pgx.Tx) ([]types.Promotion, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+promotionColumns+`
+		FROM payment.promotions p
+		ORDER BY p.created_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("fetch promotions: %w", err)
+	}
+	defer rows.Close()
+
+	promotions := []types.Promotion{}
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan promotion: %w", err)
+		}
+		promotions = append(promotions, *p)
+	}
+	return promotions, rows.Err()
+}
+
+// CreatePromotion adds a promotion. Codes are unique.
+func (t *PaymentTasks) CreatePromotion(ctx context.Context, tx pgx.Tx, req *types.PromotionRequest, validFrom time.Time, isActive bool) (*types.Promotion, error) {
+	p, err := scanPromotion(tx.QueryRow(ctx, `
+		INSERT INTO payment.promotions AS p
+			(code, description, discount_type, percent_off, amount_off, min_spend, valid_from, valid_until,
+			 usage_limit, per_customer_limit, service_types, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (code) DO NOTHING
+		RETURNING `+promotionColumns,
+		req.Code, req.Description, req.DiscountType, req.PercentOff, req.AmountOff, req.MinSpend, validFrom, req.ValidUntil,
+		req.UsageLimit, req.PerCustomerLimit, req.ServiceTypes, isActive, req.Actor))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: code %s already exists", types.ErrInvalidPromotion, req.Code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("insert promotion: %w", err)
+	}
+	return p, nil
+}
+
+// UpdatePromotion replaces a promotion. Quotes already discounted keep their discount.
+func (t *PaymentTasks) UpdatePromotion(ctx context.Context, tx pgx.Tx, req *types.PromotionRequest, validFrom time.Time, isActive bool) (*types.Promotion, error) {
+	if err := new(pgtype.UUID).Scan(req.ID); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrPromotionNotFound, req.ID)
+	}
+	var taken bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM payment.promotions WHERE code = $1 AND id <> $2)`, req.Code, req.ID).Scan(&taken); err != nil {
+		return nil, fmt.Errorf("check promotion code: %w", err)
+	}
+	if taken {
+		return nil, fmt.Errorf("%w: code %s already exists", types.ErrInvalidPromotion, req.Code)
+	}
+	p, err := scanPromotion(tx.QueryRow(ctx, `
+		UPDATE payment.promotions p
+		SET code = $2, description = $3, discount_type = $4, percent_off = $5, amount_off = $6, min_spend = $7,
+			valid_from = $8, valid_until = $9, usage_limit = $10, per_customer_limit = $11, service_types = $12,
+			is_active = $13, updated_at = NOW()
+		WHERE p.id = $1
+		RETURNING `+promotionColumns,
+		req.ID, req.Code, req.Description, req.DiscountType, req.PercentOff, req.AmountOff, req.MinSpend,
+		validFrom, req.ValidUntil, req.UsageLimit, req.PerCustomerLimit, req.ServiceTypes, isActive))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", types.ErrPromotionNotFound, req.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update promotion: %w", err)
+	}
+	return p, nil
+}
+
+// DeactivatePromotion stops a promotion from applying to new quotes. It is kept for its redemptions.
+func (t *PaymentTasks) DeactivatePromotion(ctx context.Context, tx pgx.Tx, id string) (*types.Promotion, error) {
+	if err := new(pgtype.UUID).Scan(id); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrPromotionNotFound, id)
+	}
+	p, err := scanPromotion(tx.QueryRow(ctx, `
+		UPDATE payment.promotions p
+		SET is_active = FALSE, updated_at = NOW()
+		WHERE p.id = $1
+		RETURNING `+promotionColumns, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", types.ErrPromotionNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("deactivate promotion: %w", err)
+	}
+	return p, nil
+}
+
+// ValidateDepot rejects coordinates off the globe and bands that are empty, unordered or negative.
+func ValidateDepot(req *types.DepotRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return fmt.Errorf("%w: name is required", types.ErrInvalidDepot)
+	}
+	if *req.Lat < -90 || *req.Lat > 90 || *req.Lng < -180 || *req.Lng > 180 {
+		return fmt.Errorf("%w: coordinates out of range", types.ErrInvalidDepot)
+	}
+	if len(req.Bands) == 0 {
+		return fmt.Errorf("%w: at least one band is required", types.ErrInvalidDepot)
+	}
+	var prev float64
+	for i, b := range req.Bands {
+		if b.MaxKm <= prev {
+			return fmt.Errorf("%w: band %d must reach further than the one before it", types.ErrInvalidDepot, i)
+		}
+		if b.Fee.IsNegative() || b.PerKm.IsNegative() {
+			return fmt.Errorf("%w: band %d cannot be negative", types.ErrInvalidDepot, i)
+		}
+		if err := types.CheckCurrencies(b.Fee, b.PerKm); err != nil {
+			return fmt.Errorf("%w: band %d: %v", types.ErrInvalidDepot, i, err)
+		}
+		prev = b.MaxKm
+	}
+	return nil
+}
+
+const depotColumns = `id, name, lat, lng, bands, is_active, created_at, updated_at`
+
+func scanDepot(row pgx.Row) (*types.Depot, error) {
+	var (
+		d   types.Depot
+		raw []byte
+	)
+	if err := row.Scan(&d.ID, &d.Name, &d.Lat, &d.Lng, &raw, &d.IsActive, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &d.Bands); err != nil {
+		return nil, fmt.Errorf("decode bands of depot %s: %w", d.Name, err)
+	}
+	return &d, nil
+}
+
+// FetchDepots returns the depots by name, only the active ones if activeOnly is set.
+func (t *PaymentTasks) FetchDepots(ctx context.Context, tx pgx.Tx, activeOnly bool) ([]types.Depot, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+depotColumns+`
+		FROM payment.depots
+		WHERE is_active OR NOT $1
+		ORDER BY name`, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("fetch depots: %w", err)
+	}
+	defer rows.Close()
+
+	depots := []types.Depot{}
+	for rows.Next() {
+		d, err := scanDepot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan depot: %w", err)
+		}
+		depots = append(depots, *d)
+	}
+	return depots, rows.Err()
+}
+
+// CreateDepot adds a depot. Names are unique.
+func (t *PaymentTasks) CreateDepot(ctx context.Context, tx pgx.Tx, req *types.DepotRequest, isActive bool) (*types.Depot, error) {
+	raw, err := json.Marshal(req.Bands)
+	if err != nil {
+		return nil, fmt.Errorf("encode bands: %w", err)
+	}
+	d, err := scanDepot(tx.QueryRow(ctx, `
+		INSERT INTO payment.depots (name, lat, lng, bands, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO NOTHING
+		RETURNING `+depotColumns, req.Name, *req.Lat, *req.Lng, raw, isActive))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: a depot named %s already exists", types.ErrInvalidDepot, req.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("insert depot: %w", err)
+	}
+	return d, nil
+}
+
+// UpdateDepot replaces a depot. Quotes already priced keep their travel fee.
+func (t *PaymentTasks) UpdateDepot(ctx context.Context, tx pgx.Tx, req *types.DepotRequest, isActive bool) (*types.Depot, error) {
+	if err := new(pgtype.UUID).Scan(req.ID); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrDepotNotFound, req.ID)
+	}
+	raw, err := json.Marshal(req.Bands)
+	if err != nil {
+		return nil, fmt.Errorf("encode bands: %w", err)
+	}
+	var taken bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM payment.depots WHERE name = $1 AND id <> $2)`, req.Name, req.ID).Scan(&taken); err != nil {
+		return nil, fmt.Errorf("check depot name: %w", err)
+	}
+	if taken {
+		return nil, fmt.Errorf("%w: a depot named %s already exists", types.ErrInvalidDepot, req.Name)
+	}
+	d, err := scanDepot(tx.QueryRow(ctx, `
+		UPDATE payment.depots
+		SET name = $2, lat = $3, lng = $4, bands = $5, is_active = $6, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+depotColumns, req.ID, req.Name, *req.Lat, *req.Lng, raw, isActive))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", types.ErrDepotNotFound, req.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update depot: %w", err)
+	}
+	return d, nil
+}
+
+// DeactivateDepot takes a depot out of the service area. It is kept for the quotes it priced.
+func (t *PaymentTasks) DeactivateDepot(ctx context.Context, tx pgx.Tx, id string) (*types.Depot, error) {
+	if err := new(pgtype.UUID).Scan(id); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrDepotNotFound, id)
+	}
+	d, err := scanDepot(tx.QueryRow(ctx, `
+		UPDATE payment.depots
+		SET is_active = FALSE, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+depotColumns, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", types.ErrDepotNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("deactivate depot: %w", err)
+	}
+	return d, nil
+}
+
+// FetchHolidays returns the holiday calendar from the given date on, in date order.
+func (t *PaymentTasks) FetchHolidays(ctx context.Context, tx pgx.Tx, from time.Time) ([]types.Holiday, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT date::text, name, created_at
+		FROM payment.holidays
+		WHERE date >= $1::date
+		ORDER BY date
+	`, from.In(PricingZone).Format(time.DateOnly))
+	if err != nil {
+		return nil, fmt.Errorf("fetch holidays: %w", err)
+	}
+	defer rows.Close()
+
+	holidays := []types.Holiday{}
+	for rows.Next() {
+		var h types.Holiday
+		if err := rows.Scan(&h.Date, &h.Name, &h.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan holiday: %w", err)
+		}
+		holidays = append(holidays, h)
+	}
+	return holidays, rows.Err()
+}
+
+// ValidateHoliday rejects a holiday without a YYYY-MM-DD date or a name and trims the name.
+func ValidateHoliday(req *types.HolidayRequest) error {
+	if _, err := time.Parse(time.DateOnly, req.Date); err != nil {
+		return fmt.Errorf("%w: date must be YYYY-MM-DD", types.ErrInvalidHoliday)
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return fmt.Errorf("%w: name is required", types.ErrInvalidHoliday)
+	}
+	return nil
+}
+
+// SaveHoliday adds a date to the holiday calendar, renaming it if it is already there.
+func (t *PaymentTasks) SaveHoliday(ctx context.Context, tx pgx.Tx, req *types.HolidayRequest) (*types.Holiday, error) {
+	var h types.Holiday
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO payment.holidays (date, name)
+		VALUES ($1::date, $2)
+		ON CONFLICT (date) DO UPDATE SET name = EXCLUDED.name
+		RETURNING date::text, name, created_at
+	`, req.Date, req.Name).Scan(&h.Date, &h.Name, &h.CreatedAt); err != nil {
+		return nil, fmt.Errorf("save holiday: %w", err)
+	}
+	return &h, nil
+}
+
+// DeleteHoliday removes a date from the holiday calendar.
+func (t *PaymentTasks) DeleteHoliday(ctx context.Context, tx pgx.Tx, date string) (*types.Holiday, error) {
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrHolidayNotFound, date)
+	}
+	var h types.Holiday
+	if err := tx.QueryRow(ctx, `
+		DELETE FROM payment.holidays
+		WHERE date = $1::date
+		RETURNING date::text, name, created_at
+	`, date).Scan(&h.Date, &h.Name, &h.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", types.ErrHolidayNotFound, date)
+		}
+		return nil, fmt.Errorf("delete holiday: %w", err)
+	}
+	return &h, nil
+}
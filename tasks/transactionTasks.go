@@ -0,0 +1,487 @@
+package tasks
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"handworks-api/types"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// FetchBookingBalance totals a booking's settled payments net of refunds against its price. With
+// lock set the booking row is locked for the caller's tx, so payments of one booking run one at a time.
+func (t *PaymentTasks) FetchBookingBalance(ctx context.Context, tx pgx.Tx, bookingID string, lock bool) (*types.BookingBalance, error) {
+	if err := new(pgtype.UUID).Scan(bookingID); err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrBookingNotFound, bookingID)
+	}
+	query := `
+		SELECT b.id, s.service_type, bb.status, bb.payment_status, b.total_price
+		FROM booking.bookings b
+		JOIN booking.basebookings bb ON bb.id = b.base_booking_id
+		JOIN booking.services s ON s.id = b.main_service_id
+		WHERE b.id = $1`
+	if lock {
+		query += ` FOR UPDATE OF b`
+	}
+	var balance types.BookingBalance
+	if err := tx.QueryRow(ctx, query, bookingID).Scan(
+		&balance.BookingID,
+		&balance.ServiceType,
+		&balance.BookingStatus,
+		&balance.PaymentStatus,
+		&balance.Total,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", types.ErrBookingNotFound, bookingID)
+		}
+		return nil, fmt.Errorf("fetch booking balance: %w", err)
+	}
+	if err := tx.QueryRow(ctx, `
+		SELECT
+			COALESCE(SUM(CASE WHEN kind = $2 THEN amount ELSE -amount END) FILTER (WHERE status = $3), 0),
+			COALESCE(SUM(amount) FILTER (WHERE kind = $2 AND status = $4), 0)
+		FROM payment.transactions
+		WHERE booking_id = $1
+	`, bookingID, types.TransactionPayment, types.TransactionSucceeded, types.TransactionPending).Scan(
+		&balance.Paid,
+		&balance.Pending,
+	); err != nil {
+		return nil, fmt.Errorf("sum booking payments: %w", err)
+	}
+	balance.Balance = balance.Total.Sub(balance.Paid)
+	return &balance, nil
+}
+
+// DepositFor returns the deposit the first matching rule of the policy asks for, or zero.
+func DepositFor(policy types.DepositPolicy, serviceType types.MainServiceType, total types.Money) types.Money {
+	for _, r := range policy.Rules {
+		if len(r.ServiceTypes) > 0 && !slices.Contains(r.ServiceTypes, serviceType) {
+			continue
+		}
+		if total.Minor < r.MinTotal.Minor {
+			continue
+		}
+		deposit := r.Fixed
+		if deposit.IsZero() {
+			deposit = total.Percent(int64(r.Percent))
+		}
+		if deposit.Minor > total.Minor {
+			deposit = total
+		}
+		return types.Money{Minor: deposit.Minor, Currency: total.Currency}
+	}
+	return types.Money{Currency: total.Currency}
+}
+
+// ApplyDeposit fills in a balance's deposit and what is due now: the outstanding deposit until
+// the booking is completed, the whole balance after.
+func ApplyDeposit(policy types.DepositPolicy, balance *types.BookingBalance) {
+	balance.Deposit = DepositFor(policy, balance.ServiceType, balance.Total)
+	balance.DepositOutstanding = balance.Deposit.Sub(balance.Paid)
+	if balance.DepositOutstanding.IsNegative() {
+		balance.DepositOutstanding = types.Money{Currency: balance.Total.Currency}
+	}
+	balance.AmountDue = balance.DepositOutstanding
+	if balance.BookingStatus == types.BookingCompleted {
+		balance.AmountDue = balance.Balance
+	}
+	if balance.AmountDue.IsNegative() {
+		balance.AmountDue = types.Money{Currency: balance.Total.Currency}
+	}
+}
+
+// RefreshPaymentStatus sets a booking's payment status from its settled transactions and marks its
+// invoice paid once the booking is.
+func (t *PaymentTasks) RefreshPaymentStatus(ctx context.Context, tx pgx.Tx, bookingID string) (*types.BookingBalance, error) {
+	balance, err := t.FetchBookingBalance(ctx, tx, bookingID, false)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case balance.Balance.Minor <= 0:
+		balance.PaymentStatus = types.PaymentPaid
+	case balance.Paid.Minor > 0:
+		balance.PaymentStatus = types.PaymentPartiallyPaid
+	default:
+		balance.PaymentStatus = types.PaymentUnpaid
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE booking.basebookings bb
+		SET payment_status = $2, updated_at = NOW()
+		FROM booking.bookings b
+		WHERE b.id = $1 AND bb.id = b.base_booking_id AND bb.payment_status <> $2
+	`, bookingID, balance.PaymentStatus); err != nil {
+		return nil, fmt.Errorf("update payment status: %w", err)
+	}
+	if balance.PaymentStatus == types.PaymentPaid {
+		if _, err := tx.Exec(ctx, `
+			UPDATE payment.invoices
+			SET status = $2, paid_at = NOW(), updated_at = NOW()
+			WHERE booking_id = $1 AND status = $3
+		`, bookingID, types.InvoicePaid, types.InvoiceIssued); err != nil {
+			return nil, fmt.Errorf("mark invoice paid: %w", err)
+		}
+	}
+	return balance, nil
+}
+
+const transactionColumns = `id, booking_id::text, kind, status, provider, provider_ref, amount, method, failure_reason,
+	idempotency_key, request_key, payment_id::text, reason, note, created_at, updated_at`
+
+func scanTransaction(row pgx.Row) (*types.Transaction, error) {
+	var txn types.Transaction
+	if err := row.Scan(
+		&txn.ID,
+		&txn.BookingID,
+		&txn.Kind,
+		&txn.Status,
+		&txn.Provider,
+		&txn.ProviderRef,
+		&txn.Amount,
+		&txn.Method,
+		&txn.FailureReason,
+		&txn.IdempotencyKey,
+		&txn.RequestKey,
+		&txn.PaymentID,
+		&txn.Reason,
+		&txn.Note,
+		&txn.CreatedAt,
+		&txn.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &txn, nil
+}
+
+func (t *PaymentTasks) FetchTransaction(ctx context.Context, tx pgx.Tx, id string) (*types.Transaction, error) {
+	txn, err := scanTransaction(tx.QueryRow(ctx, `SELECT `+transactionColumns+` FROM payment.transactions WHERE id = $1`, id))
+	if err != nil {
+		return nil, fmt.Errorf("fetch transaction %s: %w", id, err)
+	}
+	return txn, nil
+}
+
+// FetchTransactionByKey returns the transaction made with an idempotency key, or nil if there is none.
+func (t *PaymentTasks) FetchTransactionByKey(ctx context.Context, tx pgx.Tx, key string) (*types.Transaction, error) {
+	txn, err := scanTransaction(tx.QueryRow(ctx, `SELECT `+transactionColumns+` FROM payment.transactions WHERE idempotency_key = $1`, key))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch transaction by key: %w", err)
+	}
+	return txn, nil
+}
+
+// FetchTransactions returns a booking's transactions, oldest first.
+func (t *PaymentTasks) FetchTransactions(ctx context.Context, tx pgx.Tx, bookingID string) ([]types.Transaction, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+transactionColumns+`
+		FROM payment.transactions
+		WHERE booking_id = $1
+		ORDER BY created_at, id
+	`, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := []types.Transaction{}
+	for rows.Next() {
+		txn, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan transaction: %w", err)
+		}
+		transactions = append(transactions, *txn)
+	}
+	return transactions, rows.Err()
+}
+
+// FetchRefundsByKey returns the refunds a refund request made with an idempotency key, oldest first.
+// The key is matched exactly against the request key the refunds were recorded with.
+func (t *PaymentTasks) FetchRefundsByKey(ctx context.Context, tx pgx.Tx, key string) ([]types.Transaction, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+transactionColumns+`
+		FROM payment.transactions
+		WHERE kind = $1 AND request_key = $2
+		ORDER BY created_at, id
+	`, types.TransactionRefund, key)
+	if err != nil {
+		return nil, fmt.Errorf("fetch refunds by key: %w", err)
+	}
+	defer rows.Close()
+
+	refunds := []types.Transaction{}
+	for rows.Next() {
+		txn, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan refund: %w", err)
+		}
+		refunds = append(refunds, *txn)
+	}
+	return refunds, rows.Err()
+}
+
+// RefundablePayment is a captured payment and what of it can still be refunded.
+type RefundablePayment struct {
+	Payment    types.Transaction
+	Refundable types.Money
+}
+
+// RefundablePayments picks the succeeded payments of a booking's transactions with something left
+// to refund, newest first. Pending refunds count as refunded, so two refunds cannot return the
+// same money.
+func RefundablePayments(transactions []types.Transaction) []RefundablePayment {
+	refunded := map[string]types.Money{}
+	for _, txn := range transactions {
+		if txn.Kind == types.TransactionRefund && txn.PaymentID != nil && txn.Status != types.TransactionFailed {
+			refunded[*txn.PaymentID] = refunded[*txn.PaymentID].Add(txn.Amount)
+		}
+	}
+	payments := []RefundablePayment{}
+	for i := len(transactions) - 1; i >= 0; i-- {
+		txn := transactions[i]
+		if txn.Kind != types.TransactionPayment || txn.Status != types.TransactionSucceeded || txn.ProviderRef == nil {
+			continue
+		}
+		if left := txn.Amount.Sub(refunded[txn.ID]); left.Minor > 0 {
+			payments = append(payments, RefundablePayment{Payment: txn, Refundable: left})
+		}
+	}
+	return payments
+}
+
+// CreateTransaction records a PENDING transaction before the provider is called.
+func (t *PaymentTasks) CreateTransaction(ctx context.Context, tx pgx.Tx, txn *types.Transaction) (*types.Transaction, error) {
+	created, err := scanTransaction(tx.QueryRow(ctx, `
+		INSERT INTO payment.transactions (booking_id, kind, status, provider, provider_ref, amount, method, idempotency_key,
+			request_key, payment_id, reason, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING `+transactionColumns,
+		txn.BookingID,
+		txn.Kind,
+		types.TransactionPending,
+		txn.Provider,
+		txn.ProviderRef,
+		txn.Amount,
+		txn.Method,
+		txn.IdempotencyKey,
+		txn.RequestKey,
+		txn.PaymentID,
+		txn.Reason,
+		txn.Note,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("insert transaction: %w", err)
+	}
+	return created, nil
+}
+
+// RecordProviderRef stores the provider's id of a pending transaction as soon as it is known, so
+// the transaction can be reconciled with the provider if it is never settled.
+func (t *PaymentTasks) RecordProviderRef(ctx context.Context, tx pgx.Tx, id, providerRef string) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE payment.transactions
+		SET provider_ref = $2, updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, id, providerRef, types.TransactionPending); err != nil {
+		return fmt.Errorf("record provider ref of %s: %w", id, err)
+	}
+	return nil
+}
+
+// FetchStaleTransactions returns the payments and refunds still PENDING that were started before
+// olderThan, oldest first.
+func (t *PaymentTasks) FetchStaleTransactions(ctx context.Context, tx pgx.Tx, olderThan time.Time) ([]types.Transaction, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+transactionColumns+`
+		FROM payment.transactions
+		WHERE status = $1 AND created_at < $2
+		ORDER BY created_at, id
+	`, types.TransactionPending, olderThan)
+	if err != nil {
+		return nil, fmt.Errorf("fetch stale transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := []types.Transaction{}
+	for rows.Next() {
+		txn, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan transaction: %w", err)
+		}
+		transactions = append(transactions, *txn)
+	}
+	return transactions, rows.Err()
+}
+
+// CaptureOutcome decides how a payment run through the provider ends: it succeeds once its intent
+// is captured, and fails with the provider's reason when declined or when the provider errs.
+func CaptureOutcome(intent *types.PaymentIntent, providerErr error) (types.TransactionStatus, string) {
+	switch {
+	case providerErr != nil:
+		return types.TransactionFailed, providerErr.Error()
+	case intent.Status != types.IntentSucceeded:
+		return types.TransactionFailed, cmp.Or(intent.FailureReason, string(intent.Status))
+	}
+	return types.TransactionSucceeded, ""
+}
+
+// StalePaymentOutcome decides how a payment left PENDING ends, from its intent at the provider, or
+// nil if no intent was recorded. A captured payment succeeds and a declined one fails. One never
+// captured fails as expired: capture only happens after its intent is recorded, so a payment
+// without one was never charged, and an authorised one is not captured long after the customer asked.
+func StalePaymentOutcome(intent *types.PaymentIntent) (types.TransactionStatus, string) {
+	switch {
+	case intent == nil:
+		return types.TransactionFailed, "expired: the provider was never reached"
+	case intent.Status == types.IntentSucceeded:
+		return types.TransactionSucceeded, ""
+	case intent.Status == types.IntentFailed:
+		return types.TransactionFailed, cmp.Or(intent.FailureReason, string(intent.Status))
+	}
+	return types.TransactionFailed, "expired: not captured, provider status " + string(intent.Status)
+}
+
+// StaleRefundOutcome decides how a refund left PENDING ends, from the intent of the payment it
+// returns. The provider made the refund if the intent has more refunded than the booking's
+// succeeded refunds of that payment account for; otherwise it never did, and the refund fails so
+// the money can be refunded again.
+func StaleRefundOutcome(refund types.Transaction, intent *types.PaymentIntent, transactions []types.Transaction) (types.TransactionStatus, string) {
+	if intent == nil {
+		return types.TransactionFailed, "expired: the payment has no provider intent"
+	}
+	unexplained := intent.Refunded
+	for _, txn := range transactions {
+		if txn.Kind == types.TransactionRefund && txn.Status == types.TransactionSucceeded &&
+			txn.PaymentID != nil && refund.PaymentID != nil && *txn.PaymentID == *refund.PaymentID {
+			unexplained = unexplained.Sub(txn.Amount)
+		}
+	}
+	if unexplained.Minor >= refund.Amount.Minor {
+		return types.TransactionSucceeded, ""
+	}
+	return types.TransactionFailed, "expired: the provider has no record of the refund"
+}
+
+// LockTransaction fetches a transaction and locks it until the end of tx, so a webhook and the
+// reconciler cannot settle it twice.
+func (t *PaymentTasks) LockTransaction(ctx context.Context, tx pgx.Tx, id string) (*types.Transaction, error) {
+	txn, err := scanTransaction(tx.QueryRow(ctx, `SELECT `+transactionColumns+` FROM payment.transactions WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		return nil, fmt.Errorf("lock transaction %s: %w", id, err)
+	}
+	return txn, nil
+}
+
+// SettleTransaction records the provider's outcome of a transaction.
+func (t *PaymentTasks) SettleTransaction(ctx context.Context, tx pgx.Tx, id string, providerRef *string, status types.TransactionStatus, reason string) (*types.Transaction, error) {
+	txn, err := scanTransaction(tx.QueryRow(ctx, `
+		UPDATE payment.transactions
+		SET status = $2, provider_ref = COALESCE($3, provider_ref), failure_reason = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+transactionColumns,
+		id, status, providerRef, reason,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("settle transaction %s: %w", id, err)
+	}
+	return txn, nil
+}
+
+// webhookSettles maps the events that settle a transaction to its kind and new status.
+var webhookSettles = map[types.WebhookEventType]struct {
+	kind   types.TransactionKind
+	status types.TransactionStatus
+}{
+	types.EventPaymentSucceeded: {types.TransactionPayment, types.TransactionSucceeded},
+	types.EventPaymentFailed:    {types.TransactionPayment, types.TransactionFailed},
+	types.EventRefundSucceeded:  {types.TransactionRefund, types.TransactionSucceeded},
+	types.EventRefundFailed:     {types.TransactionRefund, types.TransactionFailed},
+}
+
+// ApplyWebhookEvent records a provider event and settles its transaction, once per event id: a
+// redelivered event reports the first outcome as a duplicate. Events are recorded first, so a
+// concurrent delivery of the same event waits for this one. Only PENDING transactions are settled.
+// An event contradicting a settled transaction, such as a late success for a failed refund that
+// was since replaced, would move money past what is refundable or due, so it is recorded as a
+// discrepancy with its payload for review instead.
+func (t *PaymentTasks) ApplyWebhookEvent(ctx context.Context, tx pgx.Tx, provider string, event *types.WebhookEvent, payload []byte) (*types.WebhookResponse, error) {
+	resp := &types.WebhookResponse{EventID: event.ID}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO payment.webhook_events (provider, event_id, type, outcome, payload)
+		VALUES ($1, $2, $3, '', $4)
+		ON CONFLICT (provider, event_id) DO NOTHING
+	`, provider, event.ID, event.Type, payload)
+	if err != nil {
+		return nil, fmt.Errorf("record webhook event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		resp.Duplicate = true
+		if err := tx.QueryRow(ctx, `
+			SELECT outcome, transaction_id::text
+			FROM payment.webhook_events
+			WHERE provider = $1 AND event_id = $2
+		`, provider, event.ID).Scan(&resp.Outcome, &resp.TransactionID); err != nil {
+			return nil, fmt.Errorf("fetch webhook event: %w", err)
+		}
+		return resp, nil
+	}
+
+	resp.Outcome, err = t.settleFromWebhook(ctx, tx, provider, event, resp)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE payment.webhook_events
+		SET outcome = $3, transaction_id = $4
+		WHERE provider = $1 AND event_id = $2
+	`, provider, event.ID, resp.Outcome, resp.TransactionID); err != nil {
+		return nil, fmt.Errorf("record webhook outcome: %w", err)
+	}
+	return resp, nil
+}
+
+func (t *PaymentTasks) settleFromWebhook(ctx context.Context, tx pgx.Tx, provider string, event *types.WebhookEvent, resp *types.WebhookResponse) (types.WebhookOutcome, error) {
+	settles, ok := webhookSettles[event.Type]
+	if !ok {
+		return types.WebhookIgnored, nil
+	}
+	txn, err := scanTransaction(tx.QueryRow(ctx, `
+		SELECT `+transactionColumns+`
+		FROM payment.transactions
+		WHERE provider = $1 AND kind = $2 AND (provider_ref = $3 OR id::text = $4)
+		FOR UPDATE
+	`, provider, settles.kind, event.Data.ObjectID, event.Data.Reference))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return types.WebhookUnmatched, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("fetch webhook transaction: %w", err)
+	}
+	resp.TransactionID = &txn.ID
+
+	switch txn.Status {
+	case settles.status:
+		return types.WebhookUnchanged, nil
+	case types.TransactionPending:
+	default:
+		return types.WebhookDiscrepancy, nil
+	}
+	var ref *string
+	if event.Data.ObjectID != "" {
+		ref = &event.Data.ObjectID
+	}
+	if _, err := t.SettleTransaction(ctx, tx, txn.ID, ref, settles.status, event.Data.FailureReason); err != nil {
+		return "", err
+	}
+	if _, err := t.RefreshPaymentStatus(ctx, tx, txn.BookingID); err != nil {
+		return "", err
+	}
+	return types.WebhookApplied, nil
+}
@@ -0,0 +1,93 @@
+package tasks_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"handworks-api/tasks"
+	"handworks-api/types"
+	"handworks-api/utils"
+)
+
+func TestPaymentOutcomes(t *testing.T) {
+	ctx := context.Background()
+	amount := types.PHP(150000)
+
+	tests := []struct {
+		name   string
+		method string
+		// run takes a payment as far as it got at the provider and reports how it ends
+		run        func(t *testing.T, p *utils.FakePaymentProvider, intent *types.PaymentIntent) (types.TransactionStatus, string)
+		wantStatus types.TransactionStatus
+		wantReason string
+	}{
+		{
+			name:   "declined capture fails with the provider's reason",
+			method: utils.FakeDeclinedMethod,
+			run: func(t *testing.T, p *utils.FakePaymentProvider, intent *types.PaymentIntent) (types.TransactionStatus, string) {
+				if intent.Status == types.IntentRequiresCapture {
+					captured, err := p.Capture(ctx, intent.ID)
+					return tasks.CaptureOutcome(captured, err)
+				}
+				return tasks.CaptureOutcome(intent, nil)
+			},
+			wantStatus: types.TransactionFailed,
+			wantReason: "card declined",
+		},
+		{
+			name:   "stale intent never captured is expired",
+			method: "card",
+			run: func(t *testing.T, p *utils.FakePaymentProvider, intent *types.PaymentIntent) (types.TransactionStatus, string) {
+				stale, err := p.FetchStatus(ctx, intent.ID)
+				if err != nil {
+					t.Fatalf("fetch status: %v", err)
+				}
+				return tasks.StalePaymentOutcome(stale)
+			},
+			wantStatus: types.TransactionFailed,
+			wantReason: "expired: not captured",
+		},
+		{
+			name:   "stale intent captured at the provider is settled",
+			method: "card",
+			run: func(t *testing.T, p *utils.FakePaymentProvider, intent *types.PaymentIntent) (types.TransactionStatus, string) {
+				if _, err := p.Capture(ctx, intent.ID); err != nil {
+					t.Fatalf("capture: %v", err)
+				}
+				stale, err := p.FetchStatus(ctx, intent.ID)
+				if err != nil {
+					t.Fatalf("fetch status: %v", err)
+				}
+				return tasks.StalePaymentOutcome(stale)
+			},
+			wantStatus: types.TransactionSucceeded,
+		},
+		{
+			name:   "stale payment that never reached the provider is expired",
+			method: "card",
+			run: func(t *testing.T, p *utils.FakePaymentProvider, intent *types.PaymentIntent) (types.TransactionStatus, string) {
+				return tasks.StalePaymentOutcome(nil)
+			},
+			wantStatus: types.TransactionFailed,
+			wantReason: "expired: the provider was never reached",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := utils.NewFakePaymentProvider()
+			intent, err := p.CreateIntent(ctx, "txn-1", amount, tt.method)
+			if err != nil {
+				t.Fatalf("create intent: %v", err)
+			}
+			status, reason := tt.run(t, p, intent)
+			if status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", status, tt.wantStatus)
+			}
+			if !strings.HasPrefix(reason, tt.wantReason) || (tt.wantReason == "" && reason != "") {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -15,8 +15,10 @@ type Account struct {
 }
 
 type Customer struct {
-    ID      string  `json:"id"`
-    Account Account `json:"account"`
+    ID        string    `json:"id"`
+    Account   Account   `json:"account"`
+    TaxStatus TaxStatus `json:"tax_status"`
+    TaxID     string    `json:"tax_id"` // TIN, required for EXEMPT and ZERO_RATED
 }
 
 type Employee struct {
@@ -59,6 +61,13 @@ type UpdateCustomerRequest struct {
     CustomerID string `json:"customer_id"` 
 }
 
+// UpdateCustomerTaxRequest sets how VAT applies to a customer's quotes.
+type UpdateCustomerTaxRequest struct {
+    ID        string    `json:"-"`
+    TaxStatus TaxStatus `json:"tax_status" binding:"required,oneof=VATABLE EXEMPT ZERO_RATED"`
+    TaxID     string    `json:"tax_id"`
+}
+
 type UpdateEmployeeRequest struct {
     ID         string `json:"id"           binding:"required"`
     FirstName  string `json:"first_name"   binding:"omitempty"`
@@ -23,6 +23,8 @@ type CleaningResources struct {
 	Name     string `json:"name"`
 	Type     string `json:"type"`
 	PhotoURL string `json:"photoUrl"`
+	Quantity int32  `json:"quantity,omitempty"`
+	Unit     string `json:"unit,omitempty"`
 }
 
 type CleanerAssigned struct {
@@ -32,12 +34,28 @@ type CleanerAssigned struct {
 	PFPUrl           string `json:"pfpUrl"`
 }
 type AddonCleaningPrice struct {
-	AddonName  string  `json:"addonName"`
-	AddonPrice float32 `json:"addonPrice"`
+	AddonName  string `json:"addonName"`
+	AddonPrice Money  `json:"addonPrice"`
 }
+// CleaningPrices is what a booking's quote charges for it. AddonPrices follow the order of the
+// booking's addons.
 type CleaningPrices struct {
-	MainServicePrice float32               `json:"mainServicePrice"`
-	AddonPrices      []AddonCleaningPrice  `json:"addonPrices"`
+	MainServicePrice Money                `json:"mainServicePrice"`
+	AddonPrices      []AddonCleaningPrice `json:"addonPrices"`
+	Adjustments      []QuoteAdjustment    `json:"adjustments"` // surcharges, time window, travel and promo lines
+	Tax              TaxBreakdown         `json:"tax"`         // of Total
+}
+
+// Total is the main service plus the addons plus the adjustment lines, before tax.
+func (p *CleaningPrices) Total() Money {
+	total := p.MainServicePrice
+	for _, a := range p.AddonPrices {
+		total = total.Add(a.AddonPrice)
+	}
+	for _, a := range p.Adjustments {
+		total = total.Add(a.Amount)
+	}
+	return total
 }
 
 type ServiceDetail struct {
@@ -120,20 +138,22 @@ type PostConstructionDetails struct {
 	SQM int32 `json:"sqm"`
 }
 type BaseBookingDetails struct {
-	ID                string     `json:"id"`
-	CustID            string     `json:"custId"`
-	CustomerFirstName string     `json:"customerFirstName"`
-	CustomerLastName  string     `json:"customerLastName"`
-	Address           Address    `json:"address"`
-	StartSched        time.Time  `json:"startSched"`
-	EndSched          time.Time  `json:"endSched"`
-	DirtyScale        int32      `json:"dirtyScale"`
-	PaymentStatus     string     `json:"paymentStatus"`
-	ReviewStatus      string     `json:"reviewStatus"`
-	Photos            []string   `json:"photos"`
-	CreatedAt         time.Time  `json:"createdAt"`
-	UpdatedAt         *time.Time `json:"updatedAt,omitempty"`
-	QuoteId           string     `json:"quoteId"`
+	ID                string        `json:"id"`
+	CustID            string        `json:"custId"`
+	CustomerFirstName string        `json:"customerFirstName"`
+	CustomerLastName  string        `json:"customerLastName"`
+	Address           Address       `json:"address"`
+	StartSched        time.Time     `json:"startSched"`
+	EndSched          time.Time     `json:"endSched"`
+	DirtyScale        int32         `json:"dirtyScale"`
+	Modifiers         []string      `json:"modifiers"`
+	Status            BookingStatus `json:"status"`
+	PaymentStatus     PaymentStatus `json:"paymentStatus"`
+	ReviewStatus      string        `json:"reviewStatus"`
+	Photos            []string      `json:"photos"`
+	CreatedAt         time.Time     `json:"createdAt"`
+	UpdatedAt         *time.Time    `json:"updatedAt,omitempty"`
+	QuoteId           string        `json:"quoteId"`
 }
 type BaseBookingDetailsRequest struct {
 	ID                string     `json:"id"`
@@ -144,6 +164,7 @@ type BaseBookingDetailsRequest struct {
 	StartSched        time.Time  `json:"startSched"`
 	EndSched          time.Time  `json:"endSched"`
 	DirtyScale        int32      `json:"dirtyScale"`
+	Modifiers         []string   `json:"modifiers,omitempty"` // catalog codes the quote was priced with, e.g. PETS
 	Photos            []string   `json:"photos"`
 	CreatedAt         time.Time  `json:"createdAt"`
 	UpdatedAt         *time.Time `json:"updatedAt,omitempty"`
@@ -190,7 +211,7 @@ type CreateBookingRequest struct {
 type AddOns struct {
 	ID            string         `json:"id"`
 	ServiceDetail ServiceDetails `json:"serviceDetail"`
-	Price         float32        `json:"price"`
+	Price         Money          `json:"price"`
 }
 type Booking struct {
 	ID          string              `json:"id"`
@@ -200,5 +221,121 @@ type Booking struct {
 	Equipments  []CleaningEquipment `json:"equipments"`
 	Resources   []CleaningResources `json:"resources"`
 	Cleaners    []CleanerAssigned   `json:"cleaners"`
-	TotalPrice  float32             `json:"totalPrice"`
-}
\ No newline at end of file
+	TotalPrice  Money               `json:"totalPrice"` // gross
+	Tax         TaxBreakdown        `json:"tax"`
+}
+
+// BookingFilter narrows down a customer's bookings. Dates are YYYY-MM-DD and inclusive.
+type BookingFilter struct {
+	CustID        string `form:"-"`
+	StartDate     string `form:"startDate"`
+	EndDate       string `form:"endDate"`
+	PaymentStatus string `form:"paymentStatus"`
+	ReviewStatus  string `form:"reviewStatus"`
+	Page          int32  `form:"page"`
+	Limit         int32  `form:"limit"`
+}
+
+type BookingsResponse struct {
+	Bookings   []Booking `json:"bookings"`
+	TotalCount int32     `json:"totalCount"`
+	Page       int32     `json:"page"`
+	Limit      int32     `json:"limit"`
+}
+
+// UpdateBookingRequest carries a partial update; nil fields are left untouched.
+type UpdateBookingRequest struct {
+	ID         string          `json:"-"`
+	StartSched *time.Time      `json:"startSched,omitempty"`
+	EndSched   *time.Time      `json:"endSched,omitempty"`
+	Address    *Address        `json:"address,omitempty"`
+	DirtyScale *int32          `json:"dirtyScale,omitempty"`
+	Modifiers  *[]string       `json:"modifiers,omitempty"`
+	Photos     *[]string       `json:"photos,omitempty"`
+	Addons     *[]AddOnRequest `json:"addons,omitempty"`
+	QuoteId    *string         `json:"quoteId,omitempty"` // quote for the edited job; required when the price changes
+}
+
+type BookingChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type UpdateBookingResponse struct {
+	Booking     Booking         `json:"booking"`
+	Changes     []BookingChange `json:"changes"`
+	Reallocated bool            `json:"reallocated"`
+}
+
+// CancellationTier refunds RefundPercent of the total when a booking is cancelled at least MinHoursBefore its start.
+type CancellationTier struct {
+	MinHoursBefore float64 `json:"minHoursBefore"`
+	RefundPercent  int32   `json:"refundPercent"`
+}
+
+type CancellationPolicy struct {
+	Tiers []CancellationTier `json:"tiers"`
+}
+
+type CancelBookingRequest struct {
+	ID          string `json:"-"`
+	CancelledBy string `json:"-"`
+	Reason      string `json:"reason"`
+}
+
+type CancelBookingResponse struct {
+	BookingID        string        `json:"bookingId"`
+	Status           BookingStatus `json:"status"`
+	CancelledBy      string        `json:"cancelledBy"`
+	Reason           string        `json:"reason"`
+	RefundPercent    int32           `json:"refundPercent"`
+	RefundableAmount Money           `json:"refundableAmount"` // the policy's share of what was captured and not yet refunded
+	CancelledAt      time.Time       `json:"cancelledAt"`
+	Refund           *RefundResponse `json:"refund,omitempty"`      // the refund paid out, if any
+	RefundError      string          `json:"refundError,omitempty"` // why the refund could not be paid out; retry with the booking refund endpoint
+}
+
+// BookingStatus is the lifecycle state of a booking, stored in booking.basebookings.status.
+type BookingStatus string
+
+const (
+	BookingPending    BookingStatus = "PENDING"
+	BookingConfirmed  BookingStatus = "CONFIRMED"
+	BookingEnRoute    BookingStatus = "EN_ROUTE"
+	BookingInProgress BookingStatus = "IN_PROGRESS"
+	BookingCompleted  BookingStatus = "COMPLETED"
+	BookingCancelled  BookingStatus = "CANCELLED"
+	BookingNoShow     BookingStatus = "NO_SHOW"
+)
+
+// BookingTransitions lists the statuses each status may move to. Statuses without an entry are terminal.
+var BookingTransitions = map[BookingStatus][]BookingStatus{
+	BookingPending:    {BookingConfirmed, BookingCancelled},
+	BookingConfirmed:  {BookingEnRoute, BookingCancelled, BookingNoShow},
+	BookingEnRoute:    {BookingInProgress, BookingCancelled, BookingNoShow},
+	BookingInProgress: {BookingCompleted},
+}
+
+type TransitionBookingRequest struct {
+	ID     string        `json:"-"`
+	Actor  string        `json:"-"`
+	Status BookingStatus `json:"status" binding:"required"`
+	Note   string        `json:"note"`
+}
+
+type BookingStatusChange struct {
+	ID         string        `json:"id"`
+	BookingID  string        `json:"bookingId"`
+	FromStatus BookingStatus `json:"fromStatus"`
+	ToStatus   BookingStatus `json:"toStatus"`
+	Actor      string        `json:"actor"`
+	Note       string        `json:"note"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+type BookingStatusHistoryResponse struct {
+	BookingID string                `json:"bookingId"`
+	Status    BookingStatus         `json:"status"`
+	History   []BookingStatusChange `json:"history"`
+}
@@ -1,9 +1,150 @@
 package types
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBookingCancelled     = errors.New("booking is already cancelled")
+	ErrBookingNotEditable   = errors.New("booking can only be changed while PENDING or CONFIRMED")
+	ErrBookingNotFound      = errors.New("booking not found")
+	ErrInvalidBookingFilter = errors.New("invalid booking filter")
+	ErrInvalidBookingUpdate = errors.New("invalid booking update")
+
+	ErrInvalidStockMovement = errors.New("invalid stock movement")
+	ErrNegativeStock        = errors.New("stock cannot go below zero")
+
+	ErrInvalidInventoryFilter = errors.New("invalid inventory filter")
+	ErrItemNotFound           = errors.New("inventory item not found")
+
+	ErrQuoteNotFound = errors.New("quote not found")
+	ErrQuoteExpired  = errors.New("quote has expired")
+	ErrQuoteConsumed = errors.New("quote has already been used by a booking")
+	ErrQuoteSchedule = errors.New("quote was priced for a different time window than the booked slot")
+	ErrQuoteNotOwned = errors.New("quote belongs to another customer")
+	ErrQuoteRequired = errors.New("a new quote is required for changes that affect the price")
+
+	ErrCatalogNotFound = errors.New("price catalog not found")
+	ErrCatalogInEffect = errors.New("price catalog is already in effect and cannot be changed")
+	ErrInvalidCatalog  = errors.New("invalid price catalog")
+
+	ErrInvalidQuoteRequest = errors.New("invalid quote request")
+
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrInvalidPromotion  = errors.New("invalid promotion")
+	ErrPromoCodeRejected = errors.New("promo code cannot be applied")
+	ErrPromoExhausted    = errors.New("promo code has reached its usage limit")
+
+	ErrDepotNotFound      = errors.New("depot not found")
+	ErrInvalidDepot       = errors.New("invalid depot")
+	ErrOutsideServiceArea = errors.New("address is outside the service area")
+
+	ErrHolidayNotFound = errors.New("holiday not found")
+	ErrInvalidHoliday  = errors.New("invalid holiday")
+
+	ErrInvoiceNotFound    = errors.New("invoice not found")
+	ErrInvoiceNotVoidable = errors.New("only issued invoices can be voided")
+
+	ErrInvalidPayment  = errors.New("invalid payment")
+	ErrOverpayment     = errors.New("payment is more than the balance due")
+	ErrPaymentDeclined = errors.New("payment was declined")
+	ErrDepositRequired = errors.New("the deposit must be paid before the crew is dispatched")
+
+	ErrInvalidRefund         = errors.New("invalid refund")
+	ErrRefundExceedsCaptured = errors.New("refund is more than what was captured and not yet refunded")
+	ErrRefundFailed          = errors.New("refund failed at the payment provider")
+
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidWebhook   = errors.New("invalid webhook event")
+
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+	ErrTaxIDRequired = errors.New("a tax id is required for VAT exempt and zero-rated customers")
+)
+
+// IllegalTransitionError is returned when a booking status change is not allowed by BookingTransitions.
+type IllegalTransitionError struct {
+	From BookingStatus
+	To   BookingStatus
+}
+
+func (e *IllegalTransitionError) Error() string {
+	return fmt.Sprintf("cannot move booking from %s to %s", e.From, e.To)
+}
+
+// NoCapacityError is returned when not enough cleaners are free to staff a booking.
+type NoCapacityError struct {
+	Required   int
+	Available  int
+	StartSched time.Time
+	EndSched   time.Time
+}
+
+func (e *NoCapacityError) Error() string {
+	return fmt.Sprintf("no capacity: %d cleaners needed between %s and %s, only %d available",
+		e.Required, e.StartSched.Format(time.RFC3339), e.EndSched.Format(time.RFC3339), e.Available)
+}
+
+// InsufficientStockError names the inventory items a booking could not be allocated.
+type InsufficientStockError struct {
+	Missing []string
+}
+
+func (e *InsufficientStockError) Error() string {
+	return "insufficient stock: " + strings.Join(e.Missing, ", ")
+}
+
+// QuoteDiff items.
+const (
+	QuoteDiffMainService = "MAIN_SERVICE"
+	QuoteDiffAddon       = "ADDON"
+	QuoteDiffTotal       = "TOTAL"
+	QuoteDiffDirtyScale  = "DIRTY_SCALE"
+	QuoteDiffModifiers   = "MODIFIERS"
+	QuoteDiffAddress     = "ADDRESS"
+	QuoteDiffDepot       = "DEPOT"
+)
+
+// QuoteDiff is one way a booking differs from its quote. Submitted is the booking's item re-priced
+// with the quote's catalog. Job conditions that are not prices, such as the dirty scale or the
+// address, are reported in QuotedValue and SubmittedValue.
+type QuoteDiff struct {
+	Item           string `json:"item"` // MAIN_SERVICE, ADDON, TOTAL, DIRTY_SCALE, MODIFIERS, ADDRESS or DEPOT
+	ServiceType    string `json:"serviceType,omitempty"`
+	Quoted         *Money `json:"quoted,omitempty"`    // nil when the item was not quoted
+	Submitted      *Money `json:"submitted,omitempty"` // nil when the item was not submitted
+	QuotedValue    any    `json:"quotedValue,omitempty"`
+	SubmittedValue any    `json:"submittedValue,omitempty"`
+	Reason         string `json:"reason"`
+}
+
+// QuoteMismatchError is returned when the services of a booking do not match those of its quote.
+type QuoteMismatchError struct {
+	QuoteID string
+	Diff    []QuoteDiff
+}
+
+func (e *QuoteMismatchError) Error() string {
+	reasons := make([]string, len(e.Diff))
+	for i, d := range e.Diff {
+		reasons[i] = strings.TrimSpace(d.Item + " " + d.ServiceType + ": " + d.Reason)
+	}
+	return fmt.Sprintf("booking does not match quote %s: %s", e.QuoteID, strings.Join(reasons, "; "))
+}
+
 type ErrorResponse struct {
-    Error string `json:"error"`
+	Error string      `json:"error"`
+	Diff  []QuoteDiff `json:"diff,omitempty"` // how a booking differs from its quote
 }
 
 func NewErrorResponse(err error) ErrorResponse {
-    return ErrorResponse{Error: err.Error()}
+	resp := ErrorResponse{Error: err.Error()}
+	var mismatch *QuoteMismatchError
+	if errors.As(err, &mismatch) {
+		resp.Diff = mismatch.Diff
+	}
+	return resp
 }
@@ -29,48 +29,240 @@ const (
 
 
 
+type AlertLevel string
+
+const (
+	AlertLevelReorder    AlertLevel = "REORDER"
+	AlertLevelOutOfStock AlertLevel = "OUT_OF_STOCK"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationReserved ReservationStatus = "RESERVED"
+	ReservationConsumed ReservationStatus = "CONSUMED"
+	ReservationReleased ReservationStatus = "RELEASED"
+)
+
 // Db / Resonse Model
 type InventoryItem struct {
-	ID          string        `json:"id" db:"id"`
-	Name        string        `json:"name" db:"name"`
-	Type        ItemType      `json:"type" db:"type"`
-	Status      ItemStatus    `json:"status" db:"status"`
-	Category    ItemCategory  `json:"category" db:"category"`
-	Quantity    int32         `json:"quantity" db:"quantity"`
-	MaxQuantity int32         `json:"max_quantity" db:"max_quantity"`
-	Unit        string        `json:"unit" db:"unit"`
-	IsAvailable bool          `json:"is_available" db:"is_available"`
-	ImageURL    string        `json:"image_url" db:"image_url"`
-	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+	ID           string       `json:"id" db:"id"`
+	Name         string       `json:"name" db:"name"`
+	Type         ItemType     `json:"type" db:"type"`
+	Status       ItemStatus   `json:"status" db:"status"`
+	Category     ItemCategory `json:"category" db:"category"`
+	Quantity     int32        `json:"quantity" db:"quantity"`
+	MaxQuantity  int32        `json:"max_quantity" db:"max_quantity"`
+	// ReorderPoint is the stock level that triggers a reorder; nil uses half of MaxQuantity.
+	ReorderPoint *int32       `json:"reorder_point" db:"reorder_point"`
+	Unit         string       `json:"unit" db:"unit"`
+	IsAvailable  bool         `json:"is_available" db:"is_available"`
+	ImageURL     string       `json:"image_url" db:"image_url"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+
+type MovementType string
+
+const (
+	MovementRestock MovementType = "RESTOCK"
+	MovementConsume MovementType = "CONSUME"
+	MovementAdjust  MovementType = "ADJUST"
+	MovementDamage  MovementType = "DAMAGE"
+	MovementReturn  MovementType = "RETURN"
+	MovementReserve MovementType = "RESERVE"
+)
+
+// StockMovement is one inventory.stock_movements row. Quantity is the signed change to stock.
+type StockMovement struct {
+	ID          string       `json:"id"`
+	ItemID      string       `json:"item_id"`
+	Type        MovementType `json:"type"`
+	Quantity    int32        `json:"quantity"`
+	Reason      string       `json:"reason"`
+	ReferenceID *string      `json:"reference_id,omitempty"`
+	Actor       string       `json:"actor"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// Quantity is a positive amount for RESTOCK, CONSUME, DAMAGE and RETURN, and signed for ADJUST.
+type CreateStockMovementRequest struct {
+	ItemID   string       `json:"-"`
+	Actor    string       `json:"-"`
+	Type     MovementType `json:"type" binding:"required"`
+	Quantity int32        `json:"quantity" binding:"required"`
+	Reason   string       `json:"reason"`
+}
+
+type StockMovementResponse struct {
+	Movement StockMovement `json:"movement"`
+	Item     InventoryItem `json:"item"`
+}
+
+// LedgerQuantity is what the movements add up to and should always equal Quantity.
+type StockMovementsResponse struct {
+	ItemID         string          `json:"item_id"`
+	Quantity       int32           `json:"quantity"`
+	LedgerQuantity int32           `json:"ledger_quantity"`
+	Reserved       int32           `json:"reserved"`
+	Movements      []StockMovement `json:"movements"`
 }
 
+type StockReservation struct {
+	ID        string            `json:"id"`
+	ItemID    string            `json:"item_id"`
+	BookingID string            `json:"booking_id"`
+	Quantity  int32             `json:"quantity"`
+	Status    ReservationStatus `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
 
 type CreateItemRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Type     string `json:"type" binding:"required"`     // RESOURCE / EQUIPMENT
-	Category string `json:"category" binding:"required"` // GENERAL / ELECTRONICS / ...
-	Quantity int32  `json:"quantity" binding:"required"`
-	Unit     string `json:"unit" binding:"required"`
-	ImageURL string `json:"image_url"`
+	Name         string `json:"name" binding:"required"`
+	Type         string `json:"type" binding:"required"`     // RESOURCE / EQUIPMENT
+	Category     string `json:"category" binding:"required"` // GENERAL / ELECTRONICS / ...
+	Quantity     int32  `json:"quantity" binding:"required"`
+	Unit         string `json:"unit" binding:"required"`
+	ImageURL     string `json:"image_url"`
+	// optional, defaults to half of the initial quantity
+	ReorderPoint *int32 `json:"reorder_point" binding:"omitempty,min=0"`
 }
 
+// Status is not settable; it is recomputed from Quantity / MaxQuantity.
 type UpdateItemRequest struct {
-	ID 			string `json:"id" binding:"required"`
-	Name        string `json:"name"`
-	Type        string `json:"type"`        // optional
-	Status      string `json:"status"`      // HIGH / LOW / DANGER / OUT_OF_STOCK
-	Category    string `json:"category"`    // optional
-	Quantity    int32  `json:"quantity"`
-	MaxQuantity int32  `json:"max_quantity"`
-	Unit        string `json:"unit"`
-	ImageURL    string `json:"image_url"`
+	ID           string `json:"id" binding:"required"`
+	Actor        string `json:"-"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`        // optional
+	Category     string `json:"category"`    // optional
+	Quantity     *int32 `json:"quantity"`    // optional, a physical count recorded as an ADJUST movement
+	MaxQuantity  int32  `json:"max_quantity"`
+	ReorderPoint *int32 `json:"reorder_point" binding:"omitempty,min=0"` // optional
+	Unit         string `json:"unit"`
+	ImageURL     string `json:"image_url"`
 }
 
 
 
+// InventoryFilter narrows down GET /inventory. Every set field must match. Type, Status and Category
+// take comma separated values, Name matches a case-insensitive substring, and Sort is a column name,
+// prefixed with "-" for descending order.
 type InventoryFilter struct {
-	Type     *string `json:"type,omitempty" form:"type"`
-	Status   *string `json:"status,omitempty" form:"status"`
-	Category *string `json:"category,omitempty" form:"category"`
+	Type        string `json:"type,omitempty" form:"type"`
+	Status      string `json:"status,omitempty" form:"status"`
+	Category    string `json:"category,omitempty" form:"category"`
+	Available   *bool  `json:"available,omitempty" form:"available"`
+	Name        string `json:"name,omitempty" form:"name"`
+	MinQuantity *int32 `json:"quantity_min,omitempty" form:"quantity_min"`
+	MaxQuantity *int32 `json:"quantity_max,omitempty" form:"quantity_max"`
+	Sort        string `json:"sort,omitempty" form:"sort"`
+	Cursor      string `json:"cursor,omitempty" form:"cursor"`
+	Limit       int32  `json:"limit,omitempty" form:"limit"`
+}
+
+type InventoryItemsResponse struct {
+	Items      []*InventoryItem `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	Limit      int32            `json:"limit"`
+}
+
+// ConsumptionBasis is what a resource amount in ResourceConsumptionTable is multiplied by.
+type ConsumptionBasis string
+
+const (
+	PerJob       ConsumptionBasis = "JOB"
+	PerSQM       ConsumptionBasis = "SQM"
+	PerCouchSeat ConsumptionBasis = "COUCH_SEAT"
+	PerMattress  ConsumptionBasis = "MATTRESS"
+	PerCar       ConsumptionBasis = "CAR"
+)
+
+// ResourceConsumption is how much of a RESOURCE item (in the item's unit) a service uses per basis unit.
+type ResourceConsumption struct {
+	ItemName string
+	Basis    ConsumptionBasis
+	Amount   float64
+}
+
+// Items are matched against inventory.items by name, case-insensitively.
+var ResourceConsumptionTable = map[MainServiceType][]ResourceConsumption{
+	GeneralCleaning: {
+		{ItemName: "Detergent", Basis: PerSQM, Amount: 5},
+		{ItemName: "Disinfectant", Basis: PerSQM, Amount: 2},
+		{ItemName: "Trash Bag", Basis: PerJob, Amount: 3},
+	},
+	PostCleaning: {
+		{ItemName: "Detergent", Basis: PerSQM, Amount: 8},
+		{ItemName: "Trash Bag", Basis: PerSQM, Amount: 0.2},
+	},
+	CouchCleaning: {
+		{ItemName: "Upholstery Shampoo", Basis: PerCouchSeat, Amount: 50},
+	},
+	MattressCleaning: {
+		{ItemName: "Upholstery Shampoo", Basis: PerMattress, Amount: 80},
+		{ItemName: "Disinfectant", Basis: PerMattress, Amount: 20},
+	},
+	CarCleaning: {
+		{ItemName: "Car Shampoo", Basis: PerCar, Amount: 100},
+		{ItemName: "Upholstery Shampoo", Basis: PerCar, Amount: 50},
+	},
+}
+
+// EquipmentRequirements lists the EQUIPMENT items a crew brings for each service type.
+var EquipmentRequirements = map[MainServiceType][]string{
+	GeneralCleaning:  {"Vacuum Cleaner", "Mop"},
+	PostCleaning:     {"Vacuum Cleaner", "Mop", "Floor Scrubber"},
+	CouchCleaning:    {"Upholstery Extractor"},
+	MattressCleaning: {"Upholstery Extractor", "Vacuum Cleaner"},
+	CarCleaning:      {"Vacuum Cleaner", "Pressure Washer"},
+}
+
+// ReorderAlert is raised when an item's available stock falls to its reorder point or runs out.
+type ReorderAlert struct {
+	ItemID       string     `json:"item_id"`
+	ItemName     string     `json:"item_name"`
+	Unit         string     `json:"unit"`
+	Level        AlertLevel `json:"level"`
+	Available    int32      `json:"available"`
+	ReorderPoint int32      `json:"reorder_point"`
+	MaxQuantity  int32      `json:"max_quantity"`
+	RaisedAt     time.Time  `json:"raised_at"`
+}
+
+// StockLevel is an item's stock with its effective reorder point, what is reserved for bookings
+// and what was consumed over a recent window.
+type StockLevel struct {
+	ItemID       string
+	Name         string
+	Unit         string
+	Status       ItemStatus
+	Quantity     int32
+	MaxQuantity  int32
+	ReorderPoint int32
+	Reserved     int32
+	Consumed     int32
+}
+
+type ReorderSuggestion struct {
+	ItemID            string     `json:"item_id"`
+	Name              string     `json:"name"`
+	Unit              string     `json:"unit"`
+	Status            ItemStatus `json:"status"`
+	Quantity          int32      `json:"quantity"`
+	Reserved          int32      `json:"reserved"`
+	Available         int32      `json:"available"`
+	ReorderPoint      int32      `json:"reorder_point"`
+	MaxQuantity       int32      `json:"max_quantity"`
+	Consumed          int32      `json:"consumed"`
+	DailyUsage        float64    `json:"daily_usage"`
+	DaysOfStock       *float64   `json:"days_of_stock,omitempty"`
+	SuggestedQuantity int32      `json:"suggested_quantity"`
+}
+
+type ReorderSuggestionsResponse struct {
+	WindowDays  int                 `json:"window_days"`
+	GeneratedAt time.Time           `json:"generated_at"`
+	Suggestions []ReorderSuggestion `json:"suggestions"`
 }
@@ -0,0 +1,281 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency every amount is charged in unless stated otherwise.
+const DefaultCurrency = "PHP"
+
+// currencyExponents is the number of minor unit digits per currency.
+var currencyExponents = map[string]int{
+	"PHP": 2,
+}
+
+// Money is an exact amount in minor units (centavos for PHP) of a currency.
+// Amounts are stored in the database as BIGINT minor units and travel as JSON decimal strings,
+// see MoneyJSON. The database does not record the currency, so only DefaultCurrency is accepted:
+// parsing, validation and Value reject any other with ErrUnsupportedCurrency. Mixing currencies in
+// arithmetic is then a programming error and panics.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// MoneyJSON is the wire form of Money, e.g. {"amount": "1250.50", "currency": "PHP"}.
+type MoneyJSON struct {
+	Amount   string `json:"amount" example:"1250.50"`
+	Currency string `json:"currency" example:"PHP"`
+}
+
+// PHP returns an amount of Philippine pesos from centavos.
+func PHP(centavos int64) Money {
+	return Money{Minor: centavos, Currency: "PHP"}
+}
+
+// ParseMoney reads a decimal amount such as "1250.5" in the given currency, refusing more
+// decimal places than the currency has instead of rounding.
+func ParseMoney(amount, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if err := CheckCurrency(currency); err != nil {
+		return Money{}, err
+	}
+	exp := exponent(currency)
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	if len(frac) > exp {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimal places", amount, exp)
+	}
+	digits := whole + frac + strings.Repeat("0", exp-len(frac))
+	if strings.Trim(digits, "0123456789") != "" {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// CheckCurrency fails with ErrUnsupportedCurrency for any currency but DefaultCurrency.
+func CheckCurrency(currency string) error {
+	if _, ok := currencyExponents[currency]; !ok || currency != DefaultCurrency {
+		return fmt.Errorf("%w: %q, amounts are in %s", ErrUnsupportedCurrency, currency, DefaultCurrency)
+	}
+	return nil
+}
+
+// CheckCurrencies fails with ErrUnsupportedCurrency if any amount is not in DefaultCurrency. The
+// zero value, with no currency, is DefaultCurrency.
+func CheckCurrencies(amounts ...Money) error {
+	for _, m := range amounts {
+		if err := CheckCurrency(m.currency()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exponent(currency string) int {
+	if exp, ok := currencyExponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// combine returns the currency shared by both amounts; a zero value Money adopts the other's currency.
+func (m Money) combine(o Money) string {
+	switch {
+	case m.Currency == "":
+		return o.Currency
+	case o.Currency == "" || o.Currency == m.Currency:
+		return m.Currency
+	default:
+		panic(fmt.Sprintf("types: cannot combine %s and %s amounts", m.Currency, o.Currency))
+	}
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{Minor: m.Minor + o.Minor, Currency: m.combine(o)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{Minor: m.Minor - o.Minor, Currency: m.combine(o)}
+}
+
+// Mul multiplies by a whole quantity, e.g. a unit price by the number of units.
+func (m Money) Mul(n int64) Money {
+	return Money{Minor: m.Minor * n, Currency: m.Currency}
+}
+
+// Percent returns p percent of the amount, rounded half away from zero to the nearest minor unit.
+func (m Money) Percent(p int64) Money {
+	return m.Ratio(p, 100)
+}
+
+// Ratio returns num/den of the amount, rounded half away from zero to the nearest minor unit.
+func (m Money) Ratio(num, den int64) Money {
+	product := m.Minor * num
+	q, r := product/den, product%den
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= abs(den) {
+		if (product < 0) != (den < 0) {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Money{Minor: q, Currency: m.Currency}
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Equal reports whether both are the same amount, an empty currency being DefaultCurrency.
+func (m Money) Equal(o Money) bool { return m.Minor == o.Minor && m.currency() == o.currency() }
+
+func (m Money) IsZero() bool     { return m.Minor == 0 }
+func (m Money) IsNegative() bool { return m.Minor < 0 }
+
+// String formats the amount as a plain decimal, e.g. "1250.50".
+func (m Money) String() string {
+	exp := exponent(m.currency())
+	minor := m.Minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	s := strconv.FormatInt(minor, 10)
+	if exp == 0 {
+		return sign + s
+	}
+	if len(s) <= exp {
+		s = strings.Repeat("0", exp-len(s)+1) + s
+	}
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(MoneyJSON{Amount: m.String(), Currency: m.currency()})
+}
+
+// UnmarshalJSON accepts the MoneyJSON object, with the amount as a string or a number, or a bare
+// string or number in DefaultCurrency. Numbers are read from their text, never through a float.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '{' {
+		var obj struct {
+			Amount   json.RawMessage `json:"amount"`
+			Currency string          `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		amount, err := amountText(obj.Amount)
+		if err != nil {
+			return err
+		}
+		parsed, err := ParseMoney(amount, obj.Currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+	amount, err := amountText(data)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(amount, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// amountText returns the decimal text of a JSON string or number.
+func amountText(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 {
+		return "", fmt.Errorf("missing amount")
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return "", fmt.Errorf("invalid amount %s", raw)
+	}
+	if strings.ContainsAny(n.String(), "eE") {
+		return "", fmt.Errorf("amount %s must be a plain decimal", n)
+	}
+	return n.String(), nil
+}
+
+// Value stores the amount as BIGINT minor units, refusing amounts whose currency would be lost.
+func (m Money) Value() (driver.Value, error) {
+	if err := CheckCurrencies(m); err != nil {
+		return nil, err
+	}
+	return m.Minor, nil
+}
+
+// Scan reads BIGINT minor units in DefaultCurrency.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = Money{Currency: DefaultCurrency}
+	case int64:
+		*m = Money{Minor: v, Currency: DefaultCurrency}
+	case int32:
+		*m = Money{Minor: int64(v), Currency: DefaultCurrency}
+	case []byte:
+		return m.Scan(string(v))
+	case string:
+		minor, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("cannot scan %q into Money: %w", v, err)
+		}
+		*m = Money{Minor: minor, Currency: DefaultCurrency}
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	return nil
+}
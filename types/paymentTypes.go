@@ -6,54 +6,77 @@ import (
 )
 
 type Quote struct {
-	ID          string        `json:"id"`
-	CustomerID  string        `json:"customerId"`
-	MainService string        `json:"mainService"`
-	Subtotal    float32       `json:"subtotal"`
-	AddonTotal  float32       `json:"addonTotal"`
-	TotalPrice  float32       `json:"totalPrice"`
-	IsValid     bool          `json:"isValid"`
-	CreatedAt   time.Time     `json:"createdAt"`
-	UpdatedAt   time.Time     `json:"updatedAt"`
-	Addons      []*QuoteAddon `json:"addons"`
+	ID              string            `json:"id"`
+	CustomerID      string            `json:"customerId"`
+	MainService     string            `json:"mainService"`
+	Subtotal        Money             `json:"subtotal"`
+	AddonTotal      Money             `json:"addonTotal"`
+	AdjustmentTotal Money             `json:"adjustmentTotal"`
+	TotalPrice      Money             `json:"totalPrice"` // gross
+	Tax             TaxBreakdown      `json:"tax"`
+	IsValid         bool              `json:"isValid"`
+	PromotionID     *string           `json:"promotionId,omitempty"`
+	DirtyScale      int32             `json:"dirtyScale"`
+	Modifiers       []string          `json:"modifiers"`
+	Address         *Address          `json:"address,omitempty"`
+	Travel          *TravelQuote      `json:"travel,omitempty"`
+	StartSched      *time.Time        `json:"startSched,omitempty"`
+	ExpiresAt       *time.Time        `json:"expiresAt,omitempty"`
+	CatalogID       string            `json:"catalogId"`
+	CatalogVersion  int32             `json:"catalogVersion"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+	Addons          []*QuoteAddon     `json:"addons"`
+	Adjustments     []QuoteAdjustment `json:"adjustments"`
 }
 
 type QuoteAddon struct {
-	ID            string 		`json:"id"`
+	ID            string          `json:"id"`
 	QuoteID       string          `json:"quoteId"`
 	ServiceType   string          `json:"serviceType"`
 	ServiceDetail json.RawMessage `json:"serviceDetail"` // serialized ServicesRequest
-	AddonPrice    float32         `json:"addonPrice"`
+	AddonPrice    Money           `json:"addonPrice"`
 	CreatedAt     time.Time       `json:"createdAt"`
 }
 
 type QuoteAddonCleaningPrice struct {
-	AddonName  string  `json:"addon_name"`
-	AddonPrice float32 `json:"addon_price"`
+	AddonName  string `json:"addon_name"`
+	AddonPrice Money  `json:"addon_price"`
 }
 type QuoteCleaningPrices struct {
-	MainServicePrice float32                `json:"mainServicePrice"`
-	AddonPrices      []AddonCleaningPrice   `json:"addonPrices"`
+	MainServicePrice Money                `json:"mainServicePrice"`
+	AddonPrices      []AddonCleaningPrice `json:"addonPrices"`
 }
 type QuoteResponse struct {
-	QuoteId string `json:"quote_id"`
-	MainServiceName string `json:"mainServiceName"`
-	MainServiceTotal float32 `json:"mainServiceTotal"`
-	Addons []AddOnBreakdown `json:"addons"`
-	AddonTotal float32 `json:"addonTotal"`
-	TotalPrice float32 `json:"totalPrice"`
+	QuoteId          string            `json:"quote_id"`
+	MainServiceName  string            `json:"mainServiceName"`
+	MainServiceTotal Money             `json:"mainServiceTotal"`
+	Addons           []AddOnBreakdown  `json:"addons"`
+	AddonTotal       Money             `json:"addonTotal"`
+	Adjustments      []QuoteAdjustment `json:"adjustments"` // e.g. the promo discount line
+	AdjustmentTotal  Money             `json:"adjustmentTotal"`
+	TotalPrice       Money             `json:"totalPrice"` // gross, what the customer pays
+	Tax              TaxBreakdown      `json:"tax"`
+	ExpiresAt        *time.Time        `json:"expiresAt,omitempty"` // unset for previews
+	CatalogVersion   int32             `json:"catalogVersion"`
+	Travel           *TravelQuote      `json:"travel,omitempty"` // unset when no depots are set up
 }
 // QuoteRequest represents the data needed to build a quotation.
 type QuoteRequest struct {
     CustomerID string            `json:"customerId" db:"customer_id"`
     Service    ServicesRequest   `json:"service"`               // nested structs usually don't need db tags
     Addons     []AddOnRequest    `json:"addons"`                // same here
+    PromoCode  string            `json:"promoCode,omitempty"`   // optional, case-insensitive
+    DirtyScale int32             `json:"dirtyScale"`
+    Modifiers  []string          `json:"modifiers,omitempty"`   // codes from the catalog, e.g. PETS, STAIRS_NO_LIFT
+    Address    *Address          `json:"address,omitempty"`     // required once depots are set up
+    StartSched *time.Time        `json:"startSched,omitempty"`  // desired slot; prices time rules, and the booking must match them
 }
 
 type AddOnBreakdown struct {
-    AddonID   string   `json:"addonId" db:"addon_id"`
-    AddonName string   `json:"addonName" db:"addon_name"`
-    Price     float64  `json:"price" db:"price"`
+	AddonID   string `json:"addonId" db:"addon_id"`
+	AddonName string `json:"addonName" db:"addon_name"`
+	Price     Money  `json:"price" db:"price"`
 }
 
 // CustomerRequest fetches all quotes belonging to a customer.
@@ -61,39 +84,536 @@ type CustomerRequest struct {
     CustomerID string `json:"customerId" db:"customer_id"`
 }
 
-// QuotesResponse holds a list of quotations for a customer.
+// QuotesFilter pages through a customer's quotes.
+type QuotesFilter struct {
+	CustomerID string `form:"-"`
+	Page       int32  `form:"page"`
+	Limit      int32  `form:"limit"`
+}
+
+// QuoteAddonDetail is a quote addon with its stored service request decoded.
+type QuoteAddonDetail struct {
+	ID            string          `json:"id"`
+	ServiceType   string          `json:"serviceType"`
+	ServiceDetail ServicesRequest `json:"serviceDetail"`
+	Price         Money           `json:"price"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// QuoteDetail is a stored quote with everything needed to pre-fill a booking from it.
+// MainServiceDetail is nil for quotes made before it was recorded.
+type QuoteDetail struct {
+	ID                string             `json:"id"`
+	CustomerID        string             `json:"customerId"`
+	MainService       string             `json:"mainService"`
+	MainServiceDetail *ServicesRequest   `json:"mainServiceDetail,omitempty"`
+	DirtyScale        int32              `json:"dirtyScale"`
+	Modifiers         []string           `json:"modifiers"`
+	Address           *Address           `json:"address,omitempty"`
+	Travel            *TravelQuote       `json:"travel,omitempty"`
+	StartSched        *time.Time         `json:"startSched,omitempty"`
+	Subtotal          Money              `json:"subtotal"`
+	AddonTotal        Money              `json:"addonTotal"`
+	AdjustmentTotal   Money              `json:"adjustmentTotal"`
+	TotalPrice        Money              `json:"totalPrice"`
+	Tax               TaxBreakdown       `json:"tax"`
+	IsValid           bool               `json:"isValid"`
+	ExpiresAt         time.Time          `json:"expiresAt"`
+	ConsumedAt        *time.Time         `json:"consumedAt,omitempty"`
+	ConsumedBy        *string            `json:"consumedBy,omitempty"`     // base booking id
+	CatalogVersion    *int32             `json:"catalogVersion,omitempty"` // nil for quotes priced before catalogs
+	CreatedAt         time.Time          `json:"createdAt"`
+	UpdatedAt         time.Time          `json:"updatedAt"`
+	Addons            []QuoteAddonDetail `json:"addons"`
+	Adjustments       []QuoteAdjustment  `json:"adjustments"`
+}
+
+// QuotesResponse holds a page of a customer's quotations, newest first.
 type QuotesResponse struct {
-    Quotes []QuoteResponse `json:"quotes"`
-}
-
-var MattressPrices = map[string]float32{
-	"KING":           2000.00,
-	"KING_HEADBAND":  2500.00,
-	"QUEEN":          1800.00,
-	"QUEEN_HEADBAND": 2300.00,
-	"DOUBLE":         1500.00,
-	"SINGLE":         1000.00,
-}
-var CarPrices = map[string]float32{
-	"SEDAN":     3250.00,
-	"MPV":       4000.00,
-	"SUV":       4000.00,
-	"VAN":       5200.00,
-	"PICKUP":    3600.00,
-	"CAR_SMALL": 1750.00,
-}
-
-var CouchPrices = map[string]float32{
-	"SEATER_1":             500.00,
-	"SEATER_2":             1000.00,
-	"SEATER_3":             1300.00,
-	"SEATER_3_LTYPE_SMALL": 1500.00,
-	"SEATER_3_LTYPE_LARGE": 1750.00,
-	"SEATER_4_LTYPE_SMALL": 1800.00,
-	"SEATER_4_LTYPE_LARGE": 2000.00,
-	"SEATER_5_LTYPE":       2250.00,
-	"SEATER_6_LTYPE":       2500.00,
-	"OTTOMAN":              500.00,
-	"LAZBOY":               900.00,
-	"CHAIR":                250.00,
-}
\ No newline at end of file
+	Quotes     []QuoteDetail `json:"quotes"`
+	TotalCount int32         `json:"totalCount"`
+	Page       int32         `json:"page"`
+	Limit      int32         `json:"limit"`
+}
+
+// GeneralCleaningTier is a flat general cleaning price for a home type or a SQM band (MinSQM, MaxSQM].
+type GeneralCleaningTier struct {
+	HomeType string `json:"homeType,omitempty"`
+	MinSQM   int32  `json:"minSqm"`
+	MaxSQM   int32  `json:"maxSqm"`
+	Price    Money  `json:"price"`
+}
+
+// PriceRates are the prices of one catalog version. Tiers are tried in order; homes matching none
+// are charged GeneralPerSQM.
+type PriceRates struct {
+	GeneralTiers           []GeneralCleaningTier    `json:"generalTiers"`
+	GeneralPerSQM          Money                    `json:"generalPerSqm"`
+	PostConstructionPerSQM Money                    `json:"postConstructionPerSqm"`
+	Mattress               map[string]Money         `json:"mattress"` // by bed type
+	Car                    map[string]Money         `json:"car"`      // by car type
+	Couch                  map[string]Money         `json:"couch"`    // by couch type
+	ChildSeat              Money                    `json:"childSeat"`
+	BedPillow              Money                    `json:"bedPillow"`
+	DirtyScaleSurcharges   []DirtyScaleSurcharge    `json:"dirtyScaleSurcharges,omitempty"`
+	Modifiers              map[string]PriceModifier `json:"modifiers,omitempty"` // by modifier code, e.g. PETS
+	TimeRules              []TimePricingRule        `json:"timeRules,omitempty"`
+}
+
+// TimePricingRule adjusts jobs starting on one of Days between StartHour and EndHour local time (end
+// exclusive, wrapping past midnight if StartHour > EndHour; 0 and 0 mean all day). Days are weekday
+// names such as SUNDAY, or HOLIDAY: on a holiday the day is HOLIDAY instead of its weekday. Empty Days
+// matches every day. Percent is of the price of the services; Percent and Flat are negative for discounts.
+type TimePricingRule struct {
+	Code      string   `json:"code"`
+	Label     string   `json:"label"`
+	Days      []string `json:"days,omitempty"`
+	StartHour int32    `json:"startHour"`
+	EndHour   int32    `json:"endHour"`
+	Percent   int32    `json:"percent"`
+	Flat      Money    `json:"flat"`
+}
+
+// Holiday is a date in the holiday calendar, YYYY-MM-DD.
+type Holiday struct {
+	Date      string    `json:"date" example:"2026-12-25"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type HolidayRequest struct {
+	Date string `json:"date" binding:"required" example:"2026-12-25"`
+	Name string `json:"name" binding:"required"`
+}
+
+// DirtyScaleSurcharge is charged on jobs at or above MinScale; the highest matching tier applies.
+// Percent is of the price of the services, Flat is added on top.
+type DirtyScaleSurcharge struct {
+	MinScale int32 `json:"minScale"`
+	Percent  int32 `json:"percent"`
+	Flat     Money `json:"flat"`
+}
+
+// PriceModifier is an optional job condition, such as pets or stairs without a lift, priced like a
+// DirtyScaleSurcharge.
+type PriceModifier struct {
+	Label   string `json:"label"`
+	Percent int32  `json:"percent"`
+	Flat    Money  `json:"flat"`
+}
+
+// PriceCatalog is one version of the price list. It applies from EffectiveFrom until a newer version takes effect.
+type PriceCatalog struct {
+	ID            string     `json:"id"`
+	Version       int32      `json:"version"`
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	Notes         string     `json:"notes"`
+	Rates         PriceRates `json:"rates"`
+	CreatedBy     string     `json:"createdBy"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// PriceCatalogRequest creates or replaces a catalog version. EffectiveFrom defaults to now.
+type PriceCatalogRequest struct {
+	ID            string     `json:"-"`
+	Actor         string     `json:"-"`
+	EffectiveFrom *time.Time `json:"effectiveFrom"`
+	Notes         string     `json:"notes"`
+	Rates         PriceRates `json:"rates" binding:"required"`
+}
+
+// AdjustmentKind is the kind of a priced quote line that is not a service.
+type AdjustmentKind string
+
+const (
+	AdjustmentSurcharge  AdjustmentKind = "SURCHARGE"
+	AdjustmentTimeWindow AdjustmentKind = "TIME_WINDOW"
+	AdjustmentTravelFee  AdjustmentKind = "TRAVEL_FEE"
+	AdjustmentDiscount   AdjustmentKind = "DISCOUNT"
+)
+
+// QuoteAdjustment is a priced line of a quote on top of its services. Amount is negative for discounts.
+type QuoteAdjustment struct {
+	Kind   AdjustmentKind `json:"kind"`
+	Code   string         `json:"code,omitempty"` // e.g. the promo code
+	Label  string         `json:"label"`
+	Amount Money          `json:"amount"`
+}
+
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "PERCENT"
+	DiscountFixed   DiscountType = "FIXED"
+)
+
+// Promotion is a promo code. It applies to quotes made between ValidFrom and ValidUntil whose services
+// cost at least MinSpend; with ServiceTypes set only those services are discounted. UsageLimit and
+// PerCustomerLimit count redemptions, i.e. bookings made from discounted quotes.
+type Promotion struct {
+	ID               string       `json:"id"`
+	Code             string       `json:"code"`
+	Description      string       `json:"description"`
+	DiscountType     DiscountType `json:"discountType"`
+	PercentOff       *int32       `json:"percentOff,omitempty"`
+	AmountOff        *Money       `json:"amountOff,omitempty"`
+	MinSpend         Money        `json:"minSpend"`
+	ValidFrom        time.Time    `json:"validFrom"`
+	ValidUntil       *time.Time   `json:"validUntil,omitempty"`
+	UsageLimit       *int32       `json:"usageLimit,omitempty"`
+	PerCustomerLimit *int32       `json:"perCustomerLimit,omitempty"`
+	ServiceTypes     []string     `json:"serviceTypes"`
+	IsActive         bool         `json:"isActive"`
+	Redemptions      int32        `json:"redemptions"`
+	CreatedBy        string       `json:"createdBy"`
+	CreatedAt        time.Time    `json:"createdAt"`
+	UpdatedAt        time.Time    `json:"updatedAt"`
+}
+
+// PromotionRequest creates or replaces a promotion. ValidFrom defaults to now and IsActive to true.
+type PromotionRequest struct {
+	ID               string       `json:"-"`
+	Actor            string       `json:"-"`
+	Code             string       `json:"code" binding:"required"`
+	Description      string       `json:"description"`
+	DiscountType     DiscountType `json:"discountType" binding:"required"`
+	PercentOff       *int32       `json:"percentOff"`
+	AmountOff        *Money       `json:"amountOff"`
+	MinSpend         Money        `json:"minSpend"`
+	ValidFrom        *time.Time   `json:"validFrom"`
+	ValidUntil       *time.Time   `json:"validUntil"`
+	UsageLimit       *int32       `json:"usageLimit"`
+	PerCustomerLimit *int32       `json:"perCustomerLimit"`
+	ServiceTypes     []string     `json:"serviceTypes"`
+	IsActive         *bool        `json:"isActive"`
+}
+
+type TaxStatus string
+
+const (
+	TaxVatable   TaxStatus = "VATABLE"
+	TaxExempt    TaxStatus = "EXEMPT"
+	TaxZeroRated TaxStatus = "ZERO_RATED"
+)
+
+// TaxPolicy is the VAT charged on quotes. RateBasisPoints is in hundredths of a percent (1200 = 12%);
+// PricesIncludeTax says whether catalog prices already contain it.
+type TaxPolicy struct {
+	RateBasisPoints  int64 `json:"rateBasisPoints"`
+	PricesIncludeTax bool  `json:"pricesIncludeTax"`
+}
+
+// TaxBreakdown splits a price into net, tax and gross for a customer's tax status.
+// RateBasisPoints is the rate applied, 0 for exempt and zero-rated customers.
+type TaxBreakdown struct {
+	Status           TaxStatus `json:"status"`
+	RateBasisPoints  int32     `json:"rateBasisPoints"`
+	PricesIncludeTax bool      `json:"pricesIncludeTax"`
+	Net              Money     `json:"net"`
+	Tax              Money     `json:"tax"`
+	Gross            Money     `json:"gross"`
+}
+
+// TravelBand covers addresses up to MaxKm from a depot, beyond the previous band. They pay Fee plus
+// PerKm for every started km past the previous band's MaxKm.
+type TravelBand struct {
+	MaxKm float64 `json:"maxKm"`
+	Fee   Money   `json:"fee"`
+	PerKm Money   `json:"perKm"`
+}
+
+// Depot is a location crews travel from. Its last band is the edge of its coverage.
+type Depot struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Lat       float64      `json:"lat"`
+	Lng       float64      `json:"lng"`
+	Bands     []TravelBand `json:"bands"`
+	IsActive  bool         `json:"isActive"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// DepotRequest creates or replaces a depot. IsActive defaults to true.
+type DepotRequest struct {
+	ID       string       `json:"-"`
+	Name     string       `json:"name" binding:"required"`
+	Lat      *float64     `json:"lat" binding:"required"`
+	Lng      *float64     `json:"lng" binding:"required"`
+	Bands    []TravelBand `json:"bands" binding:"required"`
+	IsActive *bool        `json:"isActive"`
+}
+
+// TravelQuote is the depot serving a quoted address and the travel fee charged for it.
+type TravelQuote struct {
+	DepotID        string `json:"depotId"`
+	DepotName      string `json:"depotName"`
+	DistanceMeters int32  `json:"distanceMeters"`
+	Fee            Money  `json:"fee"`
+}
+
+// InvoiceStatus is the state of an invoice. Only ISSUED invoices can be paid or voided.
+type InvoiceStatus string
+
+const (
+	InvoiceIssued InvoiceStatus = "ISSUED"
+	InvoicePaid   InvoiceStatus = "PAID"
+	InvoiceVoid   InvoiceStatus = "VOID"
+)
+
+// InvoiceLineKind is SERVICE or ADDON for the booked services, otherwise the AdjustmentKind of the
+// quote line it comes from.
+type InvoiceLineKind string
+
+const (
+	InvoiceLineService InvoiceLineKind = "SERVICE"
+	InvoiceLineAddon   InvoiceLineKind = "ADDON"
+)
+
+// InvoicePolicy is the series new invoices and credit notes are numbered in and how long invoices
+// are due after issue.
+type InvoicePolicy struct {
+	Series           string
+	CreditNoteSeries string
+	DueIn            time.Duration
+}
+
+type InvoiceLine struct {
+	Kind        InvoiceLineKind `json:"kind"`
+	Description string          `json:"description"`
+	Amount      Money           `json:"amount"` // before tax, negative for discounts
+}
+
+// Invoice bills a completed booking. Number is gap-free within its series, e.g. INV-000042.
+// Subtotal is the sum of the lines; Tax splits it with the booking's tax treatment.
+type Invoice struct {
+	ID             string        `json:"id"`
+	Number         string        `json:"number"`
+	Series         string        `json:"series"`
+	BookingID      string        `json:"bookingId"`
+	CustomerID     string        `json:"customerId"`
+	CustomerName   string        `json:"customerName"`
+	BillingAddress string        `json:"billingAddress"`
+	Status         InvoiceStatus `json:"status"`
+	Lines          []InvoiceLine `json:"lines"`
+	Subtotal       Money         `json:"subtotal"`
+	Tax            TaxBreakdown  `json:"tax"`
+	IssuedAt       time.Time     `json:"issuedAt"`
+	DueAt          time.Time     `json:"dueAt"`
+	PaidAt         *time.Time    `json:"paidAt,omitempty"`
+	VoidedAt       *time.Time    `json:"voidedAt,omitempty"`
+	VoidReason     string        `json:"voidReason,omitempty"`
+	CreditNotes    []CreditNote  `json:"creditNotes"`
+}
+
+// CreditNote reduces an invoice by a refund. Number is gap-free within its series, e.g. CN-000007;
+// Tax splits Amount in the invoice's proportion of tax.
+type CreditNote struct {
+	ID            string       `json:"id"`
+	Number        string       `json:"number"`
+	InvoiceID     string       `json:"invoiceId"`
+	InvoiceNumber string       `json:"invoiceNumber"`
+	BookingID     string       `json:"bookingId"`
+	TransactionID string       `json:"transactionId"`
+	Reason        RefundReason `json:"reason"`
+	Amount        Money        `json:"amount"`
+	Net           Money        `json:"net"`
+	Tax           Money        `json:"tax"`
+	IssuedAt      time.Time    `json:"issuedAt"`
+}
+
+type VoidInvoiceRequest struct {
+	ID     string `json:"-"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// PaymentStatus is how much of a booking has been paid, stored in booking.basebookings.payment_status.
+type PaymentStatus string
+
+const (
+	PaymentUnpaid        PaymentStatus = "UNPAID"
+	PaymentPartiallyPaid PaymentStatus = "PARTIALLY_PAID"
+	PaymentPaid          PaymentStatus = "PAID"
+)
+
+type TransactionKind string
+
+const (
+	TransactionPayment TransactionKind = "PAYMENT"
+	TransactionRefund  TransactionKind = "REFUND"
+)
+
+type TransactionStatus string
+
+const (
+	TransactionPending   TransactionStatus = "PENDING"
+	TransactionSucceeded TransactionStatus = "SUCCEEDED"
+	TransactionFailed    TransactionStatus = "FAILED"
+)
+
+// Transaction is money moved for a booking through a payment provider. ProviderRef is the
+// provider's payment intent or refund id.
+type Transaction struct {
+	ID             string            `json:"id"`
+	BookingID      string            `json:"bookingId"`
+	Kind           TransactionKind   `json:"kind"`
+	Status         TransactionStatus `json:"status"`
+	Provider       string            `json:"provider"`
+	ProviderRef    *string           `json:"providerRef,omitempty"`
+	Amount         Money             `json:"amount"`
+	Method         string            `json:"method"`
+	FailureReason  string            `json:"failureReason,omitempty"`
+	IdempotencyKey *string           `json:"idempotencyKey,omitempty"`
+	RequestKey     *string           `json:"requestKey,omitempty"` // idempotency key of the refund request that made a refund
+	PaymentID      *string           `json:"paymentId,omitempty"`  // the payment a refund returns
+	Reason         *RefundReason     `json:"reason,omitempty"`
+	Note           string            `json:"note,omitempty"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+}
+
+// PayBookingRequest pays towards a booking. Amount defaults to the balance due. Retrying with the
+// same IdempotencyKey returns the first attempt instead of charging again.
+type PayBookingRequest struct {
+	BookingID      string `json:"-"`
+	Amount         *Money `json:"amount,omitempty"`
+	Method         string `json:"method" binding:"required" example:"card"`
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+}
+
+// RefundReason says why money was returned.
+type RefundReason string
+
+const (
+	RefundCancellation RefundReason = "CANCELLATION"
+	RefundComplaint    RefundReason = "COMPLAINT"
+	RefundOvercharge   RefundReason = "OVERCHARGE"
+	RefundDuplicate    RefundReason = "DUPLICATE"
+	RefundOther        RefundReason = "OTHER"
+)
+
+var RefundReasons = []RefundReason{RefundCancellation, RefundComplaint, RefundOvercharge, RefundDuplicate, RefundOther}
+
+// RefundBookingRequest returns money paid for a booking. Amount defaults to everything still
+// refundable. Retrying with the same IdempotencyKey returns the first attempt.
+type RefundBookingRequest struct {
+	BookingID      string       `json:"-"`
+	Amount         *Money       `json:"amount,omitempty"`
+	Reason         RefundReason `json:"reason" binding:"required" example:"CANCELLATION"`
+	Note           string       `json:"note,omitempty"`
+	IdempotencyKey string       `json:"idempotencyKey,omitempty"`
+}
+
+// RefundResponse lists the refund transactions of a request, one per payment it returns money
+// from, and the credit notes issued for them.
+type RefundResponse struct {
+	Refunds     []Transaction  `json:"refunds"`
+	CreditNotes []CreditNote   `json:"creditNotes"`
+	Balance     BookingBalance `json:"balance"`
+}
+
+// BookingBalance is what a booking costs, what has been paid net of refunds and what is left.
+// Before dispatch only the deposit is due; the rest of the balance is due on completion.
+type BookingBalance struct {
+	BookingID          string          `json:"bookingId"`
+	ServiceType        MainServiceType `json:"serviceType"`
+	BookingStatus      BookingStatus   `json:"bookingStatus"`
+	PaymentStatus      PaymentStatus   `json:"paymentStatus"`
+	Total              Money           `json:"total"`
+	Paid               Money           `json:"paid"`
+	Pending            Money           `json:"pending"` // payments still waiting on the provider
+	Balance            Money           `json:"balance"` // outstanding
+	Deposit            Money           `json:"deposit"` // required before dispatch, zero if none
+	DepositOutstanding Money           `json:"depositOutstanding"`
+	AmountDue          Money           `json:"amountDue"` // due now
+}
+
+// DepositRule asks for a deposit on bookings of ServiceTypes (any, when empty) whose total is at
+// least MinTotal: Fixed when set, otherwise Percent of the total, never more than the total.
+type DepositRule struct {
+	ServiceTypes []MainServiceType `json:"serviceTypes,omitempty"`
+	MinTotal     Money             `json:"minTotal"`
+	Percent      int32             `json:"percent"`
+	Fixed        Money             `json:"fixed"`
+}
+
+// DepositPolicy holds the deposit rules; the first rule matching a booking applies.
+type DepositPolicy struct {
+	Rules []DepositRule `json:"rules"`
+}
+
+type PaymentResponse struct {
+	Transaction Transaction    `json:"transaction"`
+	Balance     BookingBalance `json:"balance"`
+}
+
+// IntentStatus is the state of a payment intent at the provider.
+type IntentStatus string
+
+const (
+	IntentRequiresCapture IntentStatus = "REQUIRES_CAPTURE"
+	IntentSucceeded       IntentStatus = "SUCCEEDED"
+	IntentFailed          IntentStatus = "FAILED"
+)
+
+// PaymentIntent is a provider's record of one payment.
+type PaymentIntent struct {
+	ID            string       `json:"id"`
+	Reference     string       `json:"reference"` // our transaction id
+	Amount        Money        `json:"amount"`
+	Captured      Money        `json:"captured"`
+	Refunded      Money        `json:"refunded"`
+	Status        IntentStatus `json:"status"`
+	FailureReason string       `json:"failureReason,omitempty"`
+}
+
+// ProviderRefund is a provider's record of money returned from a captured intent.
+type ProviderRefund struct {
+	ID       string `json:"id"`
+	IntentID string `json:"intentId"`
+	Amount   Money  `json:"amount"`
+}
+
+// WebhookEventType is what a payment provider reports happened to a payment or refund.
+type WebhookEventType string
+
+const (
+	EventPaymentSucceeded WebhookEventType = "payment.succeeded"
+	EventPaymentFailed    WebhookEventType = "payment.failed"
+	EventRefundSucceeded  WebhookEventType = "refund.succeeded"
+	EventRefundFailed     WebhookEventType = "refund.failed"
+)
+
+// WebhookEvent is the body a payment provider posts to the webhook. Its transaction is found by
+// the provider's ObjectID or, before that is known to us, by Reference.
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	Data      WebhookEventData `json:"data"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+type WebhookEventData struct {
+	ObjectID      string `json:"objectId"`  // payment intent or refund id
+	Reference     string `json:"reference"` // our transaction id
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+// WebhookOutcome is what applying a webhook event did.
+type WebhookOutcome string
+
+const (
+	WebhookApplied     WebhookOutcome = "APPLIED"
+	WebhookUnchanged   WebhookOutcome = "UNCHANGED"   // the transaction was already settled that way
+	WebhookUnmatched   WebhookOutcome = "UNMATCHED"   // no transaction of ours
+	WebhookIgnored     WebhookOutcome = "IGNORED"     // an event type we do not act on
+	WebhookDiscrepancy WebhookOutcome = "DISCREPANCY" // contradicts how the transaction was settled; kept for review, not applied
+)
+
+type WebhookResponse struct {
+	EventID       string         `json:"eventId"`
+	Outcome       WebhookOutcome `json:"outcome"`
+	Duplicate     bool           `json:"duplicate"` // already received; nothing was applied this time
+	TransactionID *string        `json:"transactionId,omitempty"`
+}
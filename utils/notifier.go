@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"context"
+	"handworks-api/types"
+	"sync"
+)
+
+// Notifier delivers inventory reorder alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alert types.ReorderAlert) error
+}
+
+// LogNotifier writes alerts to the logger.
+type LogNotifier struct {
+	Logger *Logger
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, alert types.ReorderAlert) error {
+	n.Logger.Warn("inventory %s: %s has %d %s available (reorder point %d, max %d)",
+		alert.Level, alert.ItemName, alert.Available, alert.Unit, alert.ReorderPoint, alert.MaxQuantity)
+	return nil
+}
+
+// MemoryNotifier keeps alerts in memory so they can be inspected.
+type MemoryNotifier struct {
+	mu     sync.Mutex
+	alerts []types.ReorderAlert
+}
+
+func (n *MemoryNotifier) Notify(ctx context.Context, alert types.ReorderAlert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+// Alerts returns a copy of the alerts received so far.
+func (n *MemoryNotifier) Alerts() []types.ReorderAlert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]types.ReorderAlert(nil), n.alerts...)
+}
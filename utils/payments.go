@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"handworks-api/types"
+	"strings"
+	"sync"
+)
+
+// PaymentProvider collects and returns money through a payment gateway.
+type PaymentProvider interface {
+	// Name identifies the provider on recorded transactions.
+	Name() string
+	// CreateIntent starts a payment of amount with method; reference is our transaction id.
+	CreateIntent(ctx context.Context, reference string, amount types.Money, method string) (*types.PaymentIntent, error)
+	// Capture collects an intent. A declined payment returns the intent as FAILED, not an error.
+	Capture(ctx context.Context, intentID string) (*types.PaymentIntent, error)
+	// Refund returns part or all of a captured intent.
+	Refund(ctx context.Context, intentID string, amount types.Money) (*types.ProviderRefund, error)
+	FetchStatus(ctx context.Context, intentID string) (*types.PaymentIntent, error)
+}
+
+// WebhookSignatureHeader carries the signature of a webhook body.
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// SignWebhook returns the hex HMAC-SHA256 of a webhook body under secret.
+func SignWebhook(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a SignWebhook signature, optionally prefixed "sha256=", in
+// constant time. Nothing verifies without a secret.
+func VerifyWebhookSignature(secret, body []byte, signature string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// FakeDeclinedMethod is a payment method the fake provider always declines.
+const FakeDeclinedMethod = "fake_declined"
+
+// FakePaymentProvider is an in-process provider for tests and local development. Every method but
+// FakeDeclinedMethod succeeds, and ids derive from the reference (fake_pi_<reference>, then
+// fake_re_<reference>_1, _2... for its refunds). Intents live in memory and are lost on restart.
+type FakePaymentProvider struct {
+	mu      sync.Mutex
+	intents map[string]*types.PaymentIntent
+	refunds map[string]int
+}
+
+func NewFakePaymentProvider() *FakePaymentProvider {
+	return &FakePaymentProvider{intents: map[string]*types.PaymentIntent{}, refunds: map[string]int{}}
+}
+
+func (p *FakePaymentProvider) Name() string { return "fake" }
+
+func (p *FakePaymentProvider) CreateIntent(ctx context.Context, reference string, amount types.Money, method string) (*types.PaymentIntent, error) {
+	if amount.Minor <= 0 {
+		return nil, fmt.Errorf("fake provider: amount must be positive, got %s", amount)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	id := "fake_pi_" + reference
+	if intent, ok := p.intents[id]; ok {
+		copied := *intent
+		return &copied, nil
+	}
+	intent := &types.PaymentIntent{
+		ID:        id,
+		Reference: reference,
+		Amount:    amount,
+		Captured:  types.Money{Currency: amount.Currency},
+		Refunded:  types.Money{Currency: amount.Currency},
+		Status:    types.IntentRequiresCapture,
+	}
+	if method == FakeDeclinedMethod {
+		intent.Status = types.IntentFailed
+		intent.FailureReason = "card declined"
+	}
+	p.intents[intent.ID] = intent
+	copied := *intent
+	return &copied, nil
+}
+
+func (p *FakePaymentProvider) Capture(ctx context.Context, intentID string) (*types.PaymentIntent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, fmt.Errorf("fake provider: no intent %s", intentID)
+	}
+	if intent.Status == types.IntentRequiresCapture {
+		intent.Status = types.IntentSucceeded
+		intent.Captured = intent.Amount
+	}
+	copied := *intent
+	return &copied, nil
+}
+
+func (p *FakePaymentProvider) Refund(ctx context.Context, intentID string, amount types.Money) (*types.ProviderRefund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, fmt.Errorf("fake provider: no intent %s", intentID)
+	}
+	if amount.Minor <= 0 || intent.Refunded.Add(amount).Minor > intent.Captured.Minor {
+		return nil, fmt.Errorf("fake provider: cannot refund %s of %s captured, %s already refunded", amount, intent.Captured, intent.Refunded)
+	}
+	intent.Refunded = intent.Refunded.Add(amount)
+	p.refunds[intentID]++
+	return &types.ProviderRefund{
+		ID:       fmt.Sprintf("fake_re_%s_%d", intent.Reference, p.refunds[intentID]),
+		IntentID: intentID,
+		Amount:   amount,
+	}, nil
+}
+
+func (p *FakePaymentProvider) FetchStatus(ctx context.Context, intentID string) (*types.PaymentIntent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, fmt.Errorf("fake provider: no intent %s", intentID)
+	}
+	copied := *intent
+	return &copied, nil
+}
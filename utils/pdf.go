@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// A4 page size in points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font is one of the standard PDF fonts, which readers provide so nothing has to be embedded.
+type Font string
+
+const (
+	FontRegular Font = "F1" // Helvetica
+	FontBold    Font = "F2" // Helvetica-Bold
+	FontMono    Font = "F3" // Courier, every glyph 0.6 em wide
+)
+
+var fontNames = []struct {
+	font Font
+	name string
+}{
+	{FontRegular, "Helvetica"},
+	{FontBold, "Helvetica-Bold"},
+	{FontMono, "Courier"},
+}
+
+// PDF is a minimal PDF writer for text documents on A4 pages. Coordinates are in points from the
+// top left corner. Characters outside Latin-1 are written as '?'.
+type PDF struct {
+	pages []*bytes.Buffer
+}
+
+// NewPDF returns a document with one empty page.
+func NewPDF() *PDF {
+	p := &PDF{}
+	p.AddPage()
+	return p
+}
+
+// AddPage starts a new page; later drawing goes on it.
+func (p *PDF) AddPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+}
+
+func (p *PDF) page() *bytes.Buffer {
+	return p.pages[len(p.pages)-1]
+}
+
+// Text writes s with its baseline starting at x, y.
+func (p *PDF) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(p.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, escapePDFText(s))
+}
+
+// TextRight writes s in FontMono so that it ends at x.
+func (p *PDF) TextRight(x, y, size float64, s string) {
+	p.Text(x-MonoWidth(size, s), y, FontMono, size, s)
+}
+
+// MonoWidth is the width of s in FontMono.
+func MonoWidth(size float64, s string) float64 {
+	return 0.6 * size * float64(len([]rune(s)))
+}
+
+// Line draws a thin line from x1, y1 to x2, y2.
+func (p *PDF) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(p.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Bytes renders the document.
+func (p *PDF) Bytes() []byte {
+	var (
+		out     bytes.Buffer
+		offsets []int
+	)
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// objects: 1 catalog, 2 page tree, then the fonts, then a page and its contents per page
+	firstPage := 3 + len(fontNames)
+	out.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+
+	var kids, fonts bytes.Buffer
+	for i := range p.pages {
+		fmt.Fprintf(&kids, "%d 0 R ", firstPage+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids.String(), len(p.pages)))
+
+	for i, f := range fontNames {
+		fmt.Fprintf(&fonts, "/%s %d 0 R ", f.font, 3+i)
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.name))
+	}
+	for i, content := range p.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, fonts.String(), firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// escapePDFText encodes s as a Latin-1 PDF string body.
+func escapePDFText(s string) string {
+	var b bytes.Buffer
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r < 32 || r > 255:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}